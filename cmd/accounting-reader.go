@@ -112,7 +112,7 @@ func (a *accounter) Stat() accountStat {
 	var acntStat accountStat
 	a.finishOnce.Do(func() {
 		close(a.isFinished)
-		acntStat.Total = a.Total
+		acntStat.Total = atomic.LoadInt64(&a.Total)
 		acntStat.Transferred = atomic.LoadInt64(&a.current)
 		acntStat.Speed = a.write(atomic.LoadInt64(&a.current))
 	})
@@ -139,7 +139,9 @@ func (a *accounter) Get() int64 {
 	return atomic.LoadInt64(&a.current)
 }
 
-func (a *accounter) SetTotal(int64) {
+// SetTotal sets the total value atomically.
+func (a *accounter) SetTotal(total int64) {
+	atomic.StoreInt64(&a.Total, total)
 }
 
 // Add add to current value atomically.
@@ -75,6 +75,9 @@ func mainAliasList(ctx *cli.Context, deprecated bool) error {
 	console.SetColor("SecretKey", color.New(color.FgCyan))
 	console.SetColor("API", color.New(color.FgBlue))
 	console.SetColor("Path", color.New(color.FgCyan))
+	console.SetColor("Socket", color.New(color.FgCyan))
+	console.SetColor("StrictAWSNames", color.New(color.FgCyan))
+	console.SetColor("ListAPI", color.New(color.FgCyan))
 
 	alias := cleanAlias(ctx.Args().Get(0))
 
@@ -130,6 +133,10 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 				AccessKey:   v.AccessKey,
 				SecretKey:   v.SecretKey,
 				API:         v.API,
+				Socket:      v.Socket,
+				StrictNames: v.StrictAWSNames,
+				ListAPI:     v.ListAPI,
+				Flags:       v.Flags,
 			}
 
 			if deprecated {
@@ -151,6 +158,10 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 			AccessKey:   v.AccessKey,
 			SecretKey:   v.SecretKey,
 			API:         v.API,
+			Socket:      v.Socket,
+			StrictNames: v.StrictAWSNames,
+			ListAPI:     v.ListAPI,
+			Flags:       v.Flags,
 		}
 
 		if deprecated {
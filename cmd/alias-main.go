@@ -18,6 +18,9 @@
 package cmd
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
 	"github.com/minio/mc/pkg/probe"
@@ -64,13 +67,18 @@ func mainAlias(ctx *cli.Context) error {
 type aliasMessage struct {
 	op          string
 	prettyPrint bool
-	Status      string `json:"status"`
-	Alias       string `json:"alias"`
-	URL         string `json:"URL"`
-	AccessKey   string `json:"accessKey,omitempty"`
-	SecretKey   string `json:"secretKey,omitempty"`
-	API         string `json:"api,omitempty"`
-	Path        string `json:"path,omitempty"`
+	Status      string            `json:"status"`
+	Alias       string            `json:"alias"`
+	URL         string            `json:"URL"`
+	AccessKey   string            `json:"accessKey,omitempty"`
+	SecretKey   string            `json:"secretKey,omitempty"`
+	API         string            `json:"api,omitempty"`
+	Path        string            `json:"path,omitempty"`
+	Socket      string            `json:"socket,omitempty"`
+	StrictNames string            `json:"strictAwsNames,omitempty"`
+	ListAPI     string            `json:"listApi,omitempty"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	Warnings    []string          `json:"warnings,omitempty"`
 	// Deprecated field, replaced by Path
 	Lookup string `json:"lookup,omitempty"`
 }
@@ -82,26 +90,52 @@ func (h aliasMessage) String() string {
 	switch h.op {
 	case "list":
 		// Create a new pretty table with cols configuration
-		t := newPrettyRecord(2,
-			Row{"Alias", "Alias"},
-			Row{"URL", "URL"},
-			Row{"AccessKey", "AccessKey"},
-			Row{"SecretKey", "SecretKey"},
-			Row{"API", "API"},
-			Row{"Path", "Path"},
-		)
+		rows := []Row{
+			{"Alias", "Alias"},
+			{"URL", "URL"},
+			{"AccessKey", "AccessKey"},
+			{"SecretKey", "SecretKey"},
+			{"API", "API"},
+			{"Path", "Path"},
+		}
 		// Handle deprecated lookup
 		path := h.Path
 		if path == "" {
 			path = h.Lookup
 		}
-		return t.buildRecord(h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path)
+		contents := []string{h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path}
+		if h.Socket != "" {
+			rows = append(rows, Row{"Socket", "Socket"})
+			contents = append(contents, h.Socket)
+		}
+		if h.StrictNames != "" {
+			rows = append(rows, Row{"StrictAWSNames", "StrictAWSNames"})
+			contents = append(contents, h.StrictNames)
+		}
+		if h.ListAPI != "" {
+			rows = append(rows, Row{"ListAPI", "ListAPI"})
+			contents = append(contents, h.ListAPI)
+		}
+		if len(h.Flags) > 0 {
+			var flags []string
+			for name, value := range h.Flags {
+				flags = append(flags, name+"="+value)
+			}
+			sort.Strings(flags)
+			rows = append(rows, Row{"Flags", "Flags"})
+			contents = append(contents, strings.Join(flags, " "))
+		}
+		return newPrettyRecord(2, rows...).buildRecord(contents...)
 	case "remove":
 		return console.Colorize("AliasMessage", "Removed `"+h.Alias+"` successfully.")
 	case "add": // add is deprecated
 		fallthrough
 	case "set":
-		return console.Colorize("AliasMessage", "Added `"+h.Alias+"` successfully.")
+		var msg string
+		for _, warning := range h.Warnings {
+			msg += console.Colorize("AliasWarning", warning) + "\n"
+		}
+		return msg + console.Colorize("AliasMessage", "Added `"+h.Alias+"` successfully.")
 	default:
 		return ""
 	}
@@ -0,0 +1,137 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Timeout of each capability probe issued by 'alias set'.
+const aliasProbeTimeout = 10 * time.Second
+
+// probeHTTPSRedirect returns the https URL an http endpoint redirects
+// plain requests to, or an empty string if there is no such redirect.
+func probeHTTPSRedirect(ctx context.Context, hostURL, socket string) string {
+	u, e := url.Parse(hostURL)
+	if e != nil || u.Scheme != "http" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, aliasProbeTimeout)
+	defer cancel()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           getProxyFunc(http.ProxyFromEnvironment),
+			DialContext:     newSocketDialContext(socket, &net.Dialer{Timeout: aliasProbeTimeout}),
+			TLSClientConfig: &tls.Config{RootCAs: globalRootCAs, Certificates: globalClientCerts},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodGet, u.Scheme+"://"+u.Host+"/", nil)
+	if e != nil {
+		return ""
+	}
+	resp, e := client.Do(req)
+	if e != nil {
+		return ""
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return ""
+	}
+	location, e := resp.Location()
+	if e != nil || location.Scheme != "https" || location.Hostname() != u.Hostname() {
+		return ""
+	}
+	return "https://" + location.Host
+}
+
+// probeBucketResponse returns true if a request for a non existing bucket
+// was answered by the endpoint, whatever the answer was.
+func probeBucketResponse(ctx context.Context, s3Config *Config) bool {
+	s3Client, err := S3New(s3Config)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, aliasProbeTimeout)
+	defer cancel()
+
+	_, err = s3Client.Stat(ctx, StatOptions{})
+	if err == nil {
+		return true
+	}
+	if _, ok := err.ToGoError().(BucketDoesNotExist); ok {
+		return true
+	}
+	return minio.ToErrorResponse(err.ToGoError()).StatusCode != 0
+}
+
+// probePathStyle returns true if the endpoint is only reachable with path
+// style requests while the configured lookup would use virtual host style.
+func probePathStyle(ctx context.Context, s3Config *Config, path string) bool {
+	host := newClientURL(s3Config.HostURL).Host
+	if !isVirtualHostStyle(host, getLookupType(path)) {
+		return false
+	}
+
+	probeConfig := *s3Config
+	probeConfig.HostURL = urlJoinPath(s3Config.HostURL, randString(60, rand.NewSource(time.Now().UnixNano()), "probe-bucket-path-"))
+	probeConfig.Lookup = minio.BucketLookupPath
+	if !probeBucketResponse(ctx, &probeConfig) {
+		// Endpoint is not reachable at all, nothing to learn.
+		return false
+	}
+	probeConfig.Lookup = minio.BucketLookupDNS
+	return !probeBucketResponse(ctx, &probeConfig)
+}
+
+// probeMultipart returns false if the endpoint answers multipart upload
+// requests with NotImplemented.
+func probeMultipart(ctx context.Context, s3Config *Config) bool {
+	probeConfig := *s3Config
+	probeConfig.HostURL = urlJoinPath(s3Config.HostURL, randString(60, rand.NewSource(time.Now().UnixNano()), "probe-bucket-mpu-"))
+	s3Client, err := S3New(&probeConfig)
+	if err != nil {
+		return true
+	}
+	api := s3Client.(*S3Client).api
+	bucket, _ := s3Client.(*S3Client).url2BucketAndObject()
+
+	ctx, cancel := context.WithTimeout(ctx, aliasProbeTimeout)
+	defer cancel()
+	for upload := range api.ListIncompleteUploads(ctx, bucket, "", false) {
+		if upload.Err != nil {
+			return minio.ToErrorResponse(upload.Err).Code != "NotImplemented"
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestProbeHTTPSRedirect(t *testing.T) {
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := url.URL{Scheme: "https", Host: r.Host, Path: r.URL.Path}
+		http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+	}))
+	defer redirect.Close()
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer plain.Close()
+
+	redirectURL, _ := url.Parse(redirect.URL)
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{redirect.URL, "https://" + redirectURL.Host},
+		{plain.URL, ""},
+		{"https://" + redirectURL.Host, ""},
+	}
+
+	for i, testCase := range testCases {
+		if httpsURL := probeHTTPSRedirect(context.Background(), testCase.url, ""); httpsURL != testCase.expected {
+			t.Errorf("Test %d: expected `%s`, got `%s`", i+1, testCase.expected, httpsURL)
+		}
+	}
+}
+
+func TestProbeMultipart(t *testing.T) {
+	testCases := []struct {
+		code      string
+		status    int
+		multipart bool
+	}{
+		{"NoSuchBucket", http.StatusNotFound, true},
+		{"NotImplemented", http.StatusNotImplemented, false},
+	}
+
+	for i, testCase := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(testCase.status)
+			w.Write([]byte("<Error><Code>" + testCase.code + "</Code></Error>"))
+		}))
+
+		s3Config := &Config{
+			HostURL:   server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			Signature: "S3v4",
+		}
+		if multipart := probeMultipart(context.Background(), s3Config); multipart != testCase.multipart {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.multipart, multipart)
+		}
+		server.Close()
+	}
+}
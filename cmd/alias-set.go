@@ -46,6 +46,22 @@ var aliasSetFlags = []cli.Flag{
 		Name:  "api",
 		Usage: "API signature. Valid options are '[S3v4, S3v2]'",
 	},
+	cli.StringFlag{
+		Name:  "strict-aws-names",
+		Usage: "validate bucket names with AWS rules, ignored for AWS endpoints. Valid options are '[on, off]'",
+	},
+	cli.StringFlag{
+		Name:  "list-api",
+		Usage: "object listing API, ListObjectsV2 falls back to V1 if unset. Valid options are '[v1, v2]'",
+	},
+	cli.StringFlag{
+		Name:  "socket",
+		Usage: "connect through the unix domain socket at this path instead of the URL host",
+	},
+	cli.BoolFlag{
+		Name:  "probe",
+		Usage: "probe the HTTPS redirects, path style and multipart upload support of the server",
+	},
 }
 
 var aliasSetCmd = cli.Command{
@@ -96,6 +112,33 @@ EXAMPLES:
      {{.Prompt}} echo -e "BKIKJAA5BMMU2RHO6IBB\nV8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12" | \
                  {{.HelpName}} mys3 https://s3.amazonaws.com --api "s3v4" --path "off"
      {{.EnableHistory}}
+
+  6. Add a MinIO sidecar listening on a unix domain socket under "local" alias. For security reasons
+     turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} local http://localhost minio minio123 --socket /var/run/minio/minio.sock
+     {{.EnableHistory}}
+
+  7. Add a S3 compatible server accepting bucket names outside of the AWS rules, such as "My_Bucket",
+     under "private" alias. For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} private https://s3.example.com minio minio123 --strict-aws-names off
+     {{.EnableHistory}}
+
+  8. Add an older S3 compatible server only listing objects with the V1 API under "legacy" alias.
+     For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} legacy https://s3.example.com minio minio123 --list-api v1
+     {{.EnableHistory}}
+
+  9. Add Amazon S3 storage service under "public" alias for anonymous access to public buckets.
+     {{.Prompt}} {{.HelpName}} public https://s3.amazonaws.com "" ""
+
+  10. Add a S3 compatible server under "gateway" alias, probing which features it supports.
+      For security reasons turn off bash history momentarily.
+      {{.DisableHistory}}
+      {{.Prompt}} {{.HelpName}} gateway http://10.0.0.5:9000 minio minio123 --probe
+      {{.EnableHistory}}
 `,
 }
 
@@ -148,6 +191,20 @@ func checkAliasSetSyntax(ctx *cli.Context, accessKey string, secretKey string, d
 				"Unrecognized path value. Valid options are `[auto, on, off]`.")
 		}
 	}
+
+	switch strings.ToLower(ctx.String("strict-aws-names")) {
+	case "", "on", "off":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("strict-aws-names")),
+			"Unrecognized strict-aws-names value. Valid options are `[on, off]`.")
+	}
+
+	switch strings.ToLower(ctx.String("list-api")) {
+	case "", "v1", "v2":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("list-api")),
+			"Unrecognized list-api value. Valid options are `[v1, v2]`.")
+	}
 }
 
 // setAlias - set an alias config.
@@ -173,7 +230,7 @@ func setAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
 
 // probeS3Signature - auto probe S3 server signature: issue a Stat call
 // using v4 signature then v2 in case of failure.
-func probeS3Signature(ctx context.Context, accessKey, secretKey, url string) (string, *probe.Error) {
+func probeS3Signature(ctx context.Context, accessKey, secretKey, url, socket string) (string, *probe.Error) {
 	probeBucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "probe-bucket-sign-")
 	// Test s3 connection for API auto probe
 	s3Config := &Config{
@@ -183,6 +240,7 @@ func probeS3Signature(ctx context.Context, accessKey, secretKey, url string) (st
 		SecretKey: secretKey,
 		HostURL:   urlJoinPath(url, probeBucketName),
 		Debug:     globalDebug,
+		Socket:    socket,
 	}
 
 	probeSignatureType := func(stype string) (string, *probe.Error) {
@@ -223,12 +281,13 @@ func probeS3Signature(ctx context.Context, accessKey, secretKey, url string) (st
 
 // BuildS3Config constructs an S3 Config and does
 // signature auto-probe when needed.
-func BuildS3Config(ctx context.Context, url, accessKey, secretKey, api, path string) (*Config, *probe.Error) {
+func BuildS3Config(ctx context.Context, url, accessKey, secretKey, api, path, socket string) (*Config, *probe.Error) {
 	s3Config := NewS3Config(url, &aliasConfigV10{
 		AccessKey: accessKey,
 		SecretKey: secretKey,
 		URL:       url,
 		Path:      path,
+		Socket:    socket,
 	})
 
 	// If api is provided we do not auto probe signature, this is
@@ -238,7 +297,7 @@ func BuildS3Config(ctx context.Context, url, accessKey, secretKey, api, path str
 		return s3Config, nil
 	}
 	// Probe S3 signature version
-	api, err := probeS3Signature(ctx, accessKey, secretKey, url)
+	api, err := probeS3Signature(ctx, accessKey, secretKey, url, socket)
 	if err != nil {
 		return nil, err.Trace(url, accessKey, secretKey, api, path)
 	}
@@ -285,8 +344,59 @@ func fetchAliasKeys(args cli.Args) (string, string) {
 	return accessKey, secretKey
 }
 
+// probeS3Alias returns the alias configuration of the endpoint, with
+// --probe its capabilities are probed and recorded, with warnings to show.
+func probeS3Alias(ctx context.Context, cli *cli.Context, url, accessKey, secretKey, api, path string) (aliasConfigV10, []string) {
+	isProbe := cli.Bool("probe")
+
+	var warnings []string
+	if isProbe {
+		if httpsURL := probeHTTPSRedirect(ctx, url, cli.String("socket")); httpsURL != "" {
+			warnings = append(warnings, "`"+url+"` redirects to HTTPS, consider using `"+httpsURL+"` instead.")
+		}
+	}
+
+	s3Config, err := BuildS3Config(ctx, url, accessKey, secretKey, api, path, cli.String("socket"))
+	fatalIf(err.Trace(cli.Args()...), "Unable to initialize new alias from the provided credentials.")
+
+	if isProbe && probePathStyle(ctx, s3Config, path) {
+		if path == "auto" {
+			path = "on"
+			warnings = append(warnings, "Virtual host style requests do not reach `"+url+"`, using path style requests.")
+		} else {
+			warnings = append(warnings, "Virtual host style requests do not reach `"+url+"`, consider `--path on`.")
+		}
+	}
+
+	// Strict validation is the default, only record when it is turned off.
+	var strictAWSNames string
+	if strings.EqualFold(cli.String("strict-aws-names"), "off") {
+		strictAWSNames = "off"
+	}
+
+	var flags map[string]string
+	if isProbe && !probeMultipart(ctx, s3Config) {
+		flags = map[string]string{"disable-multipart": "true"}
+		warnings = append(warnings, "`"+url+"` does not support multipart uploads, disabling them for this alias.")
+	}
+
+	return aliasConfigV10{
+		URL:       s3Config.HostURL,
+		AccessKey: s3Config.AccessKey,
+		SecretKey: s3Config.SecretKey,
+		API:       s3Config.Signature,
+		Path:      path,
+		Socket:    s3Config.Socket,
+		Flags:     flags,
+
+		StrictAWSNames: strictAWSNames,
+		ListAPI:        strings.ToLower(cli.String("list-api")),
+	}, warnings
+}
+
 func mainAliasSet(cli *cli.Context, deprecated bool) error {
 	console.SetColor("AliasMessage", color.New(color.FgGreen))
+	console.SetColor("AliasWarning", color.New(color.FgYellow))
 	var (
 		args  = cli.Args()
 		alias = cleanAlias(args.Get(0))
@@ -315,17 +425,29 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 	ctx, cancelAliasAdd := context.WithCancel(globalContext)
 	defer cancelAliasAdd()
 
-	s3Config, err := BuildS3Config(ctx, url, accessKey, secretKey, api, path)
-	fatalIf(err.Trace(cli.Args()...), "Unable to initialize new alias from the provided credentials.")
+	var (
+		hostCfg  aliasConfigV10
+		warnings []string
+	)
+	if isBackendPluginScheme(newClientURL(url).Scheme) {
+		// Plugin backends are not S3 endpoints, record them as given.
+		if api == "" {
+			api = "S3v4"
+		}
+		hostCfg = aliasConfigV10{
+			URL:       url,
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			API:       api,
+			Path:      path,
+		}
+	} else {
+		hostCfg, warnings = probeS3Alias(ctx, cli, url, accessKey, secretKey, api, path)
+	}
 
-	msg := setAlias(alias, aliasConfigV10{
-		URL:       s3Config.HostURL,
-		AccessKey: s3Config.AccessKey,
-		SecretKey: s3Config.SecretKey,
-		API:       s3Config.Signature,
-		Path:      path,
-	}) // Add an alias with specified credentials.
+	msg := setAlias(alias, hostCfg) // Add an alias with specified credentials.
 
+	msg.Warnings = warnings
 	msg.op = "set"
 	if deprecated {
 		msg.op = "add"
@@ -240,6 +240,8 @@ var completeCmds = map[string]complete.Predictor{
 	"/diff":      complete.PredictOr(s3Completer, fsCompleter),
 	"/find":      complete.PredictOr(s3Completer, fsCompleter),
 	"/mirror":    complete.PredictOr(s3Completer, fsCompleter),
+	"/sync":      complete.PredictOr(s3Completer, fsCompleter),
+	"/flush":     nil,
 	"/pipe":      complete.PredictOr(s3Completer, fsCompleter),
 	"/stat":      complete.PredictOr(s3Completer, fsCompleter),
 	"/watch":     complete.PredictOr(s3Completer, fsCompleter),
@@ -275,6 +277,9 @@ var completeCmds = map[string]complete.Predictor{
 	"/replicate/status": s3Complete{deepLevel: 2},
 	"/replicate/resync": s3Complete{deepLevel: 2},
 
+	"/manifest/create": complete.PredictOr(s3Completer, fsCompleter),
+	"/manifest/verify": complete.PredictOr(complete.PredictFiles("*"), s3Completer, fsCompleter),
+
 	"/tag/list":   s3Completer,
 	"/tag/remove": s3Completer,
 	"/tag/set":    s3Completer,
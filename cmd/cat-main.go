@@ -245,6 +245,8 @@ func catOut(r io.Reader, size int64) *probe.Error {
 
 // mainCat is the main entry point for cat command.
 func mainCat(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelCat := context.WithCancel(globalContext)
 	defer cancelCat()
 
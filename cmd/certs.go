@@ -18,11 +18,18 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/certs"
+	"github.com/minio/pkg/console"
 )
 
 // getCertsDir - return the full path of certs dir
@@ -105,3 +112,48 @@ func loadRootCAs() {
 		fatalIf(probe.NewError(e), "Unable to load certificates.")
 	}
 }
+
+// loadCABundle adds the CA certificates of a PEM file to globalRootCAs.
+func loadCABundle(file string) error {
+	pemCerts, e := ioutil.ReadFile(file)
+	if e != nil {
+		return e
+	}
+	if globalRootCAs == nil {
+		if globalRootCAs, e = x509.SystemCertPool(); e != nil {
+			globalRootCAs = x509.NewCertPool()
+		}
+	}
+	if !globalRootCAs.AppendCertsFromPEM(pemCerts) {
+		return errors.New("no PEM certificates found")
+	}
+	return nil
+}
+
+// loadClientCert loads the certificate presented to endpoints requiring
+// TLS client authentication into globalClientCerts.
+func loadClientCert(certFile, keyFile string) error {
+	if certFile == "" && keyFile == "" {
+		return nil
+	}
+	if certFile == "" || keyFile == "" {
+		return errors.New("--client-cert and --client-key must be set together")
+	}
+	cert, e := tls.LoadX509KeyPair(certFile, keyFile)
+	if e != nil {
+		return fmt.Errorf("Unable to load --client-cert `%s`: %v", certFile, e)
+	}
+	globalClientCerts = []tls.Certificate{cert}
+	return nil
+}
+
+// insecureHosts - hosts already warned about skipping verification.
+var insecureHosts sync.Map
+
+// warnInsecure warns once per host that its TLS certificate is not verified.
+func warnInsecure(host string) {
+	if _, warned := insecureHosts.LoadOrStore(host, true); warned || globalQuiet || globalJSON {
+		return
+	}
+	console.Errorf("Skipping TLS certificate verification of `%s` with --insecure, connections can be intercepted.\n", host)
+}
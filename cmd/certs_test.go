@@ -0,0 +1,146 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// writeTestCert writes a self-signed client certificate and its key
+// as PEM files in dir.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, e := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if cert, e = x509.ParseCertificate(der); e != nil {
+		t.Fatal(e)
+	}
+	keyDER, e := x509.MarshalECPrivateKey(key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	if e = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	return certFile, keyFile, cert
+}
+
+func TestClientCertificate(t *testing.T) {
+	defer func(rootCAs *x509.CertPool, clientCerts []tls.Certificate) {
+		globalRootCAs, globalClientCerts = rootCAs, clientCerts
+	}(globalRootCAs, globalClientCerts)
+
+	dir, e := ioutil.TempDir("", "certs-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, cert := writeTestCert(t, dir)
+
+	// The server only accepts the client certificate, and is trusted
+	// through the CA bundle.
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<ListAllMyBucketsResult><Buckets><Bucket><Name>bucket</Name></Bucket></Buckets></ListAllMyBucketsResult>"))
+	}))
+	server.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert}
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	bundle := filepath.Join(dir, "ca.pem")
+	if e = ioutil.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); e != nil {
+		t.Fatal(e)
+	}
+
+	if e = loadClientCert(certFile, ""); e == nil {
+		t.Fatal("expected an error without --client-key")
+	}
+	if e = loadCABundle(keyFile); e == nil {
+		t.Fatal("expected an error for a bundle without certificates")
+	}
+	if e = loadCABundle(bundle); e != nil {
+		t.Fatal(e)
+	}
+
+	// Failed handshakes are not retried, clients are cached by
+	// credentials so new ones are used for each attempt.
+	setRetry()
+	defer func(maxRetry int) { minio.MaxRetry = maxRetry }(minio.MaxRetry)
+	minio.MaxRetry = 1
+	listBuckets := func(accessKey string) error {
+		conf := new(Config)
+		conf.HostURL = server.URL
+		conf.AccessKey = accessKey
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		s3c, err := S3New(conf)
+		if err != nil {
+			return err.ToGoError()
+		}
+		_, e := s3c.(*S3Client).api.ListBuckets(context.Background())
+		return e
+	}
+	if e = listBuckets("WLGDGYAQYIGI833EV05A"); e == nil {
+		t.Fatal("expected the server to require a client certificate")
+	}
+	if e = loadClientCert(certFile, keyFile); e != nil {
+		t.Fatal(e)
+	}
+	if e = listBuckets("WLGDGYAQYIGI833EV05B"); e != nil {
+		t.Fatal(e)
+	}
+}
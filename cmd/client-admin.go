@@ -57,7 +57,7 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.Socket))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -81,22 +81,28 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 
 			// Keep TLS config.
 			tlsConfig := &tls.Config{
-				RootCAs: globalRootCAs,
+				RootCAs:      globalRootCAs,
+				Certificates: globalClientCerts,
 				// Can't use SSLv3 because of POODLE and BEAST
 				// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 				// Can't use TLSv1.1 because of RC4 cipher usage
-				MinVersion: tls.VersionTLS12,
+				MinVersion:       tls.VersionTLS12,
+				CipherSuites:     tlsCipherSuites(),
+				CurvePreferences: tlsCurvePreferences(),
 			}
 			if config.Insecure {
 				tlsConfig.InsecureSkipVerify = true
+				if useTLS {
+					warnInsecure(hostName)
+				}
 			}
 
 			var transport http.RoundTripper = &http.Transport{
-				Proxy: ieproxy.GetProxyFunc(),
-				DialContext: (&net.Dialer{
+				Proxy: getProxyFunc(ieproxy.GetProxyFunc()),
+				DialContext: newSocketDialContext(config.Socket, &net.Dialer{
 					Timeout:   10 * time.Second,
 					KeepAlive: 15 * time.Second,
-				}).DialContext,
+				}),
 				MaxIdleConnsPerHost:   256,
 				IdleConnTimeout:       90 * time.Second,
 				TLSHandshakeTimeout:   10 * time.Second,
@@ -20,6 +20,8 @@ package cmd
 import (
 	"fmt"
 	"time"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 /// Collection of standard errors
@@ -129,6 +131,20 @@ func (e PathIsNotRegular) Error() string {
 	return "Requested file `" + e.Path + "` is not a regular file."
 }
 
+// PathIsSpecial - file is a FIFO, socket or device.
+type PathIsSpecial GenericFileError
+
+func (e PathIsSpecial) Error() string {
+	return "Requested file `" + e.Path + "` is a FIFO, socket or device."
+}
+
+// PathIsSymlink - file is a symbolic link copied as a link.
+type PathIsSymlink GenericFileError
+
+func (e PathIsSymlink) Error() string {
+	return "Requested file `" + e.Path + "` is a symbolic link, links are only copied to local folders."
+}
+
 // PathInsufficientPermission (EPERM) - permission denied.
 type PathInsufficientPermission GenericFileError
 
@@ -150,6 +166,32 @@ func (e TooManyLevelsSymlink) Error() string {
 	return "Requested file `" + e.Path + "` has too many levels of symlinks"
 }
 
+// PathNameReserved (EINVAL) - file name is reserved by the OS.
+type PathNameReserved GenericFileError
+
+func (e PathNameReserved) Error() string {
+	return "Requested file `" + e.Path + "` uses a name reserved by the operating system"
+}
+
+// PathBusy (EWOULDBLOCK) - file is being written by another process.
+type PathBusy GenericFileError
+
+func (e PathBusy) Error() string {
+	return "Requested file `" + e.Path + "` is being written by another process"
+}
+
+// InsufficientSpace (ENOSPC) - not enough free space to write the file.
+type InsufficientSpace struct {
+	Path      string
+	Size      int64
+	Available uint64
+}
+
+func (e InsufficientSpace) Error() string {
+	return "Not enough space to write `" + e.Path + "`, " + humanize.IBytes(uint64(e.Size)) +
+		" needed but only " + humanize.IBytes(e.Available) + " available"
+}
+
 // EmptyPath (EINVAL) - invalid argument.
 type EmptyPath struct{}
 
@@ -169,6 +211,13 @@ func (e ObjectMissing) Error() string {
 	return "Object does not exist"
 }
 
+// ObjectNotModified - object matches the preconditions of a conditional GET.
+type ObjectNotModified struct{}
+
+func (e ObjectNotModified) Error() string {
+	return "Object not modified"
+}
+
 // ObjectIsDeleteMarker - object is a delete marker as latest
 type ObjectIsDeleteMarker struct{}
 
@@ -187,6 +236,17 @@ func (e UnexpectedShortWrite) Error() string {
 	return msg
 }
 
+// ETagMismatch - ETag returned for an upload does not match the data sent.
+type ETagMismatch struct {
+	Object   string
+	Expected string
+	ETag     string
+}
+
+func (e ETagMismatch) Error() string {
+	return "Integrity check failed for `" + e.Object + "`, expected ETag `" + e.Expected + "` but server returned `" + e.ETag + "`."
+}
+
 // UnexpectedEOF (EPIPE) - reader closed prematurely.
 type UnexpectedEOF struct {
 	TotalSize    int64
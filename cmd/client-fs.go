@@ -22,6 +22,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -29,6 +31,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -57,14 +60,94 @@ const (
 	slashSeperator   = "/"
 	metadataKey      = "X-Amz-Meta-Mc-Attrs"
 	metadataKeyS3Cmd = "X-Amz-Meta-S3cmd-Attrs"
+
+	// user metadata is persisted as extended attributes with this prefix.
+	userMetadataPrefix      = "X-Amz-Meta-"
+	userMetadataXattrPrefix = "user.mc.meta."
+
+	// type of the special files copied with --special-files=metadata.
+	metadataKeyFileType = "X-Amz-Meta-Mc-File-Type"
 )
 
+// Symlink policies of the filesystem listings, set with --symlinks.
+const (
+	// Links to files are followed, links to directories are not
+	// traversed by recursive listings.
+	symlinksDefault = ""
+	// Links to files and directories are followed, skipping loops.
+	symlinksFollow = "follow"
+	// Links are ignored.
+	symlinksSkip = "skip"
+	// Links are listed as they are and copied as links to local
+	// targets, object storage cannot represent them.
+	symlinksLink = "link"
+)
+
+// Policies for FIFOs, sockets and devices of the filesystem listings,
+// set with --special-files.
+const (
+	// Special files are skipped with a warning.
+	specialFilesSkip = "skip"
+	// Listing a special file fails.
+	specialFilesFail = "fail"
+	// Special files are listed as empty files, copies record their
+	// type in the metadata.
+	specialFilesMetadata = "metadata"
+)
+
+// skippedSpecialFiles holds the special files already warned about,
+// sources are listed more than once by some commands.
+var skippedSpecialFiles sync.Map
+
+// fileID identifies a local file by its device and inode.
+type fileID struct {
+	dev, ino uint64
+}
+
+// copiedLinks remembers the first target path of each hard linked
+// source copied with --hard-links, so that its other links are linked
+// at the target instead of being copied again.
+var copiedLinks = struct {
+	sync.Mutex
+	paths map[fileID]string
+}{paths: make(map[fileID]string)}
+
 // GOOS specific ignore list.
 var ignoreFiles = map[string][]string{
 	"darwin":  {"*.DS_Store"},
 	"default": {"lost+found"},
 }
 
+// Junk files skipped along with dotfiles by --skip-hidden.
+var hiddenFiles = []string{"Thumbs.db", "ehthumbs.db", "desktop.ini"}
+
+// sparseWriter writes to a file leaving holes where the data is
+// all zeros, the file must be truncated to its size once written.
+type sparseWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func (w *sparseWriter) Write(b []byte) (n int, e error) {
+	if isZeros(b) {
+		w.offset += int64(len(b))
+		return len(b), nil
+	}
+	n, e = w.file.WriteAt(b, w.offset)
+	w.offset += int64(n)
+	return n, e
+}
+
+// isZeros returns true if every byte of b is zero.
+func isZeros(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // fsNew - instantiate a new fs
 func fsNew(path string) (Client, *probe.Error) {
 	if strings.TrimSpace(path) == "" {
@@ -88,10 +171,57 @@ func isNotSupported(e error) bool {
 	return errno.Err == syscall.ENOTSUP || errno.Err == syscall.EOPNOTSUPP
 }
 
+// setUserMetadata stores the X-Amz-Meta-* entries of metadata as extended
+// attributes of file, filesystems without xattr support are ignored.
+func setUserMetadata(file *os.File, metadata map[string]string) error {
+	for k, v := range metadata {
+		k = http.CanonicalHeaderKey(k)
+		if !strings.HasPrefix(k, userMetadataPrefix) || k == metadataKey || k == metadataKeyS3Cmd {
+			continue
+		}
+		name := userMetadataXattrPrefix + strings.ToLower(strings.TrimPrefix(k, userMetadataPrefix))
+		if e := xattr.FSet(file, name, []byte(v)); e != nil {
+			if isNotSupported(e) {
+				return nil
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+// getUserMetadata returns the user metadata stored by setUserMetadata,
+// keys are returned without the X-Amz-Meta- prefix.
+func getUserMetadata(path string) (map[string]string, error) {
+	list, e := xattr.List(path)
+	if e != nil {
+		if isNotSupported(e) {
+			return nil, nil
+		}
+		return nil, e
+	}
+	metadata := make(map[string]string)
+	for _, name := range list {
+		if !strings.HasPrefix(name, userMetadataXattrPrefix) {
+			continue
+		}
+		value, e := xattr.Get(path, name)
+		if e != nil {
+			return nil, e
+		}
+		metadata[strings.TrimPrefix(name, userMetadataXattrPrefix)] = string(value)
+	}
+	return metadata, nil
+}
+
 // isIgnoredFile returns true if 'filename' is on the exclude list.
 func isIgnoredFile(filename string) bool {
 	matchFile := filepath.Base(filename)
 
+	if globalSkipHidden && isHiddenFile(matchFile) {
+		return true
+	}
+
 	// OS specific ignore list.
 	for _, ignoredFile := range ignoreFiles[runtime.GOOS] {
 		matched, e := filepath.Match(ignoredFile, matchFile)
@@ -117,6 +247,50 @@ func isIgnoredFile(filename string) bool {
 	return false
 }
 
+// isHiddenFile returns true for dotfiles and junk files generated by
+// the OS, skipped by --skip-hidden.
+func isHiddenFile(filename string) bool {
+	name := filepath.Base(filename)
+	if strings.HasPrefix(name, ".") && name != "." && name != ".." {
+		return true
+	}
+	for _, hiddenFile := range hiddenFiles {
+		if strings.EqualFold(name, hiddenFile) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCaseInsensitiveDir returns true if the file names of the local
+// folder, or of its closest existing parent, are case-insensitive, as on
+// the default filesystems of macOS and Windows.
+func isCaseInsensitiveDir(dir string) bool {
+	byDefault := runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+	for {
+		if st, e := os.Stat(dir); e == nil && st.IsDir() {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return byDefault
+		}
+		dir = parent
+	}
+	probeFile, e := ioutil.TempFile(dir, ".mc-case-")
+	if e != nil {
+		return byDefault
+	}
+	probeFile.Close()
+	defer os.Remove(probeFile.Name())
+	st, e := os.Stat(probeFile.Name())
+	if e != nil {
+		return byDefault
+	}
+	upper, e := os.Stat(filepath.Join(dir, strings.ToUpper(filepath.Base(probeFile.Name()))))
+	return e == nil && os.SameFile(st, upper)
+}
+
 // URL get url.
 func (f *fsClient) GetURL() ClientURL {
 	return *f.PathURL
@@ -267,6 +441,14 @@ func preserveAttributes(fd *os.File, attr map[string]string) *probe.Error {
 func (f *fsClient) put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
 	// ContentType is not handled on purpose.
 	// For filesystem this is a redundant information.
+	if hasReservedName(f.PathURL.Path) {
+		return 0, probe.NewError(PathNameReserved{Path: f.PathURL.Path})
+	}
+
+	// Devices and FIFOs, such as /dev/null or NUL, are written directly.
+	if st, e := os.Stat(f.PathURL.Path); e == nil && isSpecialFile(st.Mode()) {
+		return f.putSpecialFile(ctx, reader, size, progress)
+	}
 
 	// Extract dir name.
 	objectDir, objectName := filepath.Split(f.PathURL.Path)
@@ -289,16 +471,69 @@ func (f *fsClient) put(ctx context.Context, reader io.Reader, size int64, progre
 	// Write to a temporary file "object.part.minio" before commit.
 	objectPartPath := objectPath + partSuffix
 
-	// We cannot resume this operation, then we
-	// should remove any partial download if any.
-	defer os.Remove(objectPartPath)
-
 	tmpFile, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_WRONLY, 0o666)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return 0, err.Trace(f.PathURL.Path)
 	}
 
+	// Lock the part file until the put is committed so that concurrent
+	// writers do not interleave, file systems without locks are written
+	// unlocked.
+	unlock, e := lockFile(tmpFile)
+	if e == nil {
+		defer unlock.Close()
+		// The previous writer may have committed the part file
+		// before the lock was released.
+		if !isSameFile(tmpFile, objectPartPath) {
+			e = syscall.EWOULDBLOCK
+		}
+	}
+	if errors.Is(e, syscall.EWOULDBLOCK) {
+		tmpFile.Close()
+		return 0, probe.NewError(PathBusy{Path: f.PathURL.Path})
+	}
+
+	// We cannot resume this operation, then we
+	// should remove any partial download if any.
+	var committed bool
+	defer func() {
+		if !committed {
+			os.Remove(objectPartPath)
+		}
+	}()
+
+	if e = tmpFile.Truncate(0); e != nil {
+		tmpFile.Close()
+		return 0, probe.NewError(e)
+	}
+
+	// Local files are copied in the kernel if possible.
+	srcFile, isLocalFile := reader.(*os.File)
+	isLocalFile = isLocalFile && !opts.sparse && isRegularFile(srcFile)
+
+	// Fail early instead of writing until ENOSPC, sparse files
+	// may need less space than their size and copies in the kernel
+	// may share the data of their source.
+	if size > 0 && !opts.sparse && !isLocalFile {
+		if free, e := disk.GetFreeSpace(objectPartPath); e == nil && free < uint64(size) {
+			tmpFile.Close()
+			return 0, probe.NewError(InsufficientSpace{Path: f.PathURL.Path, Size: size, Available: free})
+		}
+	}
+	// Allocate the whole file upfront, reducing fragmentation. Copies
+	// in the kernel allocate the file themselves and may share data.
+	if size > 0 && !opts.sparse && !isLocalFile {
+		if e = preallocate(tmpFile, size); e != nil {
+			tmpFile.Close()
+			if errors.Is(e, syscall.ENOSPC) {
+				free, _ := disk.GetFreeSpace(objectPartPath)
+				return 0, probe.NewError(InsufficientSpace{Path: f.PathURL.Path, Size: size, Available: free})
+			}
+			return 0, probe.NewError(e)
+		}
+	}
+
 	attr := make(map[string]string)
 	if _, ok := opts.metadata[metadataKey]; ok && opts.isPreserve {
 		attr, e = parseAttribute(opts.metadata)
@@ -312,10 +547,40 @@ func (f *fsClient) put(ctx context.Context, reader io.Reader, size int64, progre
 		}
 	}
 
-	totalWritten, e := io.Copy(tmpFile, hookreader.NewHook(reader, progress))
-	if e != nil {
-		tmpFile.Close()
-		return 0, probe.NewError(e)
+	if e = setUserMetadata(tmpFile, opts.metadata); e != nil {
+		console.Println(console.Colorize("Error", fmt.Sprintf("unable to store user metadata, continuing to copy the content %s\n", e)))
+	}
+
+	source := reader
+	if ctx.Done() != nil {
+		source = contextReader{ctx: ctx, reader: reader}
+	}
+	var totalWritten int64
+	var copied bool
+	if isLocalFile {
+		totalWritten, copied, e = copyFileOffload(ctx, tmpFile, srcFile, progress)
+		if e != nil {
+			tmpFile.Close()
+			return totalWritten, probe.NewError(e)
+		}
+	}
+	if !copied {
+		var writer io.Writer = tmpFile
+		if opts.sparse {
+			writer = &sparseWriter{file: tmpFile}
+		}
+		totalWritten, e = io.Copy(writer, hookreader.NewHook(source, progress))
+		if e != nil {
+			tmpFile.Close()
+			return 0, probe.NewError(e)
+		}
+	}
+	// Extend the file over a trailing hole.
+	if opts.sparse {
+		if e = tmpFile.Truncate(totalWritten); e != nil {
+			tmpFile.Close()
+			return totalWritten, probe.NewError(e)
+		}
 	}
 
 	// Close the input reader as well, if possible.
@@ -357,6 +622,7 @@ func (f *fsClient) put(ctx context.Context, reader io.Reader, size int64, progre
 		err := f.toClientError(e, objectPath)
 		return totalWritten, err.Trace(objectPartPath, objectPath)
 	}
+	committed = true
 
 	if len(attr) != 0 && opts.isPreserve {
 		atime, mtime, err := parseAtimeMtime(attr)
@@ -373,6 +639,106 @@ func (f *fsClient) put(ctx context.Context, reader io.Reader, size int64, progre
 	return totalWritten, nil
 }
 
+// isRegularFile returns true if the opened file is a regular file.
+func isRegularFile(file *os.File) bool {
+	st, e := file.Stat()
+	return e == nil && st.Mode().IsRegular()
+}
+
+// copyFileOffloadSize is the size of the ranges copied in the kernel
+// between progress updates.
+const copyFileOffloadSize = 8 * 1024 * 1024
+
+// putSpecialFile writes to an existing device or FIFO, without a part file.
+func (f *fsClient) putSpecialFile(ctx context.Context, reader io.Reader, size int64, progress io.Reader) (int64, *probe.Error) {
+	file, e := os.OpenFile(f.PathURL.Path, os.O_WRONLY, 0)
+	if e != nil {
+		err := f.toClientError(e, f.PathURL.Path)
+		return 0, err.Trace(f.PathURL.Path)
+	}
+	defer file.Close()
+
+	source := reader
+	if ctx.Done() != nil {
+		source = contextReader{ctx: ctx, reader: reader}
+	}
+	totalWritten, e := io.Copy(file, hookreader.NewHook(source, progress))
+	if closer, ok := reader.(io.Closer); ok {
+		closer.Close()
+	}
+	if e != nil {
+		return totalWritten, probe.NewError(e)
+	}
+	if size > 0 && totalWritten < size {
+		return totalWritten, probe.NewError(UnexpectedEOF{
+			TotalSize:    size,
+			TotalWritten: totalWritten,
+		})
+	}
+	if e = file.Close(); e != nil {
+		return totalWritten, probe.NewError(e)
+	}
+	return totalWritten, nil
+}
+
+// copyFileOffload copies src from its offset to dst in the kernel, the
+// data is shared by filesystems supporting reflinks. It returns false,
+// with nothing written, if the kernel cannot copy between the files.
+func copyFileOffload(ctx context.Context, dst, src *os.File, progress io.Reader) (int64, bool, error) {
+	advance := func(n int64) {
+		if progress != nil {
+			io.CopyN(io.Discard, progress, n)
+		}
+	}
+	if offset, e := src.Seek(0, io.SeekCurrent); e == nil && offset == 0 {
+		if st, e := src.Stat(); e == nil && cloneFile(dst, src) == nil {
+			advance(st.Size())
+			return st.Size(), true, nil
+		}
+	}
+
+	var written int64
+	for {
+		if e := ctx.Err(); e != nil {
+			return written, true, e
+		}
+		n, e := copyFileRange(dst, src, copyFileOffloadSize)
+		if e != nil {
+			if written == 0 && isCopyOffloadUnsupported(e) {
+				return 0, false, nil
+			}
+			return written, true, e
+		}
+		if n == 0 {
+			return written, true, nil
+		}
+		written += n
+		advance(n)
+	}
+}
+
+// isCopyOffloadUnsupported returns true if the kernel cannot copy the
+// files, because of the OS, the filesystems or the type of the files.
+func isCopyOffloadUnsupported(e error) bool {
+	return errors.Is(e, syscall.ENOSYS) || errors.Is(e, syscall.EXDEV) ||
+		errors.Is(e, syscall.EOPNOTSUPP) || errors.Is(e, syscall.EINVAL) ||
+		errors.Is(e, syscall.EBADF)
+}
+
+// contextReader fails reads once ctx is cancelled, so that long copies
+// to the filesystem can be aborted.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.reader.Read(p)
+}
+
 // Put - create a new file with metadata.
 func (f *fsClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
 	return f.put(ctx, reader, size, progress, opts)
@@ -387,16 +753,54 @@ func (f *fsClient) ShareDownload(ctx context.Context, versionID string, expires
 }
 
 // ShareUpload - share upload not implemented for filesystem.
-func (f *fsClient) ShareUpload(ctx context.Context, startsWith bool, expires time.Duration, contentType string) (string, map[string]string, *probe.Error) {
+func (f *fsClient) ShareUpload(ctx context.Context, startsWith bool, expires time.Duration, contentType string, minSize, maxSize int64) (string, map[string]string, *probe.Error) {
 	return "", nil, probe.NewError(APINotImplemented{
 		API:     "ShareUpload",
 		APIType: "filesystem",
 	})
 }
 
+// Presign - presigned URLs not implemented for filesystem.
+func (f *fsClient) Presign(ctx context.Context, method string, expires time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "Presign",
+		APIType: "filesystem",
+	})
+}
+
 // Copy - copy data from source to destination
 func (f *fsClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
-	rc, e := os.Open(source)
+	if globalSymlinks == symlinksLink {
+		if st, e := os.Lstat(source); e == nil && st.Mode()&os.ModeSymlink != 0 {
+			if err := copySymlink(source, f.PathURL.Path); err != nil {
+				return err.Trace(f.PathURL.Path, source)
+			}
+			if progress != nil {
+				io.CopyN(io.Discard, progress, opts.size)
+			}
+			return nil
+		}
+	}
+	if st, e := os.Stat(source); e == nil && isSpecialFile(st.Mode()) {
+		rc, err := openSpecialFile(source)
+		if err != nil {
+			return err.Trace(source)
+		}
+		metadata := make(map[string]string)
+		if globalSpecialFiles == specialFilesMetadata {
+			metadata[metadataKeyFileType] = specialFileType(st.Mode())
+		}
+		for k, v := range opts.metadata {
+			metadata[k] = v
+		}
+		putOpts := PutOptions{metadata: metadata, isPreserve: opts.isPreserve}
+		if _, err = f.put(ctx, rc, 0, progress, putOpts); err != nil {
+			return err.Trace(f.PathURL.Path, source)
+		}
+		return nil
+	}
+
+	rc, e := openSourceFile(source)
 	if e != nil {
 		err := f.toClientError(e, source)
 		return err.Trace(source)
@@ -407,17 +811,107 @@ func (f *fsClient) Copy(ctx context.Context, source string, opts CopyOptions, pr
 		metadata:   opts.metadata,
 		isPreserve: opts.isPreserve,
 	}
+	var linkID fileID
+	var isLinked bool
+	// Recreate the holes of sparse files instead of writing zeros.
+	if st, e := rc.Stat(); e == nil {
+		putOpts.sparse = isSparseFile(st)
+		if opts.hardLinks {
+			linkID, isLinked = hardLinkID(st)
+		}
+	}
 
 	destination := f.PathURL.Path
+	if isLinked {
+		copiedLinks.Lock()
+		linkPath, ok := copiedLinks.paths[linkID]
+		copiedLinks.Unlock()
+		if ok && linkCopied(linkPath, destination) {
+			if progress != nil {
+				io.CopyN(io.Discard, progress, opts.size)
+			}
+			return nil
+		}
+	}
+
 	if _, err := f.put(ctx, rc, opts.size, progress, putOpts); err != nil {
 		return err.Trace(destination, source)
 	}
+
+	if isLinked {
+		copiedLinks.Lock()
+		if _, ok := copiedLinks.paths[linkID]; !ok {
+			copiedLinks.paths[linkID] = destination
+		}
+		copiedLinks.Unlock()
+	}
+	return nil
+}
+
+// linkCopied replaces destination with a hard link to an already copied
+// target, it returns false if the link cannot be made, e.g. because both
+// are not on the same device, so that the caller copies the data instead.
+func linkCopied(linkPath, destination string) bool {
+	if e := os.MkdirAll(filepath.Dir(destination), 0o777); e != nil {
+		return false
+	}
+	tmpPath := destination + partSuffix
+	os.Remove(tmpPath)
+	if e := os.Link(linkPath, tmpPath); e != nil {
+		return false
+	}
+	if e := os.Rename(tmpPath, destination); e != nil {
+		os.Remove(tmpPath)
+		return false
+	}
+	return true
+}
+
+// copySymlink replaces destination with a link to the target of the
+// link source.
+func copySymlink(source, destination string) *probe.Error {
+	target, e := os.Readlink(source)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.MkdirAll(filepath.Dir(destination), 0o777); e != nil {
+		return probe.NewError(e)
+	}
+	tmpPath := destination + partSuffix
+	os.Remove(tmpPath)
+	if e = os.Symlink(target, tmpPath); e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.Rename(tmpPath, destination); e != nil {
+		os.Remove(tmpPath)
+		return probe.NewError(e)
+	}
 	return nil
 }
 
 // Get returns reader and any additional metadata.
 func (f *fsClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
-	fileData, e := os.Open(f.PathURL.Path)
+	switch globalSymlinks {
+	case symlinksSkip:
+		if st, e := os.Lstat(f.PathURL.Path); e == nil && st.Mode()&os.ModeSymlink != 0 {
+			return nil, probe.NewError(PathIsNotRegular{Path: f.PathURL.Path})
+		}
+	case symlinksLink:
+		// Links are only copied to local targets, without reading them.
+		if st, e := os.Lstat(f.PathURL.Path); e == nil && st.Mode()&os.ModeSymlink != 0 {
+			return nil, probe.NewError(PathIsSymlink{Path: f.PathURL.Path})
+		}
+	}
+	st, e := os.Stat(f.PathURL.Path)
+	if e == nil && isSpecialFile(st.Mode()) {
+		return openSpecialFile(f.PathURL.Path)
+	}
+	// Local files have no ETag, only their modification time is compared
+	// with the precision of the HTTP date.
+	if e == nil && !opts.IfModifiedSince.IsZero() && !st.ModTime().Truncate(time.Second).After(opts.IfModifiedSince) {
+		return nil, probe.NewError(ObjectNotModified{})
+	}
+	fileData, e := openSourceFile(f.PathURL.Path)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return nil, err.Trace(f.PathURL.Path)
@@ -425,6 +919,113 @@ func (f *fsClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *pr
 	return fileData, nil
 }
 
+// sourceFile is a local file opened for copying.
+type sourceFile interface {
+	io.ReadSeeker
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+// openSourceFile opens a local file for copying, bypassing the page
+// cache with --direct-io.
+func openSourceFile(fpath string) (sourceFile, error) {
+	if globalDirectIO {
+		return openDirect(fpath)
+	}
+	return os.Open(fpath)
+}
+
+// ReadAtCloser reads parts of a local file, concurrent reads of
+// different ranges are safe.
+type ReadAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// GetReaderAt - open a regular file for reads at any offset, it returns
+// the reader and the size of the file.
+func (f *fsClient) GetReaderAt(ctx context.Context) (ReadAtCloser, int64, *probe.Error) {
+	st, e := os.Lstat(f.PathURL.Path)
+	if e == nil && st.Mode()&os.ModeSymlink != 0 {
+		switch globalSymlinks {
+		case symlinksSkip:
+			return nil, 0, probe.NewError(PathIsNotRegular{Path: f.PathURL.Path})
+		case symlinksLink:
+			return nil, 0, probe.NewError(PathIsSymlink{Path: f.PathURL.Path})
+		}
+		st, e = os.Stat(f.PathURL.Path)
+	}
+	if e != nil {
+		err := f.toClientError(e, f.PathURL.Path)
+		return nil, 0, err.Trace(f.PathURL.Path)
+	}
+	if isSpecialFile(st.Mode()) {
+		return nil, 0, probe.NewError(PathIsSpecial{Path: f.PathURL.Path})
+	}
+	if !st.Mode().IsRegular() {
+		return nil, 0, probe.NewError(PathIsNotRegular{Path: f.PathURL.Path})
+	}
+	fileData, e := os.Open(f.PathURL.Path)
+	if e != nil {
+		err := f.toClientError(e, f.PathURL.Path)
+		return nil, 0, err.Trace(f.PathURL.Path)
+	}
+	return fileData, st.Size(), nil
+}
+
+// isSpecialFile returns true for FIFOs, sockets and devices, reading
+// them may block or never end.
+func isSpecialFile(mode os.FileMode) bool {
+	return mode&(os.ModeNamedPipe|os.ModeSocket|os.ModeDevice|os.ModeCharDevice) != 0
+}
+
+// specialFileType names the type of a special file.
+func specialFileType(mode os.FileMode) string {
+	switch {
+	case mode&os.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeCharDevice != 0:
+		return "char-device"
+	default:
+		return "device"
+	}
+}
+
+// openSpecialFile returns a reader of a special file, it reads as empty
+// without opening the file with --special-files=metadata. Otherwise only
+// explicitly named special files get here, listings skip them first.
+func openSpecialFile(fpath string) (io.ReadCloser, *probe.Error) {
+	if globalSpecialFiles != specialFilesMetadata {
+		file, e := os.Open(fpath)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		return file, nil
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+// listSpecialFile applies the --special-files policy to a special file
+// found by a listing, it returns nil if the file is skipped.
+func listSpecialFile(fpath string, fi os.FileInfo) *ClientContent {
+	switch globalSpecialFiles {
+	case specialFilesFail:
+		return &ClientContent{Err: probe.NewError(PathIsSpecial{Path: fpath})}
+	case specialFilesMetadata:
+		return &ClientContent{
+			URL:  *newClientURL(fpath),
+			Time: fi.ModTime(),
+			Type: fi.Mode().Perm(),
+		}
+	}
+	if _, warned := skippedSpecialFiles.LoadOrStore(fpath, true); !warned {
+		errorIf(probe.NewError(PathIsSpecial{Path: fpath}), "Skipping special file.")
+	}
+	return nil
+}
+
 // Check if the given error corresponds to ENOTEMPTY for unix
 // and ERROR_DIR_NOT_EMPTY for windows (directory not empty).
 func isSysErrNotEmpty(err error) bool {
@@ -449,8 +1050,15 @@ func isSysErrNotEmpty(err error) bool {
 // it will recursively delete empty parent directories
 // until it finds one with files in it. Returns nil for a non-empty directory.
 func deleteFile(basePath, deletePath string) error {
+	remove := os.Remove
+	// Folders left empty are deleted anyway.
+	if globalTrash {
+		if st, e := os.Lstat(deletePath); e == nil && !st.IsDir() {
+			remove = moveToTrash
+		}
+	}
 	// Attempt to remove path.
-	if e := os.Remove(deletePath); e != nil {
+	if e := remove(deletePath); e != nil {
 		if isSysErrNotEmpty(e) {
 			return nil
 		}
@@ -530,6 +1138,23 @@ func (f *fsClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 	return resultCh
 }
 
+// relativeListPath returns fpath relative to dir with forward slashes,
+// folders ending with a slash. The folder itself has an empty path.
+func relativeListPath(dir, fpath string, isDir bool) string {
+	rel, e := filepath.Rel(dir, fpath)
+	if e != nil {
+		return filepath.ToSlash(fpath)
+	}
+	if rel == "." {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+	if isDir {
+		rel += "/"
+	}
+	return rel
+}
+
 // List - list files and folders.
 func (f *fsClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
 	contentCh := make(chan *ClientContent)
@@ -537,17 +1162,25 @@ func (f *fsClient) List(ctx context.Context, opts ListOptions) <-chan *ClientCon
 
 	if opts.Recursive {
 		if opts.ShowDir == DirNone {
-			go f.listRecursiveInRoutine(contentCh, opts.WithMetadata)
+			go f.listRecursiveInRoutine(ctx, contentCh, opts)
 		} else {
-			go f.listDirOpt(contentCh, opts.Incomplete, opts.WithMetadata, opts.ShowDir)
+			go f.listDirOpt(ctx, contentCh, opts.Incomplete, opts.WithMetadata, opts.ShowDir, opts.MaxDepth)
 		}
 	} else {
-		go f.listInRoutine(contentCh, opts.WithMetadata)
+		go f.listInRoutine(ctx, contentCh, opts.WithMetadata)
+	}
+
+	// Paths without a trailing separator are listed from their parent
+	// folder, as the listing routines do.
+	rootDir := f.PathURL.Path
+	if !strings.HasSuffix(rootDir, string(f.PathURL.Separator)) {
+		rootDir = filepath.Dir(rootDir)
 	}
 
 	// This function filters entries from any  listing go routine
 	// created previously. If isIncomplete is activated, we will
-	// only show partly uploaded files,
+	// only show partly uploaded files, once ctx is cancelled the
+	// remaining entries are drained so the listing routine can return.
 	go func() {
 		for c := range contentCh {
 			if opts.Incomplete {
@@ -561,8 +1194,15 @@ func (f *fsClient) List(ctx context.Context, opts ListOptions) <-chan *ClientCon
 					continue
 				}
 			}
+			if opts.RelativePaths && c.Err == nil {
+				c.URL.Path = relativeListPath(rootDir, c.URL.Path, c.Type.IsDir())
+				c.URL.Separator = '/'
+			}
 			// Send to filtered channel
-			filteredCh <- c
+			select {
+			case filteredCh <- c:
+			case <-ctx.Done():
+			}
 		}
 		defer close(filteredCh)
 	}()
@@ -570,43 +1210,77 @@ func (f *fsClient) List(ctx context.Context, opts ListOptions) <-chan *ClientCon
 	return filteredCh
 }
 
-// byDirName implements sort.Interface.
-type byDirName []os.FileInfo
+// byDirName implements sort.Interface, sorting the entries by their
+// object key names.
+type byDirName struct {
+	list []os.FileInfo
+	keys []string
+}
+
+func (f byDirName) Len() int           { return len(f.list) }
+func (f byDirName) Less(i, j int) bool { return f.keys[i] < f.keys[j] }
+func (f byDirName) Swap(i, j int) {
+	f.list[i], f.list[j] = f.list[j], f.list[i]
+	f.keys[i], f.keys[j] = f.keys[j], f.keys[i]
+}
 
-func (f byDirName) Len() int { return len(f) }
-func (f byDirName) Less(i, j int) bool {
-	// For directory add an ending separator fortrue lexical
-	// order.
-	if f[i].Mode().IsDir() {
-		return f[i].Name()+string(filepath.Separator) < f[j].Name()
+// dirEntryKey returns the name of the entry as it sorts among object
+// keys, directories and links to directories followed with --symlinks
+// end with a '/' whatever the OS separator.
+func dirEntryKey(dirname string, fi os.FileInfo) string {
+	isDir := fi.IsDir()
+	if fi.Mode()&os.ModeSymlink == os.ModeSymlink && globalSymlinks == symlinksFollow {
+		st, e := os.Stat(filepath.Join(dirname, fi.Name()))
+		isDir = e == nil && st.IsDir()
 	}
-	// For directory add an ending separator for true lexical
-	// order.
-	if f[j].Mode().IsDir() {
-		return f[i].Name() < f[j].Name()+string(filepath.Separator)
+	if isDir {
+		return fi.Name() + slashSeperator
 	}
-	return f[i].Name() < f[j].Name()
+	return fi.Name()
 }
-func (f byDirName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
 
-// readDir reads the directory named by dirname and returns
-// a list of sorted directory entries.
+// readDir reads the directory named by dirname and returns a list of
+// directory entries in the lexical order of S3 listings.
 func readDir(dirname string) ([]os.FileInfo, error) {
 	f, e := os.Open(dirname)
 	if e != nil {
 		return nil, e
 	}
+	defer f.Close()
 	list, e := f.Readdir(-1)
 	if e != nil {
 		return nil, e
 	}
-	defer f.Close()
-	sort.Sort(byDirName(list))
+	keys := make([]string, len(list))
+	for i, fi := range list {
+		keys[i] = dirEntryKey(dirname, fi)
+	}
+	sort.Sort(byDirName{list: list, keys: keys})
 	return list, nil
 }
 
+// isSymlinkLoop returns true if the directory link at fp points to
+// itself or to one of its parents, following it would never end.
+func isSymlinkLoop(fp string) bool {
+	if abs, e := filepath.Abs(fp); e == nil {
+		fp = abs
+	}
+	target, e := filepath.EvalSymlinks(fp)
+	if e != nil {
+		return true
+	}
+	for dir := filepath.Dir(fp); ; dir = filepath.Dir(dir) {
+		if real, e := filepath.EvalSymlinks(dir); e == nil && real == target {
+			return true
+		}
+		if dir == filepath.Dir(dir) {
+			return false
+		}
+	}
+}
+
 // listPrefixes - list all files for any given prefix.
-func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent) {
+func (f *fsClient) listPrefixes(ctx context.Context, prefix string, contentCh chan<- *ClientContent) {
 	dirName := filepath.Dir(prefix)
 	files, e := readDir(dirName)
 	if e != nil {
@@ -617,6 +1291,9 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent)
 		return
 	}
 	for _, fi := range files {
+		if ctx.Err() != nil {
+			return
+		}
 		// Skip ignored files.
 		if isIgnoredFile(fi.Name()) {
 			continue
@@ -624,12 +1301,21 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent)
 
 		file := filepath.Join(dirName, fi.Name())
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			if globalSymlinks == symlinksSkip {
+				continue
+			}
 			st, e := os.Stat(file)
 			if e != nil {
 				// Ignore any errors on symlink
 				continue
 			}
 			if strings.HasPrefix(file, prefix) {
+				if isSpecialFile(st.Mode()) {
+					if content := listSpecialFile(file, st); content != nil {
+						contentCh <- content
+					}
+					continue
+				}
 				contentCh <- &ClientContent{
 					URL:  *newClientURL(file),
 					Time: st.ModTime(),
@@ -641,6 +1327,12 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent)
 			}
 		}
 		if strings.HasPrefix(file, prefix) {
+			if isSpecialFile(fi.Mode()) {
+				if content := listSpecialFile(file, fi); content != nil {
+					contentCh <- content
+				}
+				continue
+			}
 			contentCh <- &ClientContent{
 				URL:  *newClientURL(file),
 				Time: fi.ModTime(),
@@ -652,7 +1344,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent)
 	}
 }
 
-func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, isMetadata bool) {
+func (f *fsClient) listInRoutine(ctx context.Context, contentCh chan<- *ClientContent, isMetadata bool) {
 	// close the channel when the function returns.
 	defer close(contentCh)
 
@@ -665,7 +1357,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, isMetadata boo
 		if _, ok := err.ToGoError().(PathNotFound); ok {
 			// If file does not exist treat it like a prefix and list all prefixes if any.
 			prefix := fpath
-			f.listPrefixes(prefix, contentCh)
+			f.listPrefixes(ctx, prefix, contentCh)
 			return
 		}
 		// For all other errors we return genuine error back to the caller.
@@ -676,7 +1368,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, isMetadata boo
 	// Now if the file exists and doesn't end with a separator ('/') do not traverse it.
 	// If the directory doesn't end with a separator, do not traverse it.
 	if !strings.HasSuffix(fpath, string(pathURL.Separator)) && fst.Mode().IsDir() && fpath != "." {
-		f.listPrefixes(fpath, contentCh)
+		f.listPrefixes(ctx, fpath, contentCh)
 		return
 	}
 
@@ -689,8 +1381,14 @@ func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, isMetadata boo
 			return
 		}
 		for _, file := range files {
+			if ctx.Err() != nil {
+				return
+			}
 			fi := file
 			if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+				if globalSymlinks == symlinksSkip {
+					continue
+				}
 				fp := filepath.Join(fpath, fi.Name())
 				fi, e = os.Stat(fp)
 				if e != nil {
@@ -698,7 +1396,9 @@ func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, isMetadata boo
 					continue
 				}
 			}
-			if fi.Mode().IsRegular() || fi.Mode().IsDir() {
+			// Special files are shown as they are, such as by ls, the
+			// --special-files policy applies to recursive listings.
+			if fi.Mode().IsRegular() || fi.Mode().IsDir() || isSpecialFile(fi.Mode()) {
 				pathURL = *f.PathURL
 				pathURL.Path = filepath.Join(pathURL.Path, fi.Name())
 
@@ -728,7 +1428,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, isMetadata boo
 }
 
 // List files recursively using non-recursive mode.
-func (f *fsClient) listDirOpt(contentCh chan *ClientContent, isIncomplete bool, isMetadata bool, dirOpt DirOpt) {
+func (f *fsClient) listDirOpt(ctx context.Context, contentCh chan *ClientContent, isIncomplete bool, isMetadata bool, dirOpt DirOpt, maxDepth int) {
 	defer close(contentCh)
 
 	// Trim trailing / or \.
@@ -738,9 +1438,10 @@ func (f *fsClient) listDirOpt(contentCh chan *ClientContent, isIncomplete bool,
 		currentPath = strings.TrimSuffix(currentPath, `\`)
 	}
 
-	// Closure function reads currentPath and sends to contentCh. If a directory is found, it lists the directory content recursively.
-	var listDir func(currentPath string) bool
-	listDir = func(currentPath string) (isStop bool) {
+	// Closure function reads currentPath and sends to contentCh. If a directory is found, it lists the directory content recursively
+	// until maxDepth, depth being the level of the entries of currentPath.
+	var listDir func(currentPath string, depth int) bool
+	listDir = func(currentPath string, depth int) (isStop bool) {
 		files, e := readDir(currentPath)
 		if e != nil {
 			if os.IsNotExist(e) {
@@ -765,7 +1466,36 @@ func (f *fsClient) listDirOpt(contentCh chan *ClientContent, isIncomplete bool,
 		}
 
 		for _, file := range files {
+			if ctx.Err() != nil {
+				return true
+			}
 			name := filepath.Join(currentPath, file.Name())
+			if globalSkipHidden && isHiddenFile(name) {
+				continue
+			}
+			if file.Mode()&os.ModeSymlink == os.ModeSymlink {
+				if globalSymlinks == symlinksSkip {
+					continue
+				}
+				if globalSymlinks == symlinksFollow {
+					st, e := os.Stat(name)
+					if e != nil {
+						// Ignore any errors on symlink
+						continue
+					}
+					if st.IsDir() && isSymlinkLoop(name) {
+						contentCh <- &ClientContent{Err: probe.NewError(TooManyLevelsSymlink{Path: name})}
+						continue
+					}
+					file = st
+				}
+			}
+			if isSpecialFile(file.Mode()) {
+				if content := listSpecialFile(name, file); content != nil {
+					contentCh <- content
+				}
+				continue
+			}
 			content := ClientContent{
 				URL:  *newClientURL(name),
 				Time: file.ModTime(),
@@ -777,7 +1507,7 @@ func (f *fsClient) listDirOpt(contentCh chan *ClientContent, isIncomplete bool,
 				if dirOpt == DirFirst && !isIncomplete {
 					contentCh <- &content
 				}
-				if listDir(filepath.Join(name)) {
+				if (maxDepth == 0 || depth < maxDepth) && listDir(filepath.Join(name), depth+1) {
 					return true
 				}
 				if dirOpt == DirLast && !isIncomplete {
@@ -799,14 +1529,14 @@ func (f *fsClient) listDirOpt(contentCh chan *ClientContent, isIncomplete bool,
 		contentCh <- &ClientContent{URL: *newClientURL(currentPath), Type: os.ModeDir}
 	}
 
-	listDir(currentPath)
+	listDir(currentPath, 1)
 
 	if dirOpt == DirLast && !isIncomplete {
 		contentCh <- &ClientContent{URL: *newClientURL(currentPath), Type: os.ModeDir}
 	}
 }
 
-func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent, isMetadata bool) {
+func (f *fsClient) listRecursiveInRoutine(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
 	// close channels upon return.
 	defer close(contentCh)
 	var dirName string
@@ -816,7 +1546,21 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent, isMetad
 		pathURL.Path = filepath.FromSlash(pathURL.Path)
 		pathURL.Separator = os.PathSeparator
 	}
+	var walker *fsWalker
+	// isMaxDepth returns true if the folder fp is opts.MaxDepth levels
+	// below dirName, its entries are then not listed.
+	isMaxDepth := func(fp string) bool {
+		if opts.MaxDepth == 0 {
+			return false
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(fp, dirName), string(pathURL.Separator))
+		return strings.Count(rel, string(pathURL.Separator))+1 >= opts.MaxDepth
+	}
 	visitFS := func(fp string, fi os.FileInfo, e error) error {
+		// Stop walking once the listing is cancelled.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		// If file path ends with filepath.Separator and equals to root path, skip it.
 		if strings.HasSuffix(fp, string(pathURL.Separator)) {
 			if fp == dirName {
@@ -830,6 +1574,10 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent, isMetad
 
 		// Ignore files from ignore list.
 		if isIgnoredFile(fi.Name()) {
+			// Do not descend into hidden folders such as .git either.
+			if fi.IsDir() && globalSkipHidden && isHiddenFile(fi.Name()) {
+				return xfilepath.ErrSkipDir
+			}
 			return nil
 		}
 
@@ -871,12 +1619,44 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent, isMetad
 			}
 			return e
 		}
+		if fi.IsDir() && isMaxDepth(fp) {
+			return xfilepath.ErrSkipDir
+		}
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			if globalSymlinks == symlinksSkip {
+				return nil
+			}
+			if globalSymlinks == symlinksLink {
+				contentCh <- &ClientContent{
+					URL:  *newClientURL(fp),
+					Time: fi.ModTime(),
+					Size: fi.Size(),
+					Type: fi.Mode(),
+				}
+				return nil
+			}
 			fi, e = os.Stat(fp)
 			if e != nil {
 				// Ignore any errors for symlink
 				return nil
 			}
+			if fi.IsDir() && globalSymlinks == symlinksFollow {
+				if isMaxDepth(fp) {
+					return nil
+				}
+				if isSymlinkLoop(fp) {
+					contentCh <- &ClientContent{Err: probe.NewError(TooManyLevelsSymlink{Path: fp})}
+					return nil
+				}
+				// Walk the target as if it was a sub-directory.
+				return walker.Walk(fp + string(pathURL.Separator))
+			}
+		}
+		if isSpecialFile(fi.Mode()) {
+			if content := listSpecialFile(fp, fi); content != nil {
+				contentCh <- content
+			}
+			return nil
 		}
 		if fi.Mode().IsRegular() {
 			contentCh <- &ClientContent{
@@ -906,7 +1686,8 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent, isMetad
 		filePrefix = pathURL.Path
 	}
 	// walks invokes our custom function.
-	e := xfilepath.Walk(dirName, visitFS)
+	walker = newFSWalker(globalWalkWorkers, !opts.Unordered, visitFS)
+	e := walker.Walk(dirName)
 	if e != nil {
 		contentCh <- &ClientContent{
 			Err: probe.NewError(e),
@@ -920,6 +1701,9 @@ func (f *fsClient) MakeBucket(ctx context.Context, region string, ignoreExisting
 	// to call os.Mkdir() when ignoredExisting is disabled and os.MkdirAll()
 	// otherwise.
 	// NOTE: withLock=true has no meaning here.
+	if hasReservedName(f.PathURL.Path) {
+		return probe.NewError(PathNameReserved{Path: f.PathURL.Path})
+	}
 	e := os.MkdirAll(f.PathURL.Path, 0o777)
 	if e != nil {
 		return probe.NewError(e)
@@ -927,12 +1711,31 @@ func (f *fsClient) MakeBucket(ctx context.Context, region string, ignoreExisting
 	return nil
 }
 
+// StatBucket - verify the directory of the URL exists.
+func (f *fsClient) StatBucket(ctx context.Context) (*ClientContent, *probe.Error) {
+	st, e := os.Stat(f.PathURL.Path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil, probe.NewError(BucketDoesNotExist{Bucket: f.PathURL.Path})
+		}
+		err := f.toClientError(e, f.PathURL.Path)
+		return nil, err.Trace(f.PathURL.Path)
+	}
+	if !st.IsDir() {
+		return nil, probe.NewError(BucketDoesNotExist{Bucket: f.PathURL.Path})
+	}
+	return &ClientContent{URL: *f.PathURL, Time: st.ModTime(), Type: st.Mode()}, nil
+}
+
 // RemoveBucket - remove a bucket
 func (f *fsClient) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Error {
 	var e error
-	if forceRemove {
+	switch {
+	case forceRemove && globalTrash:
+		e = moveToTrash(f.PathURL.Path)
+	case forceRemove:
 		e = os.RemoveAll(f.PathURL.Path)
-	} else {
+	default:
 		e = os.Remove(f.PathURL.Path)
 	}
 	return probe.NewError(e)
@@ -1065,6 +1868,19 @@ func (f *fsClient) Stat(ctx context.Context, opts StatOptions) (content *ClientC
 	content.Metadata = map[string]string{
 		"Content-Type": guessURLContentType(f.PathURL.Path),
 	}
+	if isSpecialFile(st.Mode()) && globalSpecialFiles == specialFilesMetadata {
+		content.Size = 0
+		content.Type = st.Mode().Perm()
+		content.Metadata[metadataKeyFileType] = specialFileType(st.Mode())
+	}
+	if !st.Mode().IsDir() {
+		if userMetadata, e := getUserMetadata(f.PathURL.Path); e == nil && len(userMetadata) > 0 {
+			content.UserMetadata = userMetadata
+			for k, v := range userMetadata {
+				content.Metadata[http.CanonicalHeaderKey(userMetadataPrefix+k)] = v
+			}
+		}
+	}
 
 	path := f.PathURL.String()
 	// Populates meta data with file system attribute only in case of
@@ -1079,6 +1895,9 @@ func (f *fsClient) Stat(ctx context.Context, opts StatOptions) (content *ClientC
 			return content, nil
 		}
 		for k, v := range metaData {
+			if strings.HasPrefix(k, userMetadataXattrPrefix) {
+				continue
+			}
 			content.Metadata[k] = v
 		}
 		content.Metadata[metadataKey] = fileAttr
@@ -1087,6 +1906,16 @@ func (f *fsClient) Stat(ctx context.Context, opts StatOptions) (content *ClientC
 	return content, nil
 }
 
+// isSameFile returns true if path still names the opened file.
+func isSameFile(f *os.File, path string) bool {
+	fi, e := f.Stat()
+	if e != nil {
+		return false
+	}
+	st, e := os.Stat(path)
+	return e == nil && os.SameFile(fi, st)
+}
+
 // toClientError error constructs a typed client error for known filesystem errors.
 func (f *fsClient) toClientError(e error, fpath string) *probe.Error {
 	if os.IsPermission(e) {
@@ -1126,6 +1955,106 @@ func (f *fsClient) fsStat(isIncomplete bool) (os.FileInfo, *probe.Error) {
 func (f *fsClient) AddUserAgent(_, _ string) {
 }
 
+// GetObjectACL - not implemented for filesystem.
+func (f *fsClient) GetObjectACL(ctx context.Context) (string, []ClientGrant, *probe.Error) {
+	return "", nil, probe.NewError(APINotImplemented{
+		API:     "GetObjectACL",
+		APIType: "filesystem",
+	})
+}
+
+// Grantees used to describe directory permissions as bucket ACL grants.
+const (
+	fsAuthenticatedUsersGrantee = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	fsAllUsersGrantee           = "http://acs.amazonaws.com/groups/global/AllUsers"
+)
+
+// fsModeGrants - maps owner, group and other permission bits to ACL grants.
+func fsModeGrants(owner string, mode os.FileMode) []ClientGrant {
+	permission := func(bits os.FileMode) string {
+		switch {
+		case bits&0o6 == 0o6:
+			return "FULL_CONTROL"
+		case bits&0o4 != 0:
+			return "READ"
+		case bits&0o2 != 0:
+			return "WRITE"
+		}
+		return ""
+	}
+	var grants []ClientGrant
+	for _, g := range []struct {
+		grantee string
+		bits    os.FileMode
+	}{
+		{owner, mode >> 6 & 0o7},
+		{fsAuthenticatedUsersGrantee, mode >> 3 & 0o7},
+		{fsAllUsersGrantee, mode & 0o7},
+	} {
+		if p := permission(g.bits); p != "" {
+			grants = append(grants, ClientGrant{Grantee: g.grantee, Permission: p})
+		}
+	}
+	return grants
+}
+
+// GetBucketACL - get directory owner and permissions as ACL grants.
+func (f *fsClient) GetBucketACL(ctx context.Context) (string, []ClientGrant, *probe.Error) {
+	// For windows this feature is not implemented.
+	if runtime.GOOS == "windows" {
+		return "", nil, probe.NewError(APINotImplemented{API: "GetBucketACL", APIType: "filesystem"})
+	}
+	st, err := f.fsStat(false)
+	if err != nil {
+		return "", nil, err.Trace(f.PathURL.String())
+	}
+	if !st.Mode().IsDir() {
+		return "", nil, probe.NewError(APINotImplemented{API: "GetBucketACL", APIType: "filesystem"})
+	}
+	fileAttr, e := disk.GetFileSystemAttrs(f.PathURL.Path)
+	if e != nil {
+		return "", nil, probe.NewError(e)
+	}
+	attr, _ := parseAttribute(map[string]string{metadataKey: fileAttr})
+	owner := attr["uname"]
+	if owner == "" {
+		owner = attr["uid"]
+	}
+	return owner, fsModeGrants(owner, st.Mode()&os.ModePerm), nil
+}
+
+// SetBucketACL - set directory permissions from a canned ACL.
+func (f *fsClient) SetBucketACL(ctx context.Context, acl string) *probe.Error {
+	// For windows this feature is not implemented.
+	if runtime.GOOS == "windows" {
+		return probe.NewError(APINotImplemented{API: "SetBucketACL", APIType: "filesystem"})
+	}
+	st, err := f.fsStat(false)
+	if err != nil {
+		return err.Trace(f.PathURL.String())
+	}
+	if !st.Mode().IsDir() {
+		return probe.NewError(APINotImplemented{API: "SetBucketACL", APIType: "filesystem"})
+	}
+	var mode os.FileMode
+	switch acl {
+	case "private":
+		mode = os.FileMode(0o700)
+	case "public-read":
+		mode = os.FileMode(0o755)
+	case "public-read-write":
+		mode = os.FileMode(0o777)
+	case "authenticated-read":
+		mode = os.FileMode(0o750)
+	default:
+		return probe.NewError(fmt.Errorf("unknown canned ACL `%s`, expected one of %s", acl, strings.Join(cannedBucketACLs, ", ")))
+	}
+	if e := os.Chmod(f.PathURL.Path, mode); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
 // Get Object Tags
 func (f *fsClient) GetTags(ctx context.Context, _ string) (map[string]string, *probe.Error) {
 	return nil, probe.NewError(APINotImplemented{
@@ -1247,6 +2176,11 @@ func (f *fsClient) DeleteEncryption(ctx context.Context) *probe.Error {
 	})
 }
 
+// DiskUsage - walks the folder concurrently to sum its files.
+func (f *fsClient) DiskUsage(ctx context.Context, timeRef time.Time, withVersions bool) (DiskUsageInfo, *probe.Error) {
+	return listDiskUsage(ctx, f, ListOptions{Unordered: true})
+}
+
 // Gets bucket info
 func (f *fsClient) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
 	return BucketInfo{}, probe.NewError(APINotImplemented{
@@ -0,0 +1,70 @@
+//go:build darwin || freebsd || netbsd
+// +build darwin freebsd netbsd
+
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// isSparseFile returns true if fewer blocks than its size are allocated
+// to the file, i.e. it has holes.
+func isSparseFile(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && st.Blocks*512 < st.Size
+}
+
+// hardLinkID returns the device and inode of the file if more than one
+// hard link points to it.
+func hardLinkID(fi os.FileInfo) (fileID, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// getFileID returns the device and inode of the file.
+func getFileID(fi os.FileInfo) (fileID, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// preallocate allocates size bytes for the file, this is not supported
+// on this OS.
+func preallocate(f *os.File, size int64) error {
+	return nil
+}
+
+// cloneFile makes dst share the data of src, this is not supported on
+// this OS.
+func cloneFile(dst, src *os.File) error {
+	return syscall.ENOSYS
+}
+
+// copyFileRange copies bytes from src to dst in the kernel, this is not
+// supported on this OS.
+func copyFileRange(dst, src *os.File, size int) (int64, error) {
+	return 0, syscall.ENOSYS
+}
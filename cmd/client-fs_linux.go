@@ -22,11 +22,14 @@ package cmd
 
 import (
 	"encoding/hex"
+	"os"
 	"strings"
+	"syscall"
 	"unicode/utf8"
 
 	"github.com/pkg/xattr"
 	"github.com/rjeczalik/notify"
+	"golang.org/x/sys/unix"
 )
 
 var (
@@ -107,3 +110,52 @@ func getAllXattrs(path string) (map[string]string, error) {
 	}
 	return xMetadata, nil
 }
+
+// isSparseFile returns true if fewer blocks than its size are allocated
+// to the file, i.e. it has holes.
+func isSparseFile(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && st.Blocks*512 < st.Size
+}
+
+// hardLinkID returns the device and inode of the file if more than one
+// hard link points to it.
+func hardLinkID(fi os.FileInfo) (fileID, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// getFileID returns the device and inode of the file.
+func getFileID(fi os.FileInfo) (fileID, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// preallocate allocates size bytes for the file, filesystems without
+// fallocate support are left to allocate the file as it is written.
+func preallocate(f *os.File, size int64) error {
+	e := syscall.Fallocate(int(f.Fd()), 0, 0, size)
+	if e == syscall.EOPNOTSUPP || e == syscall.ENOSYS {
+		return nil
+	}
+	return e
+}
+
+// cloneFile makes dst share the data of src, on filesystems such as
+// btrfs and XFS supporting reflinks.
+func cloneFile(dst, src *os.File) error {
+	return unix.IoctlFileClone(int(dst.Fd()), int(src.Fd()))
+}
+
+// copyFileRange copies up to size bytes from src to dst in the kernel,
+// from and advancing the offsets of both files.
+func copyFileRange(dst, src *os.File, size int) (int64, error) {
+	n, e := unix.CopyFileRange(int(src.Fd()), nil, int(dst.Fd()), nil, size, 0)
+	return int64(n), e
+}
@@ -20,7 +20,12 @@
 
 package cmd
 
-import "github.com/rjeczalik/notify"
+import (
+	"os"
+	"syscall"
+
+	"github.com/rjeczalik/notify"
+)
 
 var (
 	// EventTypePut contains the notify events that will cause a put (writer)
@@ -56,3 +61,39 @@ func IsDeleteEvent(event notify.Event) bool {
 func getAllXattrs(path string) (map[string]string, error) {
 	return nil, nil
 }
+
+// isSparseFile returns true if the file has holes, it is not detected
+// on this OS.
+func isSparseFile(fi os.FileInfo) bool {
+	return false
+}
+
+// hardLinkID returns the device and inode of the file if more than one
+// hard link points to it, hard links are not detected on this OS.
+func hardLinkID(fi os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
+
+// getFileID returns the device and inode of the file, they are not
+// known on this OS.
+func getFileID(fi os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
+
+// preallocate allocates size bytes for the file, this is not supported
+// on this OS.
+func preallocate(f *os.File, size int64) error {
+	return nil
+}
+
+// cloneFile makes dst share the data of src, this is not supported on
+// this OS.
+func cloneFile(dst, src *os.File) error {
+	return syscall.ENOSYS
+}
+
+// copyFileRange copies bytes from src to dst in the kernel, this is not
+// supported on this OS.
+func copyFileRange(dst, src *os.File, size int) (int64, error) {
+	return 0, syscall.ENOSYS
+}
@@ -20,12 +20,19 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/minio/mc/pkg/disk"
 	. "gopkg.in/check.v1"
 )
 
@@ -222,6 +229,16 @@ func (s *TestSuite) TestStatBucket(c *C) {
 	c.Assert(err, IsNil)
 	_, err = fsClient.Stat(context.Background(), StatOptions{})
 	c.Assert(err, IsNil)
+	content, err := fsClient.StatBucket(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+
+	fsClient, err = fsNew(filepath.Join(root, "missing"))
+	c.Assert(err, IsNil)
+	_, err = fsClient.StatBucket(context.Background())
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(BucketDoesNotExist)
+	c.Assert(ok, Equals, true)
 }
 
 // Test bucket acl fails for directories.
@@ -268,6 +285,134 @@ func (s *TestSuite) TestPut(c *C) {
 
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
+
+	// A stale part file must not leak into the new content.
+	c.Assert(ioutil.WriteFile(objectPath+partSuffix, []byte("stale content"), 0o644), IsNil)
+	_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte("hi")), 2, nil, PutOptions{})
+	c.Assert(err, IsNil)
+	b, e := ioutil.ReadFile(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(string(b), Equals, "hi")
+
+	// A failed write leaves the previous content and no part file behind.
+	_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte("short")), 10, nil, PutOptions{})
+	c.Assert(err, NotNil)
+	b, e = ioutil.ReadFile(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(string(b), Equals, "hi")
+	_, e = os.Stat(objectPath + partSuffix)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	// A part file locked by another writer is left alone.
+	if runtime.GOOS != "windows" {
+		lock, e := os.OpenFile(objectPath+partSuffix, os.O_CREATE|os.O_WRONLY, 0o666)
+		c.Assert(e, IsNil)
+		unlock, e := lockFile(lock)
+		c.Assert(e, IsNil)
+		_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte("hi")), 2, nil, PutOptions{})
+		c.Assert(err, NotNil)
+		_, ok := err.ToGoError().(PathBusy)
+		c.Assert(ok, Equals, true)
+		_, e = os.Stat(objectPath + partSuffix)
+		c.Assert(e, IsNil)
+		unlock.Close()
+		lock.Close()
+		os.Remove(objectPath + partSuffix)
+	}
+
+	// Sizes beyond the free space fail before writing.
+	if _, e = disk.GetFreeSpace(root); e == nil {
+		_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte("big")), 1<<62, nil, PutOptions{})
+		c.Assert(err, NotNil)
+		_, ok := err.ToGoError().(InsufficientSpace)
+		c.Assert(ok, Equals, true)
+		_, e = os.Stat(objectPath + partSuffix)
+		c.Assert(os.IsNotExist(e), Equals, true)
+	}
+
+	// Short reads of preallocated files fail and leave no file behind.
+	shortPath := filepath.Join(root, "short")
+	shortClient, err := fsNew(shortPath)
+	c.Assert(err, IsNil)
+	_, err = shortClient.Put(context.Background(), bytes.NewReader([]byte("hello")), 1024, nil, PutOptions{})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(UnexpectedEOF)
+	c.Assert(ok, Equals, true)
+	_, e = os.Stat(shortPath + partSuffix)
+	c.Assert(os.IsNotExist(e), Equals, true)
+	_, e = os.Stat(shortPath)
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
+
+// Test the policies for special files.
+func (s *TestSuite) TestSpecialFiles(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("unix sockets are not listed as special files on windows")
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func(policy string) { globalSpecialFiles = policy }(globalSpecialFiles)
+
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "object"), []byte("hello"), 0o644), IsNil)
+	socketPath := filepath.Join(root, "socket")
+	l, e := net.Listen("unix", socketPath)
+	c.Assert(e, IsNil)
+	defer l.Close()
+
+	fsClient, err := fsNew(root + string(os.PathSeparator))
+	c.Assert(err, IsNil)
+	list := func() (names []string, errs int) {
+		for content := range fsClient.List(globalContext, ListOptions{Recursive: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				errs++
+				continue
+			}
+			names = append(names, filepath.Base(content.URL.Path))
+		}
+		return names, errs
+	}
+
+	globalSpecialFiles = specialFilesSkip
+	names, errs := list()
+	c.Assert(names, DeepEquals, []string{"object"})
+	c.Assert(errs, Equals, 0)
+
+	globalSpecialFiles = specialFilesFail
+	names, errs = list()
+	c.Assert(names, DeepEquals, []string{"object"})
+	c.Assert(errs, Equals, 1)
+
+	// Non-recursive listings show special files as they are.
+	var listed []string
+	for content := range fsClient.List(globalContext, ListOptions{ShowDir: DirNone}) {
+		c.Assert(content.Err, IsNil)
+		listed = append(listed, filepath.Base(content.URL.Path))
+	}
+	c.Assert(listed, DeepEquals, []string{"object", "socket"})
+
+	// Explicitly named special files are read.
+	devClient, err := fsNew(os.DevNull)
+	c.Assert(err, IsNil)
+	reader, err := devClient.Get(context.Background(), GetOptions{})
+	c.Assert(err, IsNil)
+	reader.Close()
+
+	// Special files are copied empty, recording their type.
+	globalSpecialFiles = specialFilesMetadata
+	names, errs = list()
+	c.Assert(names, DeepEquals, []string{"object", "socket"})
+	c.Assert(errs, Equals, 0)
+	socketClient, err := fsNew(socketPath)
+	c.Assert(err, IsNil)
+	reader, err = socketClient.Get(context.Background(), GetOptions{})
+	c.Assert(err, IsNil)
+	b, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(len(b), Equals, 0)
+	content, err := socketClient.Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Metadata[metadataKeyFileType], Equals, "socket")
 }
 
 // Test read a file.
@@ -332,6 +477,70 @@ func (s *TestSuite) TestGetRange(c *C) {
 	c.Assert([]byte("hello"), DeepEquals, results.Bytes())
 }
 
+// Test local files not modified since a time are not read.
+func (s *TestSuite) TestGetNotModifiedSince(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	c.Assert(ioutil.WriteFile(objectPath, []byte("Hello, World"), 0o644), IsNil)
+	modTime := time.Date(2021, time.December, 31, 12, 0, 0, 500, time.UTC)
+	c.Assert(os.Chtimes(objectPath, modTime, modTime), IsNil)
+
+	clnt, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+	_, err = clnt.Get(context.Background(), GetOptions{IfModifiedSince: modTime.Truncate(time.Second)})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectNotModified)
+	c.Assert(ok, Equals, true)
+
+	reader, err := clnt.Get(context.Background(), GetOptions{IfModifiedSince: modTime.Add(-time.Second)})
+	c.Assert(err, IsNil)
+	c.Assert(reader.Close(), IsNil)
+}
+
+// Test concurrent reads at different offsets of a file.
+func (s *TestSuite) TestGetReaderAt(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	c.Assert(ioutil.WriteFile(objectPath, data, 0o644), IsNil)
+
+	clnt, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+	reader, size, err := clnt.(*fsClient).GetReaderAt(context.Background())
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	c.Assert(size, Equals, int64(len(data)))
+
+	parts := make([][]byte, 4)
+	errs := make([]error, len(parts))
+	partSize := len(data) / len(parts)
+	var wg sync.WaitGroup
+	for i := range parts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			parts[i] = make([]byte, partSize)
+			_, errs[i] = reader.ReadAt(parts[i], int64(i*partSize))
+		}(i)
+	}
+	wg.Wait()
+	for i := range parts {
+		c.Assert(errs[i], IsNil)
+		c.Assert(parts[i], DeepEquals, data[i*partSize:(i+1)*partSize])
+	}
+
+	clnt, err = fsNew(root)
+	c.Assert(err, IsNil)
+	_, _, err = clnt.(*fsClient).GetReaderAt(context.Background())
+	c.Assert(err, Not(IsNil))
+}
+
 // Test stat file.
 func (s *TestSuite) TestStatObject(c *C) {
 	root, e := ioutil.TempDir(os.TempDir(), "fs-")
@@ -383,3 +592,582 @@ func (s *TestSuite) TestCopy(c *C) {
 	err = fsClientTarget.Copy(context.Background(), sourcePath, CopyOptions{size: int64(len(data))}, nil)
 	c.Assert(err, IsNil)
 }
+
+// Test copying a sparse file keeps its holes.
+func (s *TestSuite) TestCopySparse(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	sourcePath := filepath.Join(root, "source")
+	targetPath := filepath.Join(root, "target")
+
+	const size = 8 << 20
+	file, e := os.Create(sourcePath)
+	c.Assert(e, IsNil)
+	c.Assert(file.Truncate(size), IsNil)
+	_, e = file.WriteAt([]byte("hello"), size/2)
+	c.Assert(e, IsNil)
+	c.Assert(file.Close(), IsNil)
+	st, e := os.Stat(sourcePath)
+	c.Assert(e, IsNil)
+	if !isSparseFile(st) {
+		c.Skip("sparse files are not supported")
+	}
+
+	fsClient, err := fsNew(targetPath)
+	c.Assert(err, IsNil)
+	err = fsClient.Copy(context.Background(), sourcePath, CopyOptions{size: size}, nil)
+	c.Assert(err, IsNil)
+
+	st, e = os.Stat(targetPath)
+	c.Assert(e, IsNil)
+	c.Assert(st.Size(), Equals, int64(size))
+	c.Assert(isSparseFile(st), Equals, true)
+	source, e := ioutil.ReadFile(sourcePath)
+	c.Assert(e, IsNil)
+	target, e := ioutil.ReadFile(targetPath)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(source, target), Equals, true)
+}
+
+// progressCounter counts the bytes reported by copies.
+type progressCounter struct{ n int64 }
+
+func (p *progressCounter) Read(b []byte) (int, error) {
+	p.n += int64(len(b))
+	return len(b), nil
+}
+
+// Test copies of local files in the kernel.
+func (s *TestSuite) TestCopyOffload(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	sourcePath := filepath.Join(root, "source")
+	targetPath := filepath.Join(root, "target")
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*copyFileOffloadSize+100)/16)
+	c.Assert(ioutil.WriteFile(sourcePath, data, 0o644), IsNil)
+
+	fsClient, err := fsNew(targetPath)
+	c.Assert(err, IsNil)
+	progress := &progressCounter{}
+	err = fsClient.Copy(context.Background(), sourcePath, CopyOptions{size: int64(len(data))}, progress)
+	c.Assert(err, IsNil)
+	c.Assert(progress.n, Equals, int64(len(data)))
+	target, e := ioutil.ReadFile(targetPath)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(target, data), Equals, true)
+
+	// Files are copied from their offset.
+	file, e := os.Open(sourcePath)
+	c.Assert(e, IsNil)
+	_, e = file.Seek(100, io.SeekStart)
+	c.Assert(e, IsNil)
+	n, err := fsClient.Put(context.Background(), file, int64(len(data)-100), nil, PutOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)-100))
+	target, e = ioutil.ReadFile(targetPath)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(target, data[100:]), Equals, true)
+}
+
+// Test copy recreates hard links between source files at the target.
+func (s *TestSuite) TestCopyHardLinks(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	sourcePath := filepath.Join(root, "source", "a")
+	c.Assert(os.MkdirAll(filepath.Dir(sourcePath), 0o755), IsNil)
+	c.Assert(ioutil.WriteFile(sourcePath, []byte("hello"), 0o644), IsNil)
+	linkPath := filepath.Join(root, "source", "b")
+	if e = os.Link(sourcePath, linkPath); e != nil {
+		c.Skip("hard links are not supported")
+	}
+	st, e := os.Stat(sourcePath)
+	c.Assert(e, IsNil)
+	if _, ok := hardLinkID(st); !ok {
+		c.Skip("hard links are not detected on this OS")
+	}
+
+	var targets []os.FileInfo
+	for _, name := range []string{"a", "b"} {
+		targetPath := filepath.Join(root, "target", name)
+		fsClient, err := fsNew(targetPath)
+		c.Assert(err, IsNil)
+		err = fsClient.Copy(context.Background(), filepath.Join(root, "source", name), CopyOptions{size: 5, hardLinks: true}, nil)
+		c.Assert(err, IsNil)
+		st, e := os.Stat(targetPath)
+		c.Assert(e, IsNil)
+		targets = append(targets, st)
+	}
+	c.Assert(os.SameFile(targets[0], targets[1]), Equals, true)
+
+	// Without the option every link is copied.
+	targetPath := filepath.Join(root, "target", "c")
+	fsClient, err := fsNew(targetPath)
+	c.Assert(err, IsNil)
+	err = fsClient.Copy(context.Background(), linkPath, CopyOptions{size: 5}, nil)
+	c.Assert(err, IsNil)
+	st, e = os.Stat(targetPath)
+	c.Assert(e, IsNil)
+	c.Assert(os.SameFile(targets[0], st), Equals, false)
+}
+
+// Test bucket ACLs map to directory permissions.
+func (s *TestSuite) TestBucketACL(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bucket ACLs are not implemented on windows")
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	fsClient, err := fsNew(root)
+	c.Assert(err, IsNil)
+
+	err = fsClient.SetBucketACL(context.Background(), "public-read")
+	c.Assert(err, IsNil)
+	st, e := os.Stat(root)
+	c.Assert(e, IsNil)
+	c.Assert(st.Mode()&os.ModePerm, Equals, os.FileMode(0o755))
+
+	owner, grants, err := fsClient.GetBucketACL(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(owner, Not(Equals), "")
+	c.Assert(grants, DeepEquals, []ClientGrant{
+		{Grantee: owner, Permission: "FULL_CONTROL"},
+		{Grantee: fsAuthenticatedUsersGrantee, Permission: "READ"},
+		{Grantee: fsAllUsersGrantee, Permission: "READ"},
+	})
+
+	err = fsClient.SetBucketACL(context.Background(), "public-write")
+	c.Assert(err, Not(IsNil))
+}
+
+// Test user metadata is persisted on put and returned by stat.
+func (s *TestSuite) TestUserMetadata(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	data := "hello"
+	_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), nil, PutOptions{
+		metadata: map[string]string{
+			"Content-Type":      "application/octet-stream",
+			"X-Amz-Meta-Color":  "blue",
+			"x-amz-meta-origin": "s3",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	content, err := fsClient.Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	if content.UserMetadata == nil {
+		c.Skip("extended attributes are not supported")
+	}
+	c.Assert(content.UserMetadata, DeepEquals, map[string]string{"color": "blue", "origin": "s3"})
+	c.Assert(content.Metadata["X-Amz-Meta-Color"], Equals, "blue")
+	c.Assert(content.Metadata["X-Amz-Meta-Origin"], Equals, "s3")
+}
+
+// Test list and put stop once the context is cancelled.
+func (s *TestSuite) TestContextCancel(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		e = ioutil.WriteFile(filepath.Join(root, name), []byte("hello"), 0o644)
+		c.Assert(e, IsNil)
+	}
+
+	fsClient, err := fsNew(root + string(os.PathSeparator))
+	c.Assert(err, IsNil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	contentCh := fsClient.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone})
+	<-contentCh
+	cancel()
+	// The listing must terminate, remaining entries are dropped.
+	for range contentCh {
+	}
+
+	fsClient, err = fsNew(filepath.Join(root, "object"))
+	c.Assert(err, IsNil)
+	_, err = fsClient.Put(ctx, bytes.NewReader([]byte("hello")), 5, nil, PutOptions{})
+	c.Assert(err, NotNil)
+	_, e = os.Stat(filepath.Join(root, "object"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
+
+func (s *TestSuite) TestSymlinkPolicy(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("symlinks require privileges on windows")
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func(symlinks string) { globalSymlinks = symlinks }(globalSymlinks)
+
+	c.Assert(os.MkdirAll(filepath.Join(root, "src", "real"), 0o755), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "other"), 0o755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "src", "real", "file"), []byte("a"), 0o644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "other", "file"), []byte("b"), 0o644), IsNil)
+	c.Assert(os.Symlink(filepath.Join("real", "file"), filepath.Join(root, "src", "flink")), IsNil)
+	c.Assert(os.Symlink(filepath.Join("..", "other"), filepath.Join(root, "src", "dlink")), IsNil)
+	c.Assert(os.Symlink("..", filepath.Join(root, "src", "real", "up")), IsNil)
+
+	list := func() (names []string, loops int) {
+		fsClient, err := fsNew(filepath.Join(root, "src") + string(os.PathSeparator))
+		c.Assert(err, IsNil)
+		for content := range fsClient.List(context.Background(), ListOptions{Recursive: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				_, ok := content.Err.ToGoError().(TooManyLevelsSymlink)
+				c.Assert(ok, Equals, true)
+				loops++
+				continue
+			}
+			rel, e := filepath.Rel(filepath.Join(root, "src"), content.URL.Path)
+			c.Assert(e, IsNil)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return names, loops
+	}
+
+	globalSymlinks = symlinksDefault
+	names, loops := list()
+	c.Assert(names, DeepEquals, []string{"flink", "real/file"})
+	c.Assert(loops, Equals, 0)
+
+	globalSymlinks = symlinksFollow
+	names, loops = list()
+	c.Assert(names, DeepEquals, []string{"dlink/file", "flink", "real/file"})
+	c.Assert(loops, Equals, 1)
+
+	globalSymlinks = symlinksSkip
+	names, loops = list()
+	c.Assert(names, DeepEquals, []string{"real/file"})
+	c.Assert(loops, Equals, 0)
+
+	fsClient, err := fsNew(filepath.Join(root, "src", "flink"))
+	c.Assert(err, IsNil)
+	_, err = fsClient.Get(context.Background(), GetOptions{})
+	c.Assert(err, NotNil)
+
+	// Links are listed as they are and copied as links to local targets.
+	globalSymlinks = symlinksLink
+	names, loops = list()
+	c.Assert(names, DeepEquals, []string{"dlink", "flink", "real/file", "real/up"})
+	c.Assert(loops, Equals, 0)
+	_, err = fsClient.Get(context.Background(), GetOptions{})
+	_, ok := err.ToGoError().(PathIsSymlink)
+	c.Assert(ok, Equals, true)
+
+	for _, name := range []string{"flink", "dlink"} {
+		target := filepath.Join(root, "dst", name)
+		dstClient, err := fsNew(target)
+		c.Assert(err, IsNil)
+		c.Assert(dstClient.Copy(context.Background(), filepath.Join(root, "src", name), CopyOptions{}, nil), IsNil)
+		st, e := os.Lstat(target)
+		c.Assert(e, IsNil)
+		c.Assert(st.Mode()&os.ModeSymlink, Equals, os.ModeSymlink)
+		want, e := os.Readlink(filepath.Join(root, "src", name))
+		c.Assert(e, IsNil)
+		got, e := os.Readlink(target)
+		c.Assert(e, IsNil)
+		c.Assert(got, Equals, want)
+	}
+}
+
+func (s *TestSuite) TestSkipHidden(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func(skipHidden bool) { globalSkipHidden = skipHidden }(globalSkipHidden)
+
+	for _, name := range []string{"file", ".hidden", "Thumbs.db", filepath.Join(".git", "config"), filepath.Join("dir", "desktop.ini")} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, name), []byte("a"), 0o644), IsNil)
+	}
+
+	list := func() (names []string) {
+		fsClient, err := fsNew(root + string(os.PathSeparator))
+		c.Assert(err, IsNil)
+		for content := range fsClient.List(context.Background(), ListOptions{Recursive: true, ShowDir: DirNone}) {
+			c.Assert(content.Err, IsNil)
+			rel, e := filepath.Rel(root, content.URL.Path)
+			c.Assert(e, IsNil)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return names
+	}
+
+	globalSkipHidden = false
+	c.Assert(list(), HasLen, 5)
+
+	globalSkipHidden = true
+	c.Assert(list(), DeepEquals, []string{"file"})
+}
+
+// Test recursive listings walking folders concurrently.
+func (s *TestSuite) TestListWalkWorkers(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func(workers int) { globalWalkWorkers = workers }(globalWalkWorkers)
+
+	for i := 0; i < 20; i++ {
+		for _, name := range []string{"a", "b-1", filepath.Join("b", "c"), filepath.Join("b", "d", "e")} {
+			name = filepath.Join(fmt.Sprintf("dir%02d", i), name)
+			c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+			c.Assert(ioutil.WriteFile(filepath.Join(root, name), []byte("a"), 0o644), IsNil)
+		}
+	}
+
+	list := func(unordered bool) (names []string) {
+		fsClient, err := fsNew(root + string(os.PathSeparator))
+		c.Assert(err, IsNil)
+		for content := range fsClient.List(context.Background(), ListOptions{Recursive: true, ShowDir: DirNone, Unordered: unordered}) {
+			c.Assert(content.Err, IsNil)
+			names = append(names, content.URL.Path)
+		}
+		return names
+	}
+
+	globalWalkWorkers = 1
+	sequential := list(false)
+	c.Assert(sequential, HasLen, 80)
+
+	globalWalkWorkers = 8
+	c.Assert(list(false), DeepEquals, sequential)
+	unordered := list(true)
+	sort.Strings(unordered)
+	sorted := append([]string{}, sequential...)
+	sort.Strings(sorted)
+	c.Assert(unordered, DeepEquals, sorted)
+}
+
+// Test recursive listings are sorted like S3 listings.
+func (s *TestSuite) TestListSorted(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func(symlinks string) { globalSymlinks = symlinks }(globalSymlinks)
+
+	for _, name := range []string{"a-b", filepath.Join("a", "c"), "a.txt", filepath.Join("target", "f")} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, name), []byte("a"), 0o644), IsNil)
+	}
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "link-a"), []byte("a"), 0o644), IsNil)
+	if e = os.Symlink(filepath.Join(root, "target"), filepath.Join(root, "link")); e != nil {
+		c.Skip("symlinks are not supported")
+	}
+
+	globalSymlinks = symlinksFollow
+	for _, dirOpt := range []DirOpt{DirNone, DirFirst} {
+		fsClient, err := fsNew(root + string(os.PathSeparator))
+		c.Assert(err, IsNil)
+		var keys []string
+		for content := range fsClient.List(context.Background(), ListOptions{Recursive: true, ShowDir: dirOpt}) {
+			c.Assert(content.Err, IsNil)
+			if content.Type.IsRegular() {
+				rel, e := filepath.Rel(root, content.URL.Path)
+				c.Assert(e, IsNil)
+				keys = append(keys, filepath.ToSlash(rel))
+			}
+		}
+		c.Assert(keys, DeepEquals, []string{"a-b", "a.txt", "a/c", "link-a", "link/f", "target/f"})
+	}
+}
+
+// Test recursive listings limited to a depth.
+func (s *TestSuite) TestListMaxDepth(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"a", filepath.Join("b", "c"), filepath.Join("b", "d", "e"), filepath.Join("b", "d", "f", "g")} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, name), []byte("a"), 0o644), IsNil)
+	}
+
+	list := func(dirOpt DirOpt, maxDepth int) (names []string) {
+		fsClient, err := fsNew(root + string(os.PathSeparator))
+		c.Assert(err, IsNil)
+		for content := range fsClient.List(context.Background(), ListOptions{Recursive: true, ShowDir: dirOpt, MaxDepth: maxDepth}) {
+			c.Assert(content.Err, IsNil)
+			rel, e := filepath.Rel(root, content.URL.Path)
+			c.Assert(e, IsNil)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return names
+	}
+
+	c.Assert(list(DirNone, 0), DeepEquals, []string{"a", "b/c", "b/d/e", "b/d/f/g"})
+	c.Assert(list(DirNone, 1), DeepEquals, []string{"a"})
+	c.Assert(list(DirNone, 2), DeepEquals, []string{"a", "b/c"})
+	c.Assert(list(DirFirst, 2), DeepEquals, []string{".", "a", "b", "b/c", "b/d"})
+}
+
+// Test listing local entries with relative paths.
+func (s *TestSuite) TestListRelativePaths(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"a", filepath.Join("b", "c"), filepath.Join("b", "d", "e")} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, name), []byte("a"), 0o644), IsNil)
+	}
+
+	list := func(path string, opts ListOptions) (names []string) {
+		fsClient, err := fsNew(path)
+		c.Assert(err, IsNil)
+		opts.RelativePaths = true
+		for content := range fsClient.List(context.Background(), opts) {
+			c.Assert(content.Err, IsNil)
+			names = append(names, content.URL.Path)
+		}
+		return names
+	}
+
+	dir := root + string(os.PathSeparator)
+	c.Assert(list(dir, ListOptions{Recursive: true}), DeepEquals, []string{"a", "b/c", "b/d/e"})
+	c.Assert(list(dir, ListOptions{Recursive: true, ShowDir: DirFirst}), DeepEquals, []string{"", "a", "b/", "b/c", "b/d/", "b/d/e"})
+	c.Assert(list(filepath.Join(root, "b")+string(os.PathSeparator), ListOptions{}), DeepEquals, []string{"c", "d/"})
+	c.Assert(list(filepath.Join(root, "b"), ListOptions{Recursive: true}), DeepEquals, []string{"b/c", "b/d/e"})
+	c.Assert(list(filepath.Join(root, "a"), ListOptions{}), DeepEquals, []string{"a"})
+}
+
+// Test local glob patterns expansion.
+func (s *TestSuite) TestGlobFS(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"2015-1.gz", "2016-1.gz", filepath.Join("a", "2015-2.gz"), filepath.Join("a", "b", "2015-3.gz"), filepath.Join("a", "b", "x.txt")} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, name), []byte("a"), 0o644), IsNil)
+	}
+
+	testCases := []struct {
+		pattern string
+		matches []string
+	}{
+		{"2015-*.gz", []string{"2015-1.gz"}},
+		{"201?-1.gz", []string{"2015-1.gz", "2016-1.gz"}},
+		{"*/2015-*", []string{"a/2015-2.gz"}},
+		{"**/2015-*.gz", []string{"2015-1.gz", "a/2015-2.gz", "a/b/2015-3.gz"}},
+		{"a/**", []string{"a/2015-2.gz", "a/b", "a/b/2015-3.gz", "a/b/x.txt"}},
+		{"*.txt", nil},
+	}
+	for _, testCase := range testCases {
+		var matches []string
+		for content := range globFS(context.Background(), filepath.Join(root, testCase.pattern)) {
+			c.Assert(content.Err, IsNil)
+			rel, e := filepath.Rel(root, content.URL.Path)
+			c.Assert(e, IsNil)
+			matches = append(matches, filepath.ToSlash(rel))
+		}
+		c.Assert(matches, DeepEquals, testCase.matches, Commentf("pattern %s", testCase.pattern))
+	}
+}
+
+// Test removals moving files to the trash.
+func (s *TestSuite) TestRemoveTrash(c *C) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		c.Skip("the XDG trash is not used on " + runtime.GOOS)
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func(trash bool) { globalTrash = trash }(globalTrash)
+	defer os.Setenv("XDG_DATA_HOME", os.Getenv("XDG_DATA_HOME"))
+	c.Assert(os.Setenv("XDG_DATA_HOME", filepath.Join(root, "data")), IsNil)
+
+	objectPath := filepath.Join(root, "bucket", "dir", "object")
+	remove := func() {
+		c.Assert(os.MkdirAll(filepath.Dir(objectPath), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(objectPath, []byte("hello"), 0o644), IsNil)
+		fsClient, err := fsNew(filepath.Join(root, "bucket"))
+		c.Assert(err, IsNil)
+		contentCh := make(chan *ClientContent, 1)
+		contentCh <- &ClientContent{URL: *newClientURL(objectPath)}
+		close(contentCh)
+		for result := range fsClient.Remove(context.Background(), false, false, false, contentCh) {
+			c.Assert(result.Err, IsNil)
+		}
+		_, e := os.Stat(filepath.Join(root, "bucket", "dir"))
+		c.Assert(os.IsNotExist(e), Equals, true)
+	}
+
+	globalTrash = true
+	remove()
+	remove()
+	trashDir := filepath.Join(root, "data", "Trash")
+	for _, name := range []string{"object", "object.2"} {
+		data, e := ioutil.ReadFile(filepath.Join(trashDir, "files", name))
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, "hello")
+		info, e := ioutil.ReadFile(filepath.Join(trashDir, "info", name+".trashinfo"))
+		c.Assert(e, IsNil)
+		c.Assert(strings.Contains(string(info), "Path="+objectPath+"\n"), Equals, true)
+	}
+
+	globalTrash = false
+	remove()
+	files, e := ioutil.ReadDir(filepath.Join(trashDir, "files"))
+	c.Assert(e, IsNil)
+	c.Assert(files, HasLen, 2)
+}
+
+// Test the trash at the top of the filesystem of a file.
+func (s *TestSuite) TestTopTrashDir(c *C) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		c.Skip("the XDG trash is not used on " + runtime.GOOS)
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	c.Assert(ioutil.WriteFile(objectPath, []byte("hello"), 0o644), IsNil)
+	fi, e := os.Lstat(objectPath)
+	c.Assert(e, IsNil)
+
+	topDir := mountPoint(objectPath, fi)
+	c.Assert(isSameDevice(fi, topDir), Equals, true)
+	trashDir, infoPath, e := topTrashDir(objectPath, fi)
+	c.Assert(e, IsNil)
+	c.Assert(filepath.Join(topDir, infoPath), Equals, objectPath)
+	c.Assert(strings.HasPrefix(trashDir, filepath.Join(topDir, ".Trash")), Equals, true)
+}
+
+// Test disk usage of a folder.
+func (s *TestSuite) TestDiskUsage(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for i, name := range []string{"a", filepath.Join("b", "c"), filepath.Join("b", "d", "e")} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, name), bytes.Repeat([]byte("a"), i+1), 0o644), IsNil)
+	}
+
+	fsClient, err := fsNew(root + string(os.PathSeparator))
+	c.Assert(err, IsNil)
+	usage, err := fsClient.DiskUsage(context.Background(), time.Time{}, false)
+	c.Assert(err, IsNil)
+	c.Assert(usage, Equals, DiskUsageInfo{Size: 6, Count: 3})
+
+	fsClient, err = fsNew(filepath.Join(root, "b") + string(os.PathSeparator))
+	c.Assert(err, IsNil)
+	usage, err = fsClient.DiskUsage(context.Background(), time.Time{}, false)
+	c.Assert(err, IsNil)
+	c.Assert(usage, Equals, DiskUsageInfo{Size: 5, Count: 2})
+}
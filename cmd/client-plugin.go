@@ -0,0 +1,727 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+)
+
+// Backend plugins are executables named after the URL scheme they
+// serve, e.g. `mc-backend-archive` serves `archive://host/bucket/object`.
+// They are looked up in the plugins folder of the config folder first,
+// then in the PATH.
+const backendPluginPrefix = "mc-backend-"
+
+// Folder in the config folder holding backend plugins.
+const globalPluginsDir = "plugins"
+
+// Version of the protocol spoken with backend plugins.
+const backendPluginProtocol = "1"
+
+// backendPlugins caches the executable found for each scheme, empty
+// when there is none.
+var backendPlugins sync.Map
+
+// lookupBackendPlugin returns the executable serving a URL scheme.
+func lookupBackendPlugin(scheme string) (string, bool) {
+	scheme = strings.ToLower(scheme)
+	if scheme == "" || scheme == "http" || scheme == "https" {
+		return "", false
+	}
+	if executable, ok := backendPlugins.Load(scheme); ok {
+		return executable.(string), executable.(string) != ""
+	}
+
+	var executable string
+	name := backendPluginPrefix + scheme
+	if configDir, err := getMcConfigDir(); err == nil {
+		if found, e := exec.LookPath(filepath.Join(configDir, globalPluginsDir, name)); e == nil {
+			executable = found
+		}
+	}
+	if executable == "" {
+		if found, e := exec.LookPath(name); e == nil {
+			executable = found
+		}
+	}
+	backendPlugins.Store(scheme, executable)
+	return executable, executable != ""
+}
+
+// isBackendPluginScheme returns true if a plugin serves the URL scheme.
+func isBackendPluginScheme(scheme string) bool {
+	_, ok := lookupBackendPlugin(scheme)
+	return ok
+}
+
+// pluginRequest describes an operation, it is passed to the plugin
+// as JSON in MC_BACKEND_REQUEST.
+type pluginRequest struct {
+	URL            string            `json:"url"`
+	Bucket         string            `json:"bucket,omitempty"`
+	Object         string            `json:"object,omitempty"`
+	Source         string            `json:"source,omitempty"`
+	VersionID      string            `json:"versionId,omitempty"`
+	Recursive      bool              `json:"recursive,omitempty"`
+	Size           int64             `json:"size,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Region         string            `json:"region,omitempty"`
+	IgnoreExisting bool              `json:"ignoreExisting,omitempty"`
+	WithLock       bool              `json:"withLock,omitempty"`
+}
+
+// pluginObject is an object or a folder returned by a plugin, keys
+// start with the bucket name.
+type pluginObject struct {
+	Key      string            `json:"key"`
+	Size     int64             `json:"size"`
+	ModTime  time.Time         `json:"modTime"`
+	ETag     string            `json:"etag,omitempty"`
+	IsDir    bool              `json:"isDir,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// pluginError is written by a failing plugin to stderr as JSON.
+type pluginError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// pluginClient talks to a storage backend through a plugin executable,
+// every operation runs the plugin once.
+type pluginClient struct {
+	executable string
+	targetURL  *ClientURL
+	accessKey  string
+	secretKey  string
+	userAgent  string
+}
+
+// newPluginClient returns a client for urlStr served by a plugin.
+func newPluginClient(executable, urlStr string, hostCfg *aliasConfigV10) Client {
+	return &pluginClient{
+		executable: executable,
+		targetURL:  newClientURL(urlStr),
+		accessKey:  hostCfg.AccessKey,
+		secretKey:  hostCfg.SecretKey,
+		userAgent:  filepath.Base(os.Args[0]) + "/" + ReleaseTag,
+	}
+}
+
+// apiType is shown in errors of unsupported operations.
+func (c *pluginClient) apiType() string {
+	return c.targetURL.Scheme + " backend"
+}
+
+func (c *pluginClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{API: api, APIType: c.apiType()})
+}
+
+// request returns the request for the target URL.
+func (c *pluginClient) request() pluginRequest {
+	bucket, object := url2BucketAndObject(c.targetURL, false)
+	return pluginRequest{URL: c.targetURL.String(), Bucket: bucket, Object: object}
+}
+
+// command prepares a plugin run of an operation.
+func (c *pluginClient) command(ctx context.Context, op string, req pluginRequest) (*exec.Cmd, *bytes.Buffer, *probe.Error) {
+	data, e := json.Marshal(req)
+	if e != nil {
+		return nil, nil, probe.NewError(e)
+	}
+	cmd := exec.CommandContext(ctx, c.executable, op)
+	cmd.Env = append(os.Environ(),
+		"MC_BACKEND_PROTOCOL="+backendPluginProtocol,
+		"MC_BACKEND_REQUEST="+string(data),
+		"MC_BACKEND_ACCESS_KEY="+c.accessKey,
+		"MC_BACKEND_SECRET_KEY="+c.secretKey,
+		"MC_BACKEND_USER_AGENT="+c.userAgent,
+	)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	return cmd, stderr, nil
+}
+
+// run runs an operation to completion.
+func (c *pluginClient) run(ctx context.Context, op string, req pluginRequest, stdin io.Reader, stdout io.Writer) *probe.Error {
+	cmd, stderr, err := c.command(ctx, op, req)
+	if err != nil {
+		return err.Trace(op)
+	}
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	if e := cmd.Run(); e != nil {
+		return c.toError(op, req, e, stderr.Bytes())
+	}
+	return nil
+}
+
+// toError converts a failed plugin run into the matching typed error.
+func (c *pluginClient) toError(op string, req pluginRequest, e error, stderr []byte) *probe.Error {
+	lines := strings.Split(strings.TrimSpace(string(stderr)), "\n")
+	var perr pluginError
+	if json.Unmarshal([]byte(lines[len(lines)-1]), &perr) == nil && perr.Code != "" {
+		switch perr.Code {
+		case "NoSuchKey":
+			return probe.NewError(ObjectMissing{})
+		case "NoSuchBucket":
+			return probe.NewError(BucketDoesNotExist{Bucket: req.Bucket})
+		case "BucketAlreadyExists", "BucketAlreadyOwnedByYou":
+			return probe.NewError(BucketExists{Bucket: req.Bucket})
+		case "NotImplemented":
+			return c.notImplemented(op)
+		}
+		if perr.Message != "" {
+			return probe.NewError(errors.New(perr.Message)).Trace(op, req.URL)
+		}
+		return probe.NewError(errors.New(perr.Code)).Trace(op, req.URL)
+	}
+	if msg := strings.TrimSpace(string(stderr)); msg != "" {
+		return probe.NewError(errors.New(msg)).Trace(op, req.URL)
+	}
+	return probe.NewError(e).Trace(op, req.URL)
+}
+
+// toContent converts an object returned by the plugin.
+func (c *pluginClient) toContent(object pluginObject) *ClientContent {
+	content := &ClientContent{}
+	url := *c.targetURL
+	url.Path = "/" + strings.TrimPrefix(object.Key, "/")
+	content.URL = url
+	content.BucketName, _ = url2BucketAndObject(&url, false)
+	content.Size = object.Size
+	content.Time = object.ModTime
+	content.ETag = object.ETag
+	content.Metadata = object.Metadata
+	content.Type = os.FileMode(0o664)
+	if object.IsDir {
+		if !strings.HasSuffix(content.URL.Path, "/") {
+			content.URL.Path += "/"
+		}
+		content.Type = os.ModeDir
+	}
+	return content
+}
+
+// GetURL get url.
+func (c *pluginClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+// AddUserAgent - add custom user agent passed to the plugin.
+func (c *pluginClient) AddUserAgent(app, version string) {
+	c.userAgent += " " + app + "/" + version
+}
+
+// Stat - get metadata of an object or a folder.
+func (c *pluginClient) Stat(ctx context.Context, opts StatOptions) (*ClientContent, *probe.Error) {
+	req := c.request()
+	if req.Bucket == "" {
+		return &ClientContent{URL: c.GetURL(), Type: os.ModeDir}, nil
+	}
+	if opts.incomplete {
+		return nil, c.notImplemented("StatIncomplete")
+	}
+	req.VersionID = opts.versionID
+	stdout := &bytes.Buffer{}
+	if err := c.run(ctx, "stat", req, nil, stdout); err != nil {
+		return nil, err.Trace(req.URL)
+	}
+	var object pluginObject
+	if e := json.Unmarshal(stdout.Bytes(), &object); e != nil {
+		return nil, probe.NewError(e).Trace(req.URL)
+	}
+	return c.toContent(object), nil
+}
+
+// List - list folders and objects, one JSON object per line is read
+// from the plugin. Plugin backends keep no versions and no incomplete
+// uploads, only current objects are listed.
+func (c *pluginClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		if opts.Incomplete {
+			return
+		}
+
+		req := c.request()
+		req.Recursive = opts.Recursive
+		cmd, stderr, err := c.command(ctx, "list", req)
+		if err != nil {
+			contentCh <- &ClientContent{Err: err.Trace(req.URL)}
+			return
+		}
+		stdout, e := cmd.StdoutPipe()
+		if e == nil {
+			e = cmd.Start()
+		}
+		if e != nil {
+			contentCh <- &ClientContent{Err: probe.NewError(e).Trace(req.URL)}
+			return
+		}
+
+		decoder := json.NewDecoder(stdout)
+		for {
+			var object pluginObject
+			if e = decoder.Decode(&object); e != nil {
+				break
+			}
+			if object.IsDir && opts.Recursive && opts.ShowDir == DirNone {
+				continue
+			}
+			select {
+			case contentCh <- c.toContent(object):
+			case <-ctx.Done():
+				cmd.Wait()
+				return
+			}
+		}
+		if we := cmd.Wait(); we != nil {
+			contentCh <- &ClientContent{Err: c.toError("list", req, we, stderr.Bytes())}
+		} else if e != io.EOF {
+			contentCh <- &ClientContent{Err: probe.NewError(e).Trace(req.URL)}
+		}
+	}()
+	return contentCh
+}
+
+// pluginReader streams the output of a plugin, errors of the plugin
+// are returned at the end of the stream.
+type pluginReader struct {
+	client *pluginClient
+	req    pluginRequest
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	stdout io.ReadCloser
+	reader *bufio.Reader
+	done   bool
+}
+
+func (r *pluginReader) wait() error {
+	r.done = true
+	if e := r.cmd.Wait(); e != nil {
+		return r.client.toError("get", r.req, e, r.stderr.Bytes()).ToGoError()
+	}
+	return nil
+}
+
+func (r *pluginReader) Read(p []byte) (int, error) {
+	n, e := r.reader.Read(p)
+	if e == io.EOF && !r.done {
+		if we := r.wait(); we != nil {
+			return n, we
+		}
+	}
+	return n, e
+}
+
+func (r *pluginReader) Close() error {
+	if r.done {
+		return nil
+	}
+	r.stdout.Close()
+	r.cmd.Process.Kill()
+	r.done = true
+	r.cmd.Wait()
+	return nil
+}
+
+// Get - get object content, read from the plugin output.
+func (c *pluginClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
+	req := c.request()
+	if opts.SSE != nil {
+		return nil, c.notImplemented("GetObjectEncrypted")
+	}
+	req.VersionID = opts.VersionID
+	cmd, stderr, err := c.command(ctx, "get", req)
+	if err != nil {
+		return nil, err.Trace(req.URL)
+	}
+	stdout, e := cmd.StdoutPipe()
+	if e == nil {
+		e = cmd.Start()
+	}
+	if e != nil {
+		return nil, probe.NewError(e).Trace(req.URL)
+	}
+
+	reader := &pluginReader{client: c, req: req, cmd: cmd, stderr: stderr, stdout: stdout, reader: bufio.NewReader(stdout)}
+	// Report a missing object now rather than as a short read.
+	if _, e = reader.reader.Peek(1); e == io.EOF {
+		if e = reader.wait(); e != nil {
+			return nil, probe.NewError(e)
+		}
+	}
+	return reader, nil
+}
+
+// countingReader counts the bytes read.
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, e := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, e
+}
+
+// Put - upload an object, the content is written to the plugin input.
+func (c *pluginClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	req := c.request()
+	if req.Object == "" {
+		return 0, probe.NewError(ObjectNameEmpty{})
+	}
+	if opts.sse != nil {
+		return 0, c.notImplemented("PutObjectEncrypted")
+	}
+	req.Size = size
+	req.Metadata = opts.metadata
+	counter := &countingReader{Reader: hookreader.NewHook(reader, progress)}
+	if err := c.run(ctx, "put", req, counter, nil); err != nil {
+		return counter.n, err.Trace(req.URL)
+	}
+	return counter.n, nil
+}
+
+// Copy - copy an object within the backend, streams it through mc when
+// the plugin cannot copy by itself.
+func (c *pluginClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	req := c.request()
+	req.Source = source
+	req.VersionID = opts.versionID
+	req.Size = opts.size
+	req.Metadata = opts.metadata
+	if opts.srcSSE != nil || opts.tgtSSE != nil {
+		return c.notImplemented("CopyObjectEncrypted")
+	}
+	err := c.run(ctx, "copy", req, nil, nil)
+	if err == nil {
+		if progress != nil {
+			io.CopyN(io.Discard, progress, opts.size)
+		}
+		return nil
+	}
+	if _, ok := err.ToGoError().(APINotImplemented); !ok {
+		return err.Trace(source)
+	}
+
+	sourceURL := *c.targetURL
+	sourceURL.Path = "/" + strings.TrimPrefix(path.Clean(filepath.ToSlash(source)), "/")
+	sourceClnt := &pluginClient{executable: c.executable, targetURL: &sourceURL, accessKey: c.accessKey, secretKey: c.secretKey, userAgent: c.userAgent}
+	reader, err := sourceClnt.Get(ctx, GetOptions{VersionID: opts.versionID})
+	if err != nil {
+		return err.Trace(source)
+	}
+	defer reader.Close()
+	_, err = c.Put(ctx, reader, opts.size, progress, PutOptions{metadata: opts.metadata})
+	return err
+}
+
+// Remove - remove objects, with isRemoveBucket the buckets of the
+// removed objects are removed as well.
+func (c *pluginClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isBypass bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+
+		var prevBucket string
+		removeBucket := func() {
+			if !isRemoveBucket || isIncomplete || prevBucket == "" {
+				return
+			}
+			url := *c.targetURL
+			url.Path = "/" + prevBucket
+			req := pluginRequest{URL: url.String(), Bucket: prevBucket}
+			if err := c.run(ctx, "remove-bucket", req, nil, nil); err != nil {
+				resultCh <- RemoveResult{Err: err.Trace(req.URL)}
+				return
+			}
+			resultCh <- RemoveResult{BucketName: prevBucket}
+		}
+
+		for content := range contentCh {
+			if content.Err != nil {
+				resultCh <- RemoveResult{Err: content.Err}
+				continue
+			}
+			if isIncomplete {
+				resultCh <- RemoveResult{Err: c.notImplemented("RemoveIncompleteUpload")}
+				continue
+			}
+
+			url := content.URL
+			bucket, object := url2BucketAndObject(&url, false)
+			if bucket != prevBucket {
+				removeBucket()
+				prevBucket = bucket
+			}
+			if object == "" {
+				continue
+			}
+
+			req := pluginRequest{URL: url.String(), Bucket: bucket, Object: object, VersionID: content.VersionID}
+			res := RemoveResult{BucketName: bucket}
+			res.ObjectName = object
+			res.ObjectVersionID = content.VersionID
+			if err := c.run(ctx, "remove", req, nil, nil); err != nil {
+				if _, ok := err.ToGoError().(ObjectMissing); ok {
+					// ignore if object already removed.
+					continue
+				}
+				res.Err = err.Trace(req.URL)
+			}
+			resultCh <- res
+		}
+		removeBucket()
+	}()
+	return resultCh
+}
+
+// MakeBucket - create a new bucket.
+func (c *pluginClient) MakeBucket(ctx context.Context, region string, ignoreExisting, withLock bool) *probe.Error {
+	req := c.request()
+	if req.Bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	req.Region = region
+	req.IgnoreExisting = ignoreExisting
+	req.WithLock = withLock
+	return c.run(ctx, "make-bucket", req, nil, nil)
+}
+
+// RemoveBucket - remove a bucket, with all its objects if forced.
+func (c *pluginClient) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Error {
+	req := c.request()
+	if req.Bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	req.Recursive = forceRemove
+	return c.run(ctx, "remove-bucket", req, nil, nil)
+}
+
+// StatBucket - verify the bucket exists, the plugin is asked to stat
+// the bucket itself.
+func (c *pluginClient) StatBucket(ctx context.Context) (*ClientContent, *probe.Error) {
+	req := c.request()
+	if req.Bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	req.Object = ""
+	if err := c.run(ctx, "stat", req, nil, nil); err != nil {
+		return nil, err.Trace(req.URL)
+	}
+	content := &ClientContent{URL: c.GetURL(), BucketName: req.Bucket, Type: os.ModeDir}
+	return content, nil
+}
+
+// SetObjectLockConfig - not implemented for plugin backends.
+func (c *pluginClient) SetObjectLockConfig(ctx context.Context, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit) *probe.Error {
+	return c.notImplemented("SetObjectLockConfig")
+}
+
+// GetObjectLockConfig - not implemented for plugin backends.
+func (c *pluginClient) GetObjectLockConfig(ctx context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	return "", "", 0, "", c.notImplemented("GetObjectLockConfig")
+}
+
+// GetAccess - not implemented for plugin backends.
+func (c *pluginClient) GetAccess(ctx context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetBucketPolicy")
+}
+
+// GetAccessRules - not implemented for plugin backends.
+func (c *pluginClient) GetAccessRules(ctx context.Context) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetBucketPolicy")
+}
+
+// SetAccess - not implemented for plugin backends.
+func (c *pluginClient) SetAccess(ctx context.Context, access string, isJSON bool) *probe.Error {
+	return c.notImplemented("SetBucketPolicy")
+}
+
+// Select - not implemented for plugin backends.
+func (c *pluginClient) Select(ctx context.Context, expression string, sse encrypt.ServerSide, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("Select")
+}
+
+// PutObjectRetention - not implemented for plugin backends.
+func (c *pluginClient) PutObjectRetention(ctx context.Context, versionID string, mode minio.RetentionMode, retainUntilDate time.Time, bypassGovernance bool) *probe.Error {
+	return c.notImplemented("PutObjectRetention")
+}
+
+// GetObjectRetention - not implemented for plugin backends.
+func (c *pluginClient) GetObjectRetention(ctx context.Context, versionID string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, c.notImplemented("GetObjectRetention")
+}
+
+// PutObjectLegalHold - not implemented for plugin backends.
+func (c *pluginClient) PutObjectLegalHold(ctx context.Context, versionID string, hold minio.LegalHoldStatus) *probe.Error {
+	return c.notImplemented("PutObjectLegalHold")
+}
+
+// GetObjectLegalHold - not implemented for plugin backends.
+func (c *pluginClient) GetObjectLegalHold(ctx context.Context, versionID string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", c.notImplemented("GetObjectLegalHold")
+}
+
+// ShareDownload - not implemented for plugin backends.
+func (c *pluginClient) ShareDownload(ctx context.Context, versionID string, expires time.Duration) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+// ShareUpload - not implemented for plugin backends.
+func (c *pluginClient) ShareUpload(ctx context.Context, startsWith bool, expires time.Duration, contentType string, minSize, maxSize int64) (string, map[string]string, *probe.Error) {
+	return "", nil, c.notImplemented("ShareUpload")
+}
+
+// Presign - not implemented for plugin backends.
+func (c *pluginClient) Presign(ctx context.Context, method string, expires time.Duration) (string, *probe.Error) {
+	return "", c.notImplemented("Presign")
+}
+
+// Watch - not implemented for plugin backends.
+func (c *pluginClient) Watch(ctx context.Context, options WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+// GetObjectACL - not implemented for plugin backends.
+func (c *pluginClient) GetObjectACL(ctx context.Context) (string, []ClientGrant, *probe.Error) {
+	return "", nil, c.notImplemented("GetObjectACL")
+}
+
+// GetBucketACL - not implemented for plugin backends.
+func (c *pluginClient) GetBucketACL(ctx context.Context) (string, []ClientGrant, *probe.Error) {
+	return "", nil, c.notImplemented("GetBucketACL")
+}
+
+// SetBucketACL - not implemented for plugin backends.
+func (c *pluginClient) SetBucketACL(ctx context.Context, acl string) *probe.Error {
+	return c.notImplemented("SetBucketACL")
+}
+
+// GetTags - not implemented for plugin backends.
+func (c *pluginClient) GetTags(ctx context.Context, versionID string) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetObjectTagging")
+}
+
+// SetTags - not implemented for plugin backends.
+func (c *pluginClient) SetTags(ctx context.Context, versionID, tags string) *probe.Error {
+	return c.notImplemented("PutObjectTagging")
+}
+
+// DeleteTags - not implemented for plugin backends.
+func (c *pluginClient) DeleteTags(ctx context.Context, versionID string) *probe.Error {
+	return c.notImplemented("DeleteObjectTagging")
+}
+
+// GetLifecycle - not implemented for plugin backends.
+func (c *pluginClient) GetLifecycle(ctx context.Context) (*lifecycle.Configuration, *probe.Error) {
+	return nil, c.notImplemented("GetLifecycle")
+}
+
+// SetLifecycle - not implemented for plugin backends.
+func (c *pluginClient) SetLifecycle(ctx context.Context, config *lifecycle.Configuration) *probe.Error {
+	return c.notImplemented("SetLifecycle")
+}
+
+// GetVersion - not implemented for plugin backends.
+func (c *pluginClient) GetVersion(ctx context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, c.notImplemented("GetVersion")
+}
+
+// SetVersion - not implemented for plugin backends.
+func (c *pluginClient) SetVersion(ctx context.Context, status string) *probe.Error {
+	return c.notImplemented("SetVersion")
+}
+
+// GetReplication - not implemented for plugin backends.
+func (c *pluginClient) GetReplication(ctx context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, c.notImplemented("GetReplication")
+}
+
+// SetReplication - not implemented for plugin backends.
+func (c *pluginClient) SetReplication(ctx context.Context, cfg *replication.Config, opts replication.Options) *probe.Error {
+	return c.notImplemented("SetReplication")
+}
+
+// RemoveReplication - not implemented for plugin backends.
+func (c *pluginClient) RemoveReplication(ctx context.Context) *probe.Error {
+	return c.notImplemented("RemoveReplication")
+}
+
+// GetReplicationMetrics - not implemented for plugin backends.
+func (c *pluginClient) GetReplicationMetrics(ctx context.Context) (replication.Metrics, *probe.Error) {
+	return replication.Metrics{}, c.notImplemented("GetReplicationMetrics")
+}
+
+// ResetReplication - not implemented for plugin backends.
+func (c *pluginClient) ResetReplication(ctx context.Context, before time.Duration, arn string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ResetReplication")
+}
+
+// GetEncryption - not implemented for plugin backends.
+func (c *pluginClient) GetEncryption(ctx context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetEncryption")
+}
+
+// SetEncryption - not implemented for plugin backends.
+func (c *pluginClient) SetEncryption(ctx context.Context, algorithm, kmsKeyID string) *probe.Error {
+	return c.notImplemented("SetEncryption")
+}
+
+// DeleteEncryption - not implemented for plugin backends.
+func (c *pluginClient) DeleteEncryption(ctx context.Context) *probe.Error {
+	return c.notImplemented("DeleteEncryption")
+}
+
+// GetBucketInfo - not implemented for plugin backends.
+func (c *pluginClient) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
+	return BucketInfo{}, c.notImplemented("GetBucketInfo")
+}
+
+// DiskUsage - sums the objects listed by the plugin.
+func (c *pluginClient) DiskUsage(ctx context.Context, timeRef time.Time, withVersions bool) (DiskUsageInfo, *probe.Error) {
+	return listDiskUsage(ctx, c, ListOptions{TimeRef: timeRef, WithOlderVersions: withVersions})
+}
+
+// Restore - not implemented for plugin backends.
+func (c *pluginClient) Restore(ctx context.Context, versionID string, days int) *probe.Error {
+	return c.notImplemented("Restore")
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestPluginHelperProcess is not a real test, it serves as a backend
+// plugin storing objects in a local folder.
+func TestPluginHelperProcess(t *testing.T) {
+	root := os.Getenv("MC_TEST_PLUGIN_ROOT")
+	if root == "" {
+		return
+	}
+	var req pluginRequest
+	if e := json.Unmarshal([]byte(os.Getenv("MC_BACKEND_REQUEST")), &req); e != nil {
+		fmt.Fprintln(os.Stderr, e)
+		os.Exit(1)
+	}
+	fail := func(code string) {
+		fmt.Fprintf(os.Stderr, `{"code":%q}`+"\n", code)
+		os.Exit(1)
+	}
+	toObject := func(path string, fi os.FileInfo) pluginObject {
+		key, _ := filepath.Rel(root, path)
+		return pluginObject{Key: filepath.ToSlash(key), Size: fi.Size(), ModTime: fi.ModTime(), IsDir: fi.IsDir()}
+	}
+
+	path := filepath.Join(root, req.Bucket, req.Object)
+	op := os.Args[len(os.Args)-1]
+	switch op {
+	case "stat":
+		fi, e := os.Stat(path)
+		if e != nil {
+			fail("NoSuchKey")
+		}
+		json.NewEncoder(os.Stdout).Encode(toObject(path, fi))
+	case "list":
+		enc := json.NewEncoder(os.Stdout)
+		filepath.Walk(path, func(p string, fi os.FileInfo, e error) error {
+			if e != nil || (p == path && fi.IsDir()) {
+				return e
+			}
+			if !fi.IsDir() || !req.Recursive {
+				enc.Encode(toObject(p, fi))
+			}
+			if fi.IsDir() && !req.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	case "get":
+		f, e := os.Open(path)
+		if e != nil {
+			fail("NoSuchKey")
+		}
+		io.Copy(os.Stdout, f)
+	case "put":
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		data, _ := ioutil.ReadAll(os.Stdin)
+		ioutil.WriteFile(path, data, 0o644)
+	case "remove":
+		if os.Remove(path) != nil {
+			fail("NoSuchKey")
+		}
+	case "make-bucket":
+		if os.Mkdir(path, 0o755) != nil && !req.IgnoreExisting {
+			fail("BucketAlreadyExists")
+		}
+	default:
+		fail("NotImplemented")
+	}
+	os.Exit(0)
+}
+
+func TestPluginClient(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin script requires a POSIX shell")
+	}
+	pluginDir, root := t.TempDir(), t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nMC_TEST_PLUGIN_ROOT=%q exec %q -test.run=TestPluginHelperProcess -- \"$@\"\n", root, os.Args[0])
+	if e := ioutil.WriteFile(filepath.Join(pluginDir, backendPluginPrefix+"mctest"), []byte(script), 0o755); e != nil {
+		t.Fatal(e)
+	}
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	defer backendPlugins.Delete("mctest")
+
+	executable, ok := lookupBackendPlugin("mctest")
+	if !ok {
+		t.Fatal("expected the plugin to be found in the PATH")
+	}
+	if url := newClientURL("mctest://vault/bucket/object"); url.Type != objectStorage || url.Host != "vault" {
+		t.Fatalf("expected an object storage URL, got %#v", url)
+	}
+
+	ctx := context.Background()
+	newPluginTestClient := func(urlStr string) Client {
+		return newPluginClient(executable, urlStr, &aliasConfigV10{URL: "mctest://vault"})
+	}
+
+	if err := newPluginTestClient("mctest://vault/bucket").MakeBucket(ctx, "", false, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := newPluginTestClient("mctest://vault/bucket").MakeBucket(ctx, "", false, false); err == nil {
+		t.Fatal("expected an existing bucket error")
+	} else if _, ok := err.ToGoError().(BucketExists); !ok {
+		t.Fatalf("expected BucketExists, got %v", err)
+	}
+
+	clnt := newPluginTestClient("mctest://vault/bucket/dir/object")
+	data := "hello plugin"
+	if n, err := clnt.Put(ctx, strings.NewReader(data), int64(len(data)), nil, PutOptions{}); err != nil || n != int64(len(data)) {
+		t.Fatalf("unexpected put result %d, %v", n, err)
+	}
+
+	content, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Size != int64(len(data)) || content.URL.String() != "mctest://vault/bucket/dir/object" {
+		t.Fatalf("unexpected stat result %d %s", content.Size, content.URL.String())
+	}
+
+	reader, err := clnt.Get(ctx, GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, e := io.Copy(&buf, reader); e != nil || buf.String() != data {
+		t.Fatalf("unexpected get result %q, %v", buf.String(), e)
+	}
+	reader.Close()
+
+	var keys []string
+	for content := range newPluginTestClient("mctest://vault/bucket").List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			t.Fatal(content.Err)
+		}
+		keys = append(keys, content.URL.Path)
+	}
+	if len(keys) != 1 || keys[0] != "/bucket/dir/object" {
+		t.Fatalf("unexpected listing %v", keys)
+	}
+
+	if _, err = newPluginTestClient("mctest://vault/bucket/missing").Get(ctx, GetOptions{}); err == nil {
+		t.Fatal("expected a missing object error")
+	} else if _, ok := err.ToGoError().(ObjectMissing); !ok {
+		t.Fatalf("expected ObjectMissing, got %v", err)
+	}
+	if _, err = clnt.GetTags(ctx, ""); err == nil {
+		t.Fatal("expected tags to be unsupported")
+	}
+
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- content
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, contentCh) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+	}
+	if _, err = clnt.Stat(ctx, StatOptions{}); err == nil {
+		t.Fatal("expected the object to be removed")
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// Folder in the config folder holding the state of resumable uploads.
+const globalUploadsDir = "uploads"
+
+// States of uploads not resumed for this long are dropped, the uploads
+// are usually aborted by the server meanwhile.
+const uploadStateExpiry = 7 * 24 * time.Hour
+
+const (
+	// Parts uploaded at a time unless MC_UPLOAD_MULTIPART_THREADS is set.
+	defaultMultipartThreads = 4
+	// Attempts to upload a part before failing the upload.
+	maxPartAttempts = 3
+)
+
+// uploadState is the progress of a resumable multipart upload, saved
+// after each uploaded part. Parts are listed in the order they were
+// uploaded.
+type uploadState struct {
+	Version  string            `json:"version"`
+	Target   string            `json:"target"`
+	UploadID string            `json:"uploadId"`
+	Size     int64             `json:"size"`
+	PartSize int64             `json:"partSize"`
+	Updated  time.Time         `json:"updated"`
+	Parts    []uploadStatePart `json:"parts"`
+}
+
+// uploadStatePart is an uploaded part, SHA256 is the sum of the data
+// sent which, unlike the ETag, is known for encrypted parts and in FIPS
+// mode.
+type uploadStatePart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+	SHA256     string `json:"sha256"`
+	Size       int64  `json:"size"`
+}
+
+func getUploadStateFile(target string, size int64) (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	sum := sha256.Sum256([]byte(target + "\x00" + strconv.FormatInt(size, 10)))
+	return filepath.Join(configDir, globalUploadsDir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// loadUploadState reads the state of an upload, it returns nil if there
+// is none.
+func loadUploadState(path string) *uploadState {
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil
+	}
+	state := &uploadState{}
+	if e = json.Unmarshal(data, state); e != nil || state.UploadID == "" {
+		return nil
+	}
+	return state
+}
+
+// expired returns true if the upload was not resumed for too long.
+func (s *uploadState) expired() bool {
+	return time.Since(s.Updated) > uploadStateExpiry
+}
+
+// save atomically replaces the state file of the upload.
+func (s *uploadState) save(path string) error {
+	s.Updated = UTCNow()
+	if e := os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return e
+	}
+	data, e := json.Marshal(s)
+	if e != nil {
+		return e
+	}
+	tmpPath := path + ".tmp"
+	if e = ioutil.WriteFile(tmpPath, data, 0o600); e != nil {
+		return e
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// verifiedParts returns the parts of the saved state which the server
+// still has and which match the local data, ok is false if the upload
+// does not exist anymore.
+func (c *S3Client) verifiedParts(ctx context.Context, bucket, object string, reader io.ReaderAt, state *uploadState) (verified []uploadStatePart, ok bool) {
+	core := minio.Core{Client: c.api}
+	uploaded := make(map[int]minio.ObjectPart)
+	marker := 0
+	for {
+		result, e := core.ListObjectParts(ctx, bucket, object, state.UploadID, marker, 1000)
+		if e != nil {
+			return nil, false
+		}
+		for _, part := range result.ObjectParts {
+			uploaded[part.PartNumber] = part
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	buf := make([]byte, state.PartSize)
+	for _, part := range state.Parts {
+		remote, found := uploaded[part.PartNumber]
+		if !found || remote.Size != part.Size || part.Size > state.PartSize || strings.Trim(remote.ETag, "\"") != part.ETag {
+			continue
+		}
+		n, e := reader.ReadAt(buf[:part.Size], int64(part.PartNumber-1)*state.PartSize)
+		if int64(n) != part.Size || (e != nil && e != io.EOF) {
+			continue
+		}
+		if sum := sha256.Sum256(buf[:n]); hex.EncodeToString(sum[:]) != part.SHA256 {
+			continue
+		}
+		verified = append(verified, part)
+	}
+	return verified, true
+}
+
+// putPart uploads a part, retrying a few times if the server is busy
+// or unreachable. The progress is advanced once the part is uploaded.
+// Content-MD5 is not sent in FIPS mode, the SHA-256 sum of the part is.
+func putPart(ctx context.Context, core minio.Core, bucket, object, uploadID string, partNumber int, data []byte, progress io.Reader, sse encrypt.ServerSide) (uploadStatePart, error) {
+	sum := sha256.Sum256(data)
+	var md5Base64 string
+	if !globalFIPS {
+		md5Sum := md5.Sum(data)
+		md5Base64 = base64.StdEncoding.EncodeToString(md5Sum[:])
+	}
+	for attempt := 1; ; attempt++ {
+		part, e := core.PutObjectPart(ctx, bucket, object, uploadID, partNumber, bytes.NewReader(data), int64(len(data)),
+			md5Base64, hex.EncodeToString(sum[:]), sse)
+		if e == nil {
+			if progress != nil {
+				io.CopyN(ioutil.Discard, progress, int64(len(data)))
+			}
+			return uploadStatePart{
+				PartNumber: partNumber,
+				ETag:       strings.Trim(part.ETag, "\""),
+				SHA256:     hex.EncodeToString(sum[:]),
+				Size:       int64(len(data)),
+			}, nil
+		}
+		if attempt == maxPartAttempts || !isHostFailure(e) {
+			return uploadStatePart{}, e
+		}
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return uploadStatePart{}, ctx.Err()
+		}
+	}
+}
+
+// putMultipart uploads reader in parts of partSize, uploading threads
+// parts at a time. Resumable uploads read reader as an io.ReaderAt and
+// save their state after each part, an interrupted upload of the same
+// size to the same target then resumes with the parts missing or not
+// matching the local data. Other uploads read reader sequentially and
+// are aborted on failure.
+func (c *S3Client) putMultipart(ctx context.Context, bucket, object string, reader io.Reader, size, partSize int64, threads int, progress io.Reader, opts minio.PutObjectOptions, resumable bool) (ui minio.UploadInfo, err error) {
+	ui = minio.UploadInfo{Bucket: bucket, Key: object}
+	core := minio.Core{Client: c.api}
+	readerAt, _ := reader.(io.ReaderAt)
+
+	var state *uploadState
+	var statePath string
+	target := c.targetURL.String()
+	if resumable {
+		path, perr := getUploadStateFile(target, size)
+		if perr != nil {
+			return ui, perr.ToGoError()
+		}
+		statePath = path
+		state = loadUploadState(statePath)
+		if state != nil && (state.expired() || state.Target != target || state.Size != size || state.PartSize != partSize) {
+			// The upload cannot be resumed, abort it instead of leaving
+			// its parts on the server.
+			if state.Target == target {
+				core.AbortMultipartUpload(ctx, bucket, object, state.UploadID)
+			}
+			os.Remove(statePath)
+			state = nil
+		}
+		if state != nil {
+			verified, ok := c.verifiedParts(ctx, bucket, object, readerAt, state)
+			if ok {
+				state.Parts = verified
+			} else {
+				state = nil
+			}
+		}
+	}
+	if state == nil {
+		uploadID, e := core.NewMultipartUpload(ctx, bucket, object, opts)
+		if e != nil {
+			return ui, e
+		}
+		state = &uploadState{Version: "2", Target: target, UploadID: uploadID, Size: size, PartSize: partSize}
+		if resumable {
+			if e = state.save(statePath); e != nil {
+				errorIf(probe.NewError(e), "Unable to save the state of the upload, it cannot be resumed.")
+			}
+		} else {
+			defer func() {
+				if err != nil {
+					core.AbortMultipartUpload(context.Background(), bucket, object, uploadID)
+				}
+			}()
+		}
+	}
+
+	uploaded := make(map[int]bool, len(state.Parts))
+	for _, part := range state.Parts {
+		uploaded[part.PartNumber] = true
+		ui.Size += part.Size
+	}
+	if progress != nil {
+		io.CopyN(ioutil.Discard, progress, ui.Size)
+	}
+
+	// Only SSE-C keys are sent with each part.
+	var sse encrypt.ServerSide
+	if opts.ServerSideEncryption != nil && opts.ServerSideEncryption.Type() == encrypt.SSEC {
+		sse = opts.ServerSideEncryption
+	}
+	if threads <= 0 {
+		threads = defaultMultipartThreads
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mu sync.Mutex
+	var firstErr error
+	fail := func(e error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = e
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	// Parts are read in order, each worker uploading one part from its
+	// own buffer at a time.
+	type partJob struct {
+		number int
+		data   []byte
+	}
+	jobs := make(chan partJob)
+	buffers := make(chan []byte, threads)
+	for i := 0; i < threads; i++ {
+		buffers <- make([]byte, partSize)
+	}
+	go func() {
+		defer close(jobs)
+		totalParts := int((size + partSize - 1) / partSize)
+		for number := 1; number <= totalParts; number++ {
+			if uploaded[number] {
+				continue
+			}
+			var buf []byte
+			select {
+			case buf = <-buffers:
+			case <-ctx.Done():
+				return
+			}
+			offset := int64(number-1) * partSize
+			data := buf
+			if size-offset < partSize {
+				data = buf[:size-offset]
+			}
+			var n int
+			var e error
+			if resumable {
+				n, e = readerAt.ReadAt(data, offset)
+			} else {
+				n, e = io.ReadFull(reader, data)
+			}
+			if n != len(data) {
+				if e == nil || e == io.EOF {
+					e = io.ErrUnexpectedEOF
+				}
+				fail(e)
+				return
+			}
+			select {
+			case jobs <- partJob{number: number, data: data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				part, e := putPart(ctx, core, bucket, object, state.UploadID, job.number, job.data, progress, sse)
+				buffers <- job.data[:cap(job.data)]
+				if e != nil {
+					fail(e)
+					continue
+				}
+				mu.Lock()
+				state.Parts = append(state.Parts, part)
+				ui.Size += part.Size
+				if resumable {
+					if e = state.save(statePath); e != nil {
+						errorIf(probe.NewError(e), "Unable to save the state of the upload, it cannot be resumed.")
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return ui, firstErr
+	}
+
+	sort.Slice(state.Parts, func(i, j int) bool {
+		return state.Parts[i].PartNumber < state.Parts[j].PartNumber
+	})
+	parts := make([]minio.CompletePart, 0, len(state.Parts))
+	for _, part := range state.Parts {
+		parts = append(parts, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	etag, e := core.CompleteMultipartUpload(ctx, bucket, object, state.UploadID, parts, opts)
+	if e != nil {
+		return ui, e
+	}
+	if resumable {
+		os.Remove(statePath)
+	}
+	ui.ETag = etag
+	return ui, nil
+}
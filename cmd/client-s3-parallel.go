@@ -0,0 +1,168 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// Default part size used for parallel downloads.
+const defaultParallelGetPartSize = 16 * 1024 * 1024
+
+var errParallelGetClosed = errors.New("read on a closed parallel download")
+
+// parallelGetPart - result of a single ranged GET.
+type parallelGetPart struct {
+	data []byte
+	err  error
+}
+
+// parallelGetReader downloads an object with concurrent ranged GET
+// requests and returns the parts in order. At most `concurrency` parts
+// are held in memory at any time.
+type parallelGetReader struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	parts  []chan parallelGetPart
+	slots  chan struct{}
+	index  int
+	buf    []byte
+	err    error
+}
+
+// newParallelGetReader starts fetching all parts of the object described
+// by info, the caller must close the reader to release the workers.
+func newParallelGetReader(ctx context.Context, api *minio.Client, bucket, object string, info minio.ObjectInfo, sse encrypt.ServerSide, concurrency int, partSize int64) *parallelGetReader {
+	ctx, cancel := context.WithCancel(ctx)
+	numParts := int((info.Size + partSize - 1) / partSize)
+	r := &parallelGetReader{
+		ctx:    ctx,
+		cancel: cancel,
+		parts:  make([]chan parallelGetPart, numParts),
+		slots:  make(chan struct{}, concurrency),
+	}
+	for i := range r.parts {
+		r.parts[i] = make(chan parallelGetPart, 1)
+	}
+
+	go func() {
+		for i := range r.parts {
+			select {
+			case r.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			start := int64(i) * partSize
+			end := start + partSize - 1
+			if end >= info.Size {
+				end = info.Size - 1
+			}
+			go func(partCh chan<- parallelGetPart, start, end int64) {
+				partCh <- fetchObjectRange(ctx, api, bucket, object, info, sse, start, end)
+			}(r.parts[i], start, end)
+		}
+	}()
+	return r
+}
+
+// fetchObjectRange - reads bytes start to end inclusive of an object.
+func fetchObjectRange(ctx context.Context, api *minio.Client, bucket, object string, info minio.ObjectInfo, sse encrypt.ServerSide, start, end int64) parallelGetPart {
+	opts := minio.GetObjectOptions{
+		ServerSideEncryption: sse,
+		VersionID:            info.VersionID,
+	}
+	// Every part must come from the same version of the object.
+	if e := opts.SetMatchETag(info.ETag); e != nil {
+		return parallelGetPart{err: e}
+	}
+	if e := opts.SetRange(start, end); e != nil {
+		return parallelGetPart{err: e}
+	}
+	obj, e := api.GetObject(ctx, bucket, object, opts)
+	if e != nil {
+		return parallelGetPart{err: e}
+	}
+	defer obj.Close()
+	data := make([]byte, end-start+1)
+	if _, e = io.ReadFull(obj, data); e != nil {
+		return parallelGetPart{err: e}
+	}
+	return parallelGetPart{data: data}
+}
+
+// Read - implements io.Reader, parts are returned in order.
+func (r *parallelGetReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	for len(r.buf) == 0 {
+		if r.index == len(r.parts) {
+			r.err = io.EOF
+			return 0, r.err
+		}
+		// Parts are not started anymore once ctx is done.
+		var part parallelGetPart
+		select {
+		case part = <-r.parts[r.index]:
+		case <-r.ctx.Done():
+			r.err = r.ctx.Err()
+			return 0, r.err
+		}
+		r.index++
+		// Free the slot so that the next part can be fetched.
+		<-r.slots
+		if part.err != nil {
+			r.err = part.err
+			return 0, r.err
+		}
+		r.buf = part.data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close - stops all pending part downloads.
+func (r *parallelGetReader) Close() error {
+	r.cancel()
+	r.err = errParallelGetClosed
+	return nil
+}
+
+// getParallel - serves Get with concurrent ranged requests when the
+// object is larger than a single part.
+func (c *S3Client) getParallel(ctx context.Context, bucket, object string, opts GetOptions) (io.ReadCloser, error) {
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultParallelGetPartSize
+	}
+	getOpts := getObjectOptions(opts)
+	info, e := c.api.StatObject(ctx, bucket, object, getOpts)
+	if e != nil {
+		return nil, e
+	}
+	if info.Size <= partSize {
+		return c.api.GetObject(ctx, bucket, object, getOpts)
+	}
+	return newParallelGetReader(ctx, c.api, bucket, object, info, opts.SSE, opts.Parallel, partSize), nil
+}
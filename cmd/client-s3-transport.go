@@ -0,0 +1,184 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+// Uploads of at least this size, or of unknown size, wait for the
+// server to accept the request before sending their body.
+const expectContinueMinSize = 1024 * 1024
+
+// expectContinueTransport sends large PUT requests with Expect:
+// 100-continue, requests failing authentication or permission checks
+// are then rejected without transmitting the body.
+type expectContinueTransport struct {
+	transport http.RoundTripper
+}
+
+func (t expectContinueTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut && req.Body != nil && req.Body != http.NoBody &&
+		(req.ContentLength < 0 || req.ContentLength >= expectContinueMinSize) {
+		r := req.Clone(req.Context())
+		r.Header.Set("Expect", "100-continue")
+		req = r
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// bucketRegionTransport follows the redirects of buckets to their
+// region. The region reported in the x-amz-bucket-region header of a
+// 301 or 307 response is cached per bucket, the request is signed again
+// for it and resent, later requests to the bucket are signed for it
+// before they are sent. Requests with a body which cannot be read again
+// are not resent, they fail but later requests find the region.
+type bucketRegionTransport struct {
+	transport http.RoundTripper
+	endpoint  string
+	creds     *credentials.Credentials
+
+	mutex   sync.Mutex
+	regions map[string]string
+}
+
+func newBucketRegionTransport(transport http.RoundTripper, endpoint string, creds *credentials.Credentials) *bucketRegionTransport {
+	return &bucketRegionTransport{
+		transport: transport,
+		endpoint:  endpoint,
+		creds:     creds,
+		regions:   make(map[string]string),
+	}
+}
+
+func (t *bucketRegionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bucket, host := t.bucketOf(req)
+	signedRegion := signedRegionV4(req)
+	// Requests signed with V2, presigned, anonymous or streaming
+	// requests are sent as they are.
+	if bucket == "" || signedRegion == "" ||
+		strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return t.transport.RoundTrip(req)
+	}
+
+	t.mutex.Lock()
+	region, found := t.regions[bucket]
+	t.mutex.Unlock()
+	if found && region != signedRegion {
+		r, e := t.sign(req, req.Body, bucket, host, region)
+		if e != nil {
+			return nil, e
+		}
+		req, signedRegion = r, region
+	}
+
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil || (resp.StatusCode != http.StatusMovedPermanently && resp.StatusCode != http.StatusTemporaryRedirect) {
+		return resp, e
+	}
+	region = resp.Header.Get("x-amz-bucket-region")
+	if region == "" || region == signedRegion {
+		return resp, nil
+	}
+	t.mutex.Lock()
+	t.regions[bucket] = region
+	t.mutex.Unlock()
+
+	body := req.Body
+	if body != nil && body != http.NoBody {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		if body, e = req.GetBody(); e != nil {
+			return resp, nil
+		}
+	}
+	r, e := t.sign(req, body, bucket, host, region)
+	if e != nil {
+		return resp, nil
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	return t.transport.RoundTrip(r)
+}
+
+// bucketOf returns the bucket of the request and its host without the
+// bucket, buckets are found in the host of virtual host style requests.
+func (t *bucketRegionTransport) bucketOf(req *http.Request) (bucket, host string) {
+	host = req.URL.Host
+	if host == t.endpoint || isAmazon(host) {
+		bucket = strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)[0]
+		return bucket, host
+	}
+	if strings.HasSuffix(host, "."+t.endpoint) {
+		return strings.TrimSuffix(host, "."+t.endpoint), t.endpoint
+	}
+	if i := strings.Index(host, "."); i > 0 && isAmazon(host[i+1:]) {
+		return host[:i], host[i+1:]
+	}
+	return "", host
+}
+
+// sign returns a copy of the request with body signed for region, AWS
+// endpoints are replaced with the endpoint of the region.
+func (t *bucketRegionTransport) sign(req *http.Request, body io.ReadCloser, bucket, host, region string) (*http.Request, error) {
+	value, e := t.creds.Get()
+	if e != nil {
+		return nil, e
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	if u := (url.URL{Host: host}); isAmazon(host) && !s3utils.IsAmazonFIPSEndpoint(u) && !s3utils.IsAmazonPrivateLinkEndpoint(u) {
+		if hostRegion := s3utils.GetRegionFromURL(u); hostRegion != "" {
+			regionHost := strings.Replace(host, "."+hostRegion+".", "."+region+".", 1)
+			r.URL.Host = strings.TrimSuffix(r.URL.Host, host) + regionHost
+			r.Host = ""
+		}
+	}
+	r.Header.Del("Authorization")
+	return signer.SignV4(*r, value.AccessKeyID, value.SecretAccessKey, value.SessionToken, region), nil
+}
+
+// signedRegionV4 returns the region of the V4 signature in the
+// Authorization header of the request.
+func signedRegionV4(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return ""
+	}
+	i := strings.Index(auth, "Credential=")
+	if i < 0 {
+		return ""
+	}
+	credential := strings.SplitN(auth[i+len("Credential="):], ",", 2)[0]
+	// The credential is access-key/date/region/service/aws4_request.
+	fields := strings.Split(credential, "/")
+	if len(fields) < 5 {
+		return ""
+	}
+	return fields[len(fields)-3]
+}
@@ -20,8 +20,11 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"hash/fnv"
@@ -39,6 +42,7 @@ import (
 	"time"
 
 	"github.com/minio/mc/pkg/httptracer"
+	"github.com/minio/mc/pkg/limiter"
 	"github.com/minio/mc/pkg/probe"
 	minio "github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -50,7 +54,9 @@ import (
 	"github.com/minio/minio-go/v7/pkg/sse"
 
 	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/minio-go/v7/pkg/signer"
 	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/pkg/console"
 	"github.com/minio/pkg/mimedb"
 )
 
@@ -60,6 +66,16 @@ type S3Client struct {
 	targetURL    *ClientURL
 	api          *minio.Client
 	virtualStyle bool
+	// Object listing API, "v1", "v2" or empty to detect it.
+	listAPI string
+
+	// Set if bucket names are not validated with AWS rules.
+	relaxedNames bool
+
+	// Configuration and transport of requests that minio-go does not
+	// expose, such as bucket ACLs and buckets that minio-go refuses.
+	config    *Config
+	transport http.RoundTripper
 }
 
 const (
@@ -90,13 +106,31 @@ const (
 
 var timeSentinel = time.Unix(0, 0).UTC()
 
+var setRetryOnce sync.Once
+
+// setRetry applies --max-retry and --retry-delay to minio-go, whose retry
+// settings are package wide. They are set once, by the first client created
+// after the flags of the command were parsed.
+func setRetry() {
+	setRetryOnce.Do(func() {
+		minio.MaxRetry = globalMaxRetry
+		minio.DefaultRetryUnit = globalRetryDelay
+		if minio.DefaultRetryCap < globalRetryDelay {
+			minio.DefaultRetryCap = globalRetryDelay
+		}
+	})
+}
+
 // newFactory encloses New function with client cache.
 func newFactory() func(config *Config) (Client, *probe.Error) {
 	clientCache := make(map[uint32]*minio.Client)
+	transportCache := make(map[uint32]http.RoundTripper)
 	var mutex sync.Mutex
 
 	// Return New function.
 	return func(config *Config) (Client, *probe.Error) {
+		setRetry()
+
 		// Creates a parsed URL.
 		targetURL := newClientURL(config.HostURL)
 		// By default enable HTTPs.
@@ -113,6 +147,7 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		// Save if target supports virtual host style.
 		hostName := targetURL.Host
 		s3Clnt.virtualStyle = isVirtualHostStyle(hostName, config.Lookup)
+		s3Clnt.listAPI = strings.ToLower(config.ListAPI)
 		isS3AcceleratedEndpoint := isAmazonAccelerated(hostName)
 
 		if s3Clnt.virtualStyle {
@@ -121,9 +156,20 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				hostName = googleHostName
 			}
 		}
+		// Dotted bucket names do not match the wildcard certificates
+		// of virtual hosts, such buckets are addressed in path style.
+		lookup := config.Lookup
+		if lookup == minio.BucketLookupDNS && useTLS {
+			bucket, _ := url2BucketAndObject(targetURL, s3Clnt.virtualStyle)
+			if isDottedBucket(bucket, targetURL.Host) {
+				lookup = minio.BucketLookupPath
+			}
+		}
+
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken + config.Socket))
+		confHash.Write([]byte{byte(lookup)})
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -145,15 +191,15 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				transport = config.Transport
 			} else {
 				tr := &http.Transport{
-					Proxy: http.ProxyFromEnvironment,
-					DialContext: (&net.Dialer{
+					Proxy: getProxyFunc(http.ProxyFromEnvironment),
+					DialContext: newSocketDialContext(config.Socket, &net.Dialer{
 						Timeout:   10 * time.Second,
 						KeepAlive: 15 * time.Second,
-					}).DialContext,
+					}),
 					MaxIdleConnsPerHost:   256,
 					IdleConnTimeout:       90 * time.Second,
 					TLSHandshakeTimeout:   10 * time.Second,
-					ExpectContinueTimeout: 10 * time.Second,
+					ExpectContinueTimeout: 1 * time.Second,
 					// Set this value so that the underlying transport round-tripper
 					// doesn't try to auto decode the body of objects with
 					// content-encoding set to `gzip`.
@@ -165,14 +211,18 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				if useTLS {
 					// Keep TLS config.
 					tlsConfig := &tls.Config{
-						RootCAs: globalRootCAs,
+						RootCAs:      globalRootCAs,
+						Certificates: globalClientCerts,
 						// Can't use SSLv3 because of POODLE and BEAST
 						// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 						// Can't use TLSv1.1 because of RC4 cipher usage
-						MinVersion: tls.VersionTLS12,
+						MinVersion:       tls.VersionTLS12,
+						CipherSuites:     tlsCipherSuites(),
+						CurvePreferences: tlsCurvePreferences(),
 					}
 					if config.Insecure {
 						tlsConfig.InsecureSkipVerify = true
+						warnInsecure(hostName)
 					}
 					tr.TLSClientConfig = tlsConfig
 
@@ -196,6 +246,15 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				}
 			}
 
+			// Limit the bandwidth of all clients together.
+			transport = limiter.NewTransport(transport, globalLimitUpload, globalLimitDownload)
+
+			// Let the server reject large uploads before their body is sent.
+			transport = expectContinueTransport{transport: transport}
+
+			// Follow buckets redirected to their region.
+			transport = newBucketRegionTransport(transport, hostName, creds)
+
 			// Not found. Instantiate a new MinIO
 			var e error
 
@@ -203,7 +262,7 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				Creds:        creds,
 				Secure:       useTLS,
 				Region:       os.Getenv("MC_REGION"),
-				BucketLookup: config.Lookup,
+				BucketLookup: lookup,
 				Transport:    transport,
 			}
 
@@ -222,15 +281,34 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 			// Cache the new MinIO Client with hash of config as key.
 			clientCache[confSum] = api
+			transportCache[confSum] = transport
 		}
 
 		// Store the new api object.
 		s3Clnt.api = api
+		s3Clnt.config = config
+		s3Clnt.transport = transportCache[confSum]
+
+		// AWS endpoints always validate bucket names strictly.
+		if config.RelaxedBucketNames && !isAmazon(hostName) && !isGoogle(hostName) {
+			s3Clnt.relaxedNames = true
+		}
 
 		return s3Clnt, nil
 	}
 }
 
+// newSocketDialContext returns a dial function connecting to the unix
+// domain socket instead of the requested address, if socket is set.
+func newSocketDialContext(socket string, dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if socket == "" {
+		return newDialContext(dialer)
+	}
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+}
+
 // S3New returns an initialized S3Client structure. If debug is enabled,
 // it also enables an internal trace transport.
 var S3New = newFactory()
@@ -812,13 +890,26 @@ func (c *S3Client) Watch(ctx context.Context, options WatchOptions) (*WatchObjec
 func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 
-	reader, e := c.api.GetObject(ctx, bucket, object,
-		minio.GetObjectOptions{
-			ServerSideEncryption: opts.SSE,
-			VersionID:            opts.VersionID,
-		})
+	var reader io.ReadCloser
+	var e error
+	if opts.Parallel > 1 {
+		reader, e = c.getParallel(ctx, bucket, object, opts)
+	} else {
+		var obj *minio.Object
+		obj, e = c.api.GetObject(ctx, bucket, object, getObjectOptions(opts))
+		if e == nil && (opts.IfNoneMatch != "" || !opts.IfModifiedSince.IsZero()) {
+			// Send the request now to report unmodified objects.
+			if _, e = obj.Stat(); e != nil {
+				obj.Close()
+			}
+		}
+		reader = obj
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
+		if errResponse.StatusCode == http.StatusNotModified {
+			return nil, probe.NewError(ObjectNotModified{})
+		}
 		if errResponse.Code == "NoSuchBucket" {
 			return nil, probe.NewError(BucketDoesNotExist{
 				Bucket: bucket,
@@ -837,6 +928,22 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *pr
 	return reader, nil
 }
 
+// getObjectOptions - returns the minio-go options of the GET
+// requests of opts, with their preconditions.
+func getObjectOptions(opts GetOptions) minio.GetObjectOptions {
+	getOpts := minio.GetObjectOptions{
+		ServerSideEncryption: opts.SSE,
+		VersionID:            opts.VersionID,
+	}
+	if opts.IfNoneMatch != "" {
+		getOpts.SetMatchETagExcept(opts.IfNoneMatch)
+	}
+	if !opts.IfModifiedSince.IsZero() {
+		getOpts.SetModified(opts.IfModifiedSince)
+	}
+	return getOpts
+}
+
 // Copy - copy object, uses server side copy API. Also uses an abstracted API
 // such that large file sizes will be copied in multipart manner on server
 // side.
@@ -1029,7 +1136,32 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		opts.SendContentMd5 = true
 	}
 
-	ui, e := c.api.PutObject(ctx, bucket, object, reader, size, opts)
+	_, partSize, _, e := minio.OptimalPartInfo(size, putOpts.multipartSize)
+	if e != nil {
+		// Part size larger than the object, uploaded in a single part.
+		partSize = 0
+	}
+
+	// minio-go uploads the parts of local files in parallel, other
+	// multipart uploads of known size are parallelized here. Those of
+	// local files can be resumed part by part.
+	multipart := !putOpts.disableMultipart && partSize > 0 && size > partSize
+	resumable := multipart && putOpts.resumable && isReadAt(reader)
+	streamed := multipart && !isReadAt(reader) && putOpts.multipartThreads != 1
+
+	// Hash the uploaded data to verify the returned ETag if requested,
+	// encrypted uploads do not have MD5 based ETags.
+	var hasher *etagHasher
+	if globalVerifyETag && putOpts.sse == nil {
+		reader, hasher = newETagReader(reader, partSize)
+	}
+
+	var ui minio.UploadInfo
+	if resumable || streamed {
+		ui, e = c.putMultipart(ctx, bucket, object, reader, size, partSize, int(putOpts.multipartThreads), progress, opts, resumable)
+	} else {
+		ui, e = c.api.PutObject(ctx, bucket, object, reader, size, opts)
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "UnexpectedEOF" || e == io.EOF {
@@ -1068,9 +1200,30 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		}
 		return ui.Size, probe.NewError(e)
 	}
+
+	if hasher != nil {
+		if expected, ok := hasher.verify(ui.ETag); !ok && !c.isKMSEncrypted(ctx, bucket, object, ui.VersionID) {
+			return ui.Size, probe.NewError(ETagMismatch{
+				Object:   c.targetURL.String(),
+				Expected: expected,
+				ETag:     strings.Trim(ui.ETag, "\""),
+			})
+		}
+	}
 	return ui.Size, nil
 }
 
+// isKMSEncrypted returns true if an object is encrypted with a
+// key of a KMS, e.g. by the bucket default encryption, so that its
+// ETag is not the MD5 sum of its content.
+func (c *S3Client) isKMSEncrypted(ctx context.Context, bucket, object, versionID string) bool {
+	info, e := c.api.StatObject(ctx, bucket, object, minio.StatObjectOptions{VersionID: versionID})
+	if e != nil {
+		return false
+	}
+	return strings.EqualFold(info.Metadata.Get("X-Amz-Server-Side-Encryption"), "aws:kms")
+}
+
 // Remove incomplete uploads.
 func (c *S3Client) removeIncompleteObjects(ctx context.Context, bucket string, objectsCh <-chan minio.ObjectInfo) <-chan minio.RemoveObjectResult {
 	removeObjectErrorCh := make(chan minio.RemoveObjectResult)
@@ -1167,12 +1320,13 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 					for removeStatus := range statusCh {
 						if removeStatus.Err != nil {
 							resultCh <- RemoveResult{
-								BucketName: bucket,
-								Err:        probe.NewError(removeStatus.Err),
+								BucketName:         prevBucket,
+								RemoveObjectResult: removeStatus,
+								Err:                probe.NewError(removeStatus.Err),
 							}
 						} else {
 							resultCh <- RemoveResult{
-								BucketName:         bucket,
+								BucketName:         prevBucket,
 								RemoveObjectResult: removeStatus,
 							}
 						}
@@ -1213,8 +1367,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 						case removeStatus := <-statusCh:
 							if removeStatus.Err != nil {
 								resultCh <- RemoveResult{
-									BucketName: bucket,
-									Err:        probe.NewError(removeStatus.Err),
+									BucketName:         bucket,
+									RemoveObjectResult: removeStatus,
+									Err:                probe.NewError(removeStatus.Err),
 								}
 							} else {
 								resultCh <- RemoveResult{
@@ -1253,10 +1408,13 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 					// it is too generic. We have the object's name and vid.
 					// Adding the object's name and version id into the error msg
 					resultCh <- RemoveResult{
-						Err: probe.NewError(removeStatus.Err),
+						BucketName:         prevBucket,
+						RemoveObjectResult: removeStatus,
+						Err:                probe.NewError(removeStatus.Err),
 					}
 				} else {
 					resultCh <- RemoveResult{
+						BucketName:         prevBucket,
 						RemoveObjectResult: removeStatus,
 					}
 				}
@@ -1275,6 +1433,85 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 	return resultCh
 }
 
+// makeBucket creates a bucket, names only valid outside of AWS are
+// created directly since minio-go validates them strictly.
+func (c *S3Client) makeBucket(ctx context.Context, bucket string, opts minio.MakeBucketOptions) error {
+	if !c.relaxedNames || s3utils.CheckValidBucketNameStrict(bucket) == nil {
+		return c.api.MakeBucket(ctx, bucket, opts)
+	}
+	if e := s3utils.CheckValidBucketName(bucket); e != nil {
+		return e
+	}
+
+	location := opts.Region
+	if location == "" {
+		location = "us-east-1"
+	}
+	var body []byte
+	if location != "us-east-1" {
+		body = []byte(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>` +
+			location + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+
+	header := make(http.Header)
+	if opts.ObjectLocking {
+		header.Set("X-Amz-Bucket-Object-Lock-Enabled", "true")
+	}
+	resp, e := c.bucketRequest(ctx, http.MethodPut, bucket, nil, header, body, location)
+	if e != nil {
+		return e
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// bucketRequest sends a signed request for a bucket sub-resource which
+// minio-go does not expose, the caller must close the response body.
+func (c *S3Client) bucketRequest(ctx context.Context, method, bucket string, query url.Values, header http.Header, body []byte, location string) (*http.Response, error) {
+	// Address the bucket like minio-go does, dotted buckets do not match
+	// the wildcard certificates of virtual hosts.
+	u := *c.api.EndpointURL()
+	if c.virtualStyle && !(u.Scheme == "https" && isDottedBucket(bucket, u.Host)) {
+		if !strings.HasPrefix(u.Host, bucket+".") {
+			u.Host = bucket + "." + u.Host
+		}
+		u.Path = "/"
+	} else {
+		u.Path = "/" + bucket + "/"
+	}
+	u.RawQuery = s3utils.QueryEncode(query)
+	req, e := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if e != nil {
+		return nil, e
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if strings.EqualFold(c.config.Signature, "S3v2") {
+		req = signer.SignV2(*req, c.config.AccessKey, c.config.SecretKey, false)
+	} else {
+		sum := sha256.Sum256(body)
+		req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+		req = signer.SignV4(*req, c.config.AccessKey, c.config.SecretKey, c.config.SessionToken, location)
+	}
+
+	resp, e := (&http.Client{Transport: c.transport}).Do(req)
+	if e != nil {
+		return nil, e
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	errResp := minio.ErrorResponse{StatusCode: resp.StatusCode}
+	if e = xml.NewDecoder(resp.Body).Decode(&errResp); e != nil {
+		errResp.Code = resp.Status
+		errResp.Message = http.StatusText(resp.StatusCode)
+	}
+	errResp.BucketName = bucket
+	return nil, errResp
+}
+
 // MakeBucket - make a new bucket.
 func (c *S3Client) MakeBucket(ctx context.Context, region string, ignoreExisting, withLock bool) *probe.Error {
 	bucket, object := c.url2BucketAndObject()
@@ -1302,7 +1539,7 @@ func (c *S3Client) MakeBucket(ctx context.Context, region string, ignoreExisting
 			switch minio.ToErrorResponse(e).Code {
 			case "NoSuchBucket":
 				opts := minio.MakeBucketOptions{Region: region, ObjectLocking: withLock}
-				if e = c.api.MakeBucket(ctx, bucket, opts); e != nil {
+				if e = c.makeBucket(ctx, bucket, opts); e != nil {
 					return probe.NewError(e)
 				}
 				retried = true
@@ -1314,7 +1551,7 @@ func (c *S3Client) MakeBucket(ctx context.Context, region string, ignoreExisting
 
 	var e error
 	opts := minio.MakeBucketOptions{Region: region, ObjectLocking: withLock}
-	if e = c.api.MakeBucket(ctx, bucket, opts); e != nil {
+	if e = c.makeBucket(ctx, bucket, opts); e != nil {
 		// Ignore bucket already existing error when ignoreExisting flag is enabled
 		if ignoreExisting {
 			switch minio.ToErrorResponse(e).Code {
@@ -1441,12 +1678,105 @@ func (c *S3Client) listObjectWrapper(ctx context.Context, bucket, object string,
 		return c.listVersions(ctx, bucket, object, isRecursive, timeRef, withVersions, withDeleteMarkers)
 	}
 
+	opts := minio.ListObjectsOptions{Prefix: object, Recursive: isRecursive, WithMetadata: metadata, MaxKeys: maxKeys}
+	switch c.listAPI {
+	case "v1":
+		c.useListV1(&opts)
+		return c.api.ListObjects(ctx, bucket, opts)
+	case "v2":
+		return c.api.ListObjects(ctx, bucket, opts)
+	}
+
 	if isGoogle(c.targetURL.Host) {
 		// Google Cloud S3 layer doesn't implement ListObjectsV2 implementation
 		// https://github.com/minio/mc/issues/3073
-		return c.api.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: object, Recursive: isRecursive, UseV1: true, MaxKeys: maxKeys})
+		c.useListV1(&opts)
+		return c.api.ListObjects(ctx, bucket, opts)
+	}
+	return c.listObjectsV2Fallback(ctx, bucket, opts)
+}
+
+// listV1Hosts - hosts found to only implement the V1 listing API.
+var listV1Hosts sync.Map
+
+// listV1MetadataHosts - hosts warned about listings without metadata.
+var listV1MetadataHosts sync.Map
+
+// useListV1 - switch a listing to the V1 API, which does not return the
+// metadata of objects, with a warning if metadata was requested.
+func (c *S3Client) useListV1(opts *minio.ListObjectsOptions) {
+	host := c.targetURL.Host
+	if opts.WithMetadata && !globalQuiet && !globalJSON {
+		if _, warned := listV1MetadataHosts.LoadOrStore(host, true); !warned {
+			console.Errorf("Listing `%s` without object metadata, the server only implements the V1 listing API.\n", host)
+		}
 	}
-	return c.api.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: object, Recursive: isRecursive, WithMetadata: metadata, MaxKeys: maxKeys})
+	opts.UseV1, opts.WithMetadata = true, false
+}
+
+// isListV2Unsupported - returns true if the server refused
+// the request as it does not implement ListObjectsV2.
+func isListV2Unsupported(e error) bool {
+	errResp := minio.ToErrorResponse(e)
+	return errResp.Code == "NotImplemented" || errResp.StatusCode == http.StatusNotImplemented
+}
+
+// listObjectsV2Fallback - list objects with ListObjectsV2, continuing
+// with the V1 API if the server does not implement it. minio-go also
+// reports truncated pages without a continuation token, as sent by
+// servers answering with a V1 listing, as not implemented.
+func (c *S3Client) listObjectsV2Fallback(ctx context.Context, bucket string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	host := c.targetURL.Host
+	if _, ok := listV1Hosts.Load(host); ok {
+		c.useListV1(&opts)
+		return c.api.ListObjects(ctx, bucket, opts)
+	}
+
+	objectCh := make(chan minio.ObjectInfo, 1)
+	go func() {
+		defer close(objectCh)
+
+		listCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var last string
+		var fallback bool
+		for object := range c.api.ListObjects(listCtx, bucket, opts) {
+			if object.Err != nil && isListV2Unsupported(object.Err) {
+				fallback = true
+				break
+			}
+			if object.Key > last {
+				last = object.Key
+			}
+			select {
+			case objectCh <- object:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if !fallback {
+			return
+		}
+		cancel()
+		listV1Hosts.Store(host, true)
+
+		// Resume after the last object already sent, a common prefix
+		// sent last is listed again by the V1 API and skipped.
+		c.useListV1(&opts)
+		opts.StartAfter = last
+		for object := range c.api.ListObjects(ctx, bucket, opts) {
+			if object.Err == nil && object.Key <= last {
+				continue
+			}
+			select {
+			case objectCh <- object:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return objectCh
 }
 
 func (c *S3Client) statIncompleteUpload(ctx context.Context, bucket, object string) (*ClientContent, *probe.Error) {
@@ -1588,6 +1918,10 @@ func (c *S3Client) getObjectStat(ctx context.Context, bucket, object string, opt
 	if objectMetadata.VersionID == "" {
 		objectMetadata.VersionID = opts.VersionID
 	}
+	// HEAD reports the storage class as a header only
+	if objectMetadata.StorageClass == "" {
+		objectMetadata.StorageClass = objectStat.Metadata.Get("X-Amz-Storage-Class")
+	}
 	return objectMetadata, nil
 }
 
@@ -1624,6 +1958,12 @@ func isVirtualHostStyle(host string, lookup minio.BucketLookupType) bool {
 	return isAmazon(host) && !isAmazonChina(host) || isGoogle(host) || isAmazonAccelerated(host)
 }
 
+// isDottedBucket returns true if the bucket name has dots and is not
+// part of the host name already.
+func isDottedBucket(bucket, host string) bool {
+	return strings.Contains(bucket, ".") && !strings.HasPrefix(host, bucket+".")
+}
+
 func url2BucketAndObject(u *ClientURL, virtualStyle bool) (bucketName, objectName string) {
 	path := u.Path
 	// Convert any virtual host styled requests.
@@ -1877,6 +2217,7 @@ func (c *S3Client) listIncompleteInRoutine(ctx context.Context, contentCh chan *
 					content.URL = url
 					content.Size = object.Size
 					content.Time = object.Initiated
+					content.UploadID = object.UploadID
 					content.Type = os.ModeTemporary
 				}
 				contentCh <- content
@@ -1905,6 +2246,7 @@ func (c *S3Client) listIncompleteInRoutine(ctx context.Context, contentCh chan *
 				content.URL = url
 				content.Size = object.Size
 				content.Time = object.Initiated
+				content.UploadID = object.UploadID
 				content.Type = os.ModeTemporary
 			}
 			contentCh <- content
@@ -1944,6 +2286,7 @@ func (c *S3Client) listIncompleteRecursiveInRoutine(ctx context.Context, content
 				content.URL = url
 				content.Size = object.Size
 				content.Time = object.Initiated
+				content.UploadID = object.UploadID
 				content.Type = os.ModeTemporary
 				contentCh <- content
 			}
@@ -1968,6 +2311,7 @@ func (c *S3Client) listIncompleteRecursiveInRoutine(ctx context.Context, content
 			content.URL = url
 			content.Size = object.Size
 			content.Time = object.Initiated
+			content.UploadID = object.UploadID
 			content.Type = os.ModeTemporary
 			contentCh <- content
 		}
@@ -2021,6 +2365,7 @@ func (c *S3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 	content.Metadata = map[string]string{}
 	content.UserMetadata = map[string]string{}
 	content.ReplicationStatus = entry.ReplicationStatus
+	content.Owner = ownerName(entry.Owner)
 	for k, v := range entry.UserMetadata {
 		content.UserMetadata[k] = v
 	}
@@ -2068,6 +2413,15 @@ func (c *S3Client) bucketStat(ctx context.Context, bucket string) (*ClientConten
 	}, nil
 }
 
+// StatBucket - verify the bucket of the URL exists with a HEAD request.
+func (c *S3Client) StatBucket(ctx context.Context) (*ClientContent, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	return c.bucketStat(ctx, bucket)
+}
+
 func (c *S3Client) listInRoutine(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
@@ -2166,6 +2520,10 @@ func (c *S3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *C
 			}
 		}
 	default:
+		if opts.MaxDepth > 0 {
+			c.listDepthInRoutine(ctx, contentCh, b, o, 1, opts)
+			return
+		}
 		isRecursive := true
 		for object := range c.listObjectWrapper(ctx, b, o, isRecursive, time.Time{}, false, false, opts.WithMetadata, -1) {
 			if object.Err != nil {
@@ -2179,6 +2537,36 @@ func (c *S3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *C
 	}
 }
 
+// listDepthInRoutine lists the objects under prefix up to opts.MaxDepth
+// levels deep, depth being the level of the entries under prefix. Levels
+// are listed with a delimiter, the prefixes of the last level are sent
+// as folders, it returns false once the listing failed.
+func (c *S3Client) listDepthInRoutine(ctx context.Context, contentCh chan *ClientContent, bucket, prefix string, depth int, opts ListOptions) bool {
+	isRecursive := false
+	for object := range c.listObjectWrapper(ctx, bucket, prefix, isRecursive, time.Time{}, false, false, opts.WithMetadata, -1) {
+		if object.Err != nil {
+			contentCh <- &ClientContent{
+				Err: probe.NewError(object.Err),
+			}
+			return false
+		}
+		// A folder object named as prefix is listed as any object.
+		if object.Key != prefix && strings.HasSuffix(object.Key, string(c.targetURL.Separator)) {
+			if depth < opts.MaxDepth {
+				if !c.listDepthInRoutine(ctx, contentCh, bucket, object.Key, depth+1, opts) {
+					return false
+				}
+				continue
+			}
+			if opts.ShowDir == DirNone {
+				continue
+			}
+		}
+		contentCh <- c.objectInfo2ClientContent(bucket, object)
+	}
+	return true
+}
+
 // ShareDownload - get a usable presigned object url to share.
 func (c *S3Client) ShareDownload(ctx context.Context, versionID string, expires time.Duration) (string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
@@ -2194,8 +2582,37 @@ func (c *S3Client) ShareDownload(ctx context.Context, versionID string, expires
 	return presignedURL.String(), nil
 }
 
-// ShareUpload - get data for presigned post http form upload.
-func (c *S3Client) ShareUpload(ctx context.Context, isRecursive bool, expires time.Duration, contentType string) (string, map[string]string, *probe.Error) {
+// Presign - get a time-limited signed URL for a GET, HEAD or PUT
+// request on the object, no data is transferred.
+func (c *S3Client) Presign(ctx context.Context, method string, expires time.Duration) (string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return "", probe.NewError(BucketNameEmpty{})
+	}
+	if object == "" {
+		return "", probe.NewError(ObjectNameEmpty{})
+	}
+	var presignedURL *url.URL
+	var e error
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		presignedURL, e = c.api.PresignedGetObject(ctx, bucket, object, expires, nil)
+	case http.MethodHead:
+		presignedURL, e = c.api.PresignedHeadObject(ctx, bucket, object, expires, nil)
+	case http.MethodPut:
+		presignedURL, e = c.api.PresignedPutObject(ctx, bucket, object, expires)
+	default:
+		return "", probe.NewError(fmt.Errorf("unsupported method `%s` for a presigned URL", method))
+	}
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return presignedURL.String(), nil
+}
+
+// ShareUpload - get data for presigned post http form upload, the
+// size of uploads is only limited if maxSize is positive.
+func (c *S3Client) ShareUpload(ctx context.Context, isRecursive bool, expires time.Duration, contentType string, minSize, maxSize int64) (string, map[string]string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 	p := minio.NewPostPolicy()
 	if e := p.SetExpires(UTCNow().Add(expires)); e != nil {
@@ -2205,6 +2622,11 @@ func (c *S3Client) ShareUpload(ctx context.Context, isRecursive bool, expires ti
 		// No need to verify for error here, since we have stripped out spaces.
 		p.SetContentType(contentType)
 	}
+	if maxSize > 0 {
+		if e := p.SetContentLengthRange(minSize, maxSize); e != nil {
+			return "", nil, probe.NewError(e)
+		}
+	}
 	if e := p.SetBucket(bucket); e != nil {
 		return "", nil, probe.NewError(e)
 	}
@@ -2358,6 +2780,128 @@ func (c *S3Client) GetObjectLockConfig(ctx context.Context) (string, minio.Reten
 	return status, "", 0, "", nil
 }
 
+// ownerName returns the display name of an owner, or its ID when the
+// server does not send a display name. minio-go decodes the display
+// name into ID and the ID into DisplayName, so both are swapped here.
+func ownerName(owner minio.Owner) string {
+	if owner.ID != "" {
+		return owner.ID
+	}
+	return owner.DisplayName
+}
+
+// GetObjectACL - Get owner and ACL grants of an object.
+func (c *S3Client) GetObjectACL(ctx context.Context) (string, []ClientGrant, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if bucketName == "" {
+		return "", nil, probe.NewError(BucketNameEmpty{})
+	}
+	if objectName == "" {
+		return "", nil, probe.NewError(ObjectNameEmpty{})
+	}
+
+	objInfo, e := c.api.GetObjectACL(ctx, bucketName, objectName)
+	if e != nil {
+		return "", nil, probe.NewError(e)
+	}
+
+	var grants []ClientGrant
+	for _, grant := range objInfo.Grant {
+		grantee := grant.Grantee.URI
+		if grantee == "" {
+			grantee = grant.Grantee.DisplayName
+		}
+		if grantee == "" {
+			grantee = grant.Grantee.ID
+		}
+		grants = append(grants, ClientGrant{Grantee: grantee, Permission: grant.Permission})
+	}
+	return ownerName(objInfo.Owner), grants, nil
+}
+
+// bucketAccessControlPolicy - response of a bucket ?acl request.
+type bucketAccessControlPolicy struct {
+	XMLName           xml.Name `xml:"AccessControlPolicy"`
+	Owner             minio.Owner
+	AccessControlList minio.AccessControlList
+}
+
+// cannedBucketACLs - canned ACLs accepted by SetBucketACL.
+var cannedBucketACLs = []string{"private", "public-read", "public-read-write", "authenticated-read"}
+
+// GetBucketACL - Get owner and ACL grants of a bucket.
+func (c *S3Client) GetBucketACL(ctx context.Context) (string, []ClientGrant, *probe.Error) {
+	bucketName, objectName := c.url2BucketAndObject()
+	if bucketName == "" {
+		return "", nil, probe.NewError(BucketNameEmpty{})
+	}
+	if objectName != "" {
+		return "", nil, probe.NewError(BucketNameTopLevel{})
+	}
+	location, e := c.api.GetBucketLocation(ctx, bucketName)
+	if e != nil {
+		return "", nil, probe.NewError(e)
+	}
+	resp, e := c.bucketRequest(ctx, http.MethodGet, bucketName, url.Values{"acl": []string{""}}, nil, nil, location)
+	if e != nil {
+		return "", nil, probe.NewError(e)
+	}
+	defer resp.Body.Close()
+
+	var acl bucketAccessControlPolicy
+	if e = xml.NewDecoder(resp.Body).Decode(&acl); e != nil {
+		return "", nil, probe.NewError(e)
+	}
+	var grants []ClientGrant
+	for _, grant := range acl.AccessControlList.Grant {
+		grantee := grant.Grantee.URI
+		if grantee == "" {
+			grantee = grant.Grantee.DisplayName
+		}
+		if grantee == "" {
+			grantee = grant.Grantee.ID
+		}
+		grants = append(grants, ClientGrant{Grantee: grantee, Permission: grant.Permission})
+	}
+	return ownerName(acl.Owner), grants, nil
+}
+
+// SetBucketACL - Set a canned ACL on a bucket.
+func (c *S3Client) SetBucketACL(ctx context.Context, acl string) *probe.Error {
+	bucketName, objectName := c.url2BucketAndObject()
+	if bucketName == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if objectName != "" {
+		return probe.NewError(BucketNameTopLevel{})
+	}
+	if !isCannedBucketACL(acl) {
+		return probe.NewError(fmt.Errorf("unknown canned ACL `%s`, expected one of %s", acl, strings.Join(cannedBucketACLs, ", ")))
+	}
+	location, e := c.api.GetBucketLocation(ctx, bucketName)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	header := make(http.Header)
+	header.Set("X-Amz-Acl", acl)
+	resp, e := c.bucketRequest(ctx, http.MethodPut, bucketName, url.Values{"acl": []string{""}}, header, nil, location)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// isCannedBucketACL - returns true if acl is a known canned bucket ACL.
+func isCannedBucketACL(acl string) bool {
+	for _, canned := range cannedBucketACLs {
+		if acl == canned {
+			return true
+		}
+	}
+	return false
+}
+
 // GetTags - Get tags of bucket or object.
 func (c *S3Client) GetTags(ctx context.Context, versionID string) (map[string]string, *probe.Error) {
 	bucketName, objectName := c.url2BucketAndObject()
@@ -2638,6 +3182,11 @@ func (c *S3Client) DeleteEncryption(ctx context.Context) *probe.Error {
 	return nil
 }
 
+// DiskUsage - sums the objects of a paginated recursive listing.
+func (c *S3Client) DiskUsage(ctx context.Context, timeRef time.Time, withVersions bool) (DiskUsageInfo, *probe.Error) {
+	return listDiskUsage(ctx, c, ListOptions{TimeRef: timeRef, WithOlderVersions: withVersions})
+}
+
 // GetBucketInfo gets info about a bucket
 func (c *S3Client) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
 	var b BucketInfo
@@ -21,12 +21,28 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/minio/mc/pkg/probe"
 	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	. "gopkg.in/check.v1"
 )
 
@@ -240,6 +256,671 @@ func (s *TestSuite) TestObjectOperations(c *C) {
 	}
 }
 
+// newTestS3Client returns a client of url signing its requests with V4,
+// configure adjusts the configuration of the client before.
+func newTestS3Client(c *C, url string, configure ...func(*Config)) *S3Client {
+	conf := new(Config)
+	conf.HostURL = url
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	for _, f := range configure {
+		f(conf)
+	}
+	s3c, err := S3New(conf)
+	c.Assert(err, IsNil)
+	return s3c.(*S3Client)
+}
+
+// multipartHandler serves multipart uploads of a single object, failing
+// the upload of failPart once.
+type multipartHandler struct {
+	sync.Mutex
+	parts    map[int][]byte
+	puts     map[int]int
+	failPart int
+	object   []byte
+	aborted  bool
+}
+
+func (h *multipartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.Lock()
+	defer h.Unlock()
+	query := r.URL.Query()
+	var response string
+	switch {
+	case query.Get("location") != "" || r.URL.RawQuery == "location=":
+		response = "<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"
+	case r.Method == "POST" && r.URL.RawQuery == "uploads=":
+		response = "<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload</UploadId></InitiateMultipartUploadResult>"
+	case r.Method == "PUT":
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		if partNumber == h.failPart {
+			h.failPart = 0
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		if sum := sha256.Sum256(data); r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.parts[partNumber] = data
+		h.puts[partNumber]++
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", "\""+hex.EncodeToString(sum[:])+"\"")
+	case r.Method == "DELETE":
+		h.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.Method == "GET":
+		response = "<ListPartsResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload</UploadId><IsTruncated>false</IsTruncated>"
+		for n, data := range h.parts {
+			sum := md5.Sum(data)
+			response += "<Part><PartNumber>" + strconv.Itoa(n) + "</PartNumber><ETag>\"" + hex.EncodeToString(sum[:]) +
+				"\"</ETag><Size>" + strconv.Itoa(len(data)) + "</Size><LastModified>2015-05-21T18:24:21.097Z</LastModified></Part>"
+		}
+		response += "</ListPartsResult>"
+	case r.Method == "POST":
+		sums := md5.New()
+		h.object = nil
+		for n := 1; n <= len(h.parts); n++ {
+			sum := md5.Sum(h.parts[n])
+			sums.Write(sum[:])
+			h.object = append(h.object, h.parts[n]...)
+		}
+		etag := hex.EncodeToString(sums.Sum(nil)) + "-" + strconv.Itoa(len(h.parts))
+		response = "<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>\"" + etag + "\"</ETag></CompleteMultipartUploadResult>"
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(response)))
+	w.Write([]byte(response))
+}
+
+// Test interrupted multipart uploads resume from their last part.
+func (s *TestSuite) TestPutResumable(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "s3-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(root)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 12<<20/16)
+	fpath := filepath.Join(root, "object")
+	c.Assert(ioutil.WriteFile(fpath, data, 0o644), IsNil)
+
+	handler := &multipartHandler{parts: make(map[int][]byte), puts: make(map[int]int), failPart: 2}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket/object")
+
+	put := func() *probe.Error {
+		file, e := os.Open(fpath)
+		c.Assert(e, IsNil)
+		defer file.Close()
+		_, err := s3c.Put(context.Background(), file, int64(len(data)), nil, PutOptions{resumable: true, multipartSize: 5 << 20, multipartThreads: 1})
+		return err
+	}
+	c.Assert(put(), NotNil)
+	c.Assert(handler.puts, DeepEquals, map[int]int{1: 1})
+
+	c.Assert(put(), IsNil)
+	c.Assert(handler.puts, DeepEquals, map[int]int{1: 1, 2: 1, 3: 1})
+	c.Assert(bytes.Equal(handler.object, data), Equals, true)
+	states, e := ioutil.ReadDir(filepath.Join(root, globalUploadsDir))
+	c.Assert(e, IsNil)
+	c.Assert(states, HasLen, 0)
+
+	// Parts not matching the local data anymore are uploaded again.
+	handler.failPart = 3
+	c.Assert(put(), NotNil)
+	data[0] = 'X'
+	c.Assert(ioutil.WriteFile(fpath, data, 0o644), IsNil)
+	c.Assert(put(), IsNil)
+	c.Assert(handler.puts, DeepEquals, map[int]int{1: 3, 2: 2, 3: 2})
+	c.Assert(bytes.Equal(handler.object, data), Equals, true)
+	c.Assert(handler.aborted, Equals, false)
+
+	// Expired uploads are aborted and uploaded again.
+	handler.failPart = 2
+	c.Assert(put(), NotNil)
+	states, e = ioutil.ReadDir(filepath.Join(root, globalUploadsDir))
+	c.Assert(e, IsNil)
+	c.Assert(states, HasLen, 1)
+	statePath := filepath.Join(root, globalUploadsDir, states[0].Name())
+	state := loadUploadState(statePath)
+	c.Assert(state, NotNil)
+	state.Updated = UTCNow().Add(-uploadStateExpiry - time.Hour)
+	stateData, e := json.Marshal(state)
+	c.Assert(e, IsNil)
+	c.Assert(ioutil.WriteFile(statePath, stateData, 0o600), IsNil)
+	c.Assert(put(), IsNil)
+	c.Assert(handler.aborted, Equals, true)
+	c.Assert(handler.puts, DeepEquals, map[int]int{1: 5, 2: 3, 3: 3})
+	c.Assert(bytes.Equal(handler.object, data), Equals, true)
+}
+
+// Test parts of streamed uploads are uploaded in parallel.
+func (s *TestSuite) TestPutParallelParts(c *C) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 12<<20/16)
+	handler := &multipartHandler{parts: make(map[int][]byte), puts: make(map[int]int)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket/object")
+
+	n, err := s3c.Put(context.Background(), bytes.NewReader(data), int64(len(data)), nil, PutOptions{multipartSize: 5 << 20, multipartThreads: 4})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	c.Assert(handler.puts, DeepEquals, map[int]int{1: 1, 2: 1, 3: 1})
+	c.Assert(bytes.Equal(handler.object, data), Equals, true)
+
+	// Failed uploads are aborted.
+	handler.failPart = 3
+	_, err = s3c.Put(context.Background(), bytes.NewReader(data), int64(len(data)), nil, PutOptions{multipartSize: 5 << 20, multipartThreads: 4})
+	c.Assert(err, NotNil)
+	c.Assert(handler.aborted, Equals, true)
+}
+
+// regionHandler rejects requests not signed for the region of the
+// bucket with the region in the error, as AWS does. The location of the
+// bucket is denied if denyLocation is set, requests are redirected to
+// the region of the bucket and counted in redirects if redirect is set.
+type regionHandler struct {
+	objectHandler
+	region       string
+	denyLocation bool
+	redirect     bool
+	redirects    *int32
+}
+
+func (h regionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		if h.denyLocation {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>"))
+			return
+		}
+		fmt.Fprintf(w, "<LocationConstraint>%s</LocationConstraint>", h.region)
+		return
+	}
+	if !strings.Contains(r.Header.Get("Authorization"), "/"+h.region+"/") {
+		if h.redirect {
+			atomic.AddInt32(h.redirects, 1)
+			w.Header().Set("x-amz-bucket-region", h.region)
+			w.WriteHeader(http.StatusMovedPermanently)
+			if r.Method != http.MethodHead {
+				w.Write([]byte("<Error><Code>PermanentRedirect</Code><Message>The bucket you are attempting to access must be addressed using the specified endpoint.</Message></Error>"))
+			}
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "<Error><Code>AuthorizationHeaderMalformed</Code><Message>The authorization header is malformed</Message><Region>%s</Region></Error>", h.region)
+		return
+	}
+	h.objectHandler.ServeHTTP(w, r)
+}
+
+// Test requests are signed for the region of the bucket, found with
+// its location or in the errors of requests signed for another region.
+func (s *TestSuite) TestObjectOperationsRegion(c *C) {
+	for _, denyLocation := range []bool{false, true} {
+		object := regionHandler{
+			objectHandler: objectHandler{
+				resource: "/bucket/object",
+				data:     []byte("Hello, World"),
+			},
+			region:       "eu-central-1",
+			denyLocation: denyLocation,
+		}
+		server := httptest.NewServer(object)
+		defer server.Close()
+
+		s3c := newTestS3Client(c, server.URL+object.resource)
+
+		reader, err := s3c.Get(context.Background(), GetOptions{})
+		c.Assert(err, IsNil)
+		data, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(data, DeepEquals, object.data)
+	}
+}
+
+// Test requests redirected to the region of the bucket are signed for
+// it and sent again, later requests to the bucket are not redirected.
+func (s *TestSuite) TestObjectOperationsRegionRedirect(c *C) {
+	defer os.Setenv("MC_REGION", os.Getenv("MC_REGION"))
+	for _, mcRegion := range []string{"", "us-east-1"} {
+		os.Setenv("MC_REGION", mcRegion)
+		var redirects int32
+		object := regionHandler{
+			objectHandler: objectHandler{
+				resource: "/bucket/object",
+				data:     []byte("Hello, World"),
+			},
+			region:       "eu-central-1",
+			denyLocation: true,
+			redirect:     true,
+			redirects:    &redirects,
+		}
+		server := httptest.NewServer(object)
+		defer server.Close()
+
+		s3c := newTestS3Client(c, server.URL+object.resource)
+
+		content, err := s3c.Stat(context.Background(), StatOptions{})
+		c.Assert(err, IsNil)
+		c.Assert(content.Size, Equals, int64(len(object.data)))
+
+		reader, err := s3c.Get(context.Background(), GetOptions{})
+		c.Assert(err, IsNil)
+		data, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(data, DeepEquals, object.data)
+		c.Assert(atomic.LoadInt32(&redirects), Equals, int32(1))
+	}
+}
+
+// Test requests to AWS are sent to the endpoint of the region.
+func (s *TestSuite) TestBucketRegionTransportAmazon(c *C) {
+	creds := credentials.NewStaticV4("WLGDGYAQYIGI833EV05A", "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", "")
+	transport := newBucketRegionTransport(http.DefaultTransport, "s3.amazonaws.com", creds)
+	for _, host := range []string{"s3.dualstack.us-east-1.amazonaws.com", "bucket.s3.dualstack.us-east-1.amazonaws.com"} {
+		path := "/bucket/object"
+		if strings.HasPrefix(host, "bucket.") {
+			path = "/object"
+		}
+		req, e := http.NewRequest(http.MethodGet, "https://"+host+path, nil)
+		c.Assert(e, IsNil)
+		bucket, base := transport.bucketOf(req)
+		c.Assert(bucket, Equals, "bucket")
+		c.Assert(base, Equals, "s3.dualstack.us-east-1.amazonaws.com")
+
+		r, e := transport.sign(req, nil, bucket, base, "eu-west-1")
+		c.Assert(e, IsNil)
+		c.Assert(r.URL.Host, Equals, strings.Replace(host, "us-east-1", "eu-west-1", 1))
+		c.Assert(signedRegionV4(r), Equals, "eu-west-1")
+	}
+}
+
+// Test the body of large uploads is not sent if the server rejects them.
+func (s *TestSuite) TestExpectContinue(c *C) {
+	var expect string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expect = r.Header.Get("Expect")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	transport := expectContinueTransport{transport: &http.Transport{ExpectContinueTimeout: 10 * time.Second}}
+	body := &countingReader{Reader: bytes.NewReader(make([]byte, 2*expectContinueMinSize))}
+	req, e := http.NewRequest(http.MethodPut, server.URL+"/bucket/object", body)
+	c.Assert(e, IsNil)
+	req.ContentLength = 2 * expectContinueMinSize
+	resp, e := transport.RoundTrip(req)
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusForbidden)
+	c.Assert(expect, Equals, "100-continue")
+	c.Assert(body.n, Equals, int64(0))
+	c.Assert(req.Header.Get("Expect"), Equals, "")
+}
+
+// Test dotted buckets are addressed in path style over TLS.
+func (s *TestSuite) TestObjectOperationsDottedBucket(c *C) {
+	object := objectHandler{
+		resource: "/my.bucket/object",
+		data:     []byte("Hello, World"),
+	}
+	server := httptest.NewTLSServer(object)
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+object.resource, func(conf *Config) {
+		conf.Lookup = minio.BucketLookupDNS
+		conf.Transport = server.Client().Transport.(*http.Transport)
+	})
+
+	n, err := s3c.Put(context.Background(), bytes.NewReader(object.data), int64(len(object.data)), nil, PutOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(object.data)))
+
+	c.Assert(isDottedBucket("my.bucket", "s3.amazonaws.com"), Equals, true)
+	c.Assert(isDottedBucket("my.bucket", "my.bucket.s3.amazonaws.com"), Equals, false)
+	c.Assert(isDottedBucket("bucket", "s3.amazonaws.com"), Equals, false)
+}
+
+// Test conditional downloads of unmodified objects fail with ObjectNotModified.
+func (s *TestSuite) TestGetNotModified(c *C) {
+	data := []byte("Hello, World")
+	etag := "82bb413746aee42f89dea2b59614f9ef"
+	modTime := time.Date(2021, time.December, 31, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+			return
+		}
+		w.Header().Set("ETag", "\""+etag+"\"")
+		http.ServeContent(w, r, "object", modTime, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket/object")
+
+	for _, opts := range []GetOptions{
+		{IfNoneMatch: etag},
+		{IfModifiedSince: modTime},
+		{IfNoneMatch: etag, Parallel: 4},
+	} {
+		_, err := s3c.Get(context.Background(), opts)
+		c.Assert(err, NotNil)
+		_, ok := err.ToGoError().(ObjectNotModified)
+		c.Assert(ok, Equals, true)
+	}
+
+	reader, err := s3c.Get(context.Background(), GetOptions{IfNoneMatch: "other", IfModifiedSince: modTime.Add(-time.Hour)})
+	c.Assert(err, IsNil)
+	got, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(reader.Close(), IsNil)
+	c.Assert(got, DeepEquals, data)
+}
+
+// Test parallel downloads reassemble ranged parts in order.
+func (s *TestSuite) TestGetParallel(c *C) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64)
+	var ranges int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			atomic.AddInt32(&ranges, 1)
+		}
+		w.Header().Set("ETag", "\"9af2f8218b150c351ad802c6f3d66abe\"")
+		http.ServeContent(w, r, "object", UTCNow(), bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket/object")
+
+	reader, err := s3c.Get(context.Background(), GetOptions{Parallel: 4, PartSize: 100})
+	c.Assert(err, IsNil)
+	got, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(reader.Close(), IsNil)
+	c.Assert(got, DeepEquals, data)
+	c.Assert(atomic.LoadInt32(&ranges), Equals, int32(11))
+}
+
+// Test parallel downloads cancelled while reading fail instead of
+// waiting for parts which are never fetched.
+func (s *TestSuite) TestGetParallelCancel(c *C) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+			return
+		}
+		w.Header().Set("ETag", "\"9af2f8218b150c351ad802c6f3d66abe\"")
+		http.ServeContent(w, r, "object", UTCNow(), bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket/object")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader, e := s3c.getParallel(ctx, "bucket", "object", GetOptions{Parallel: 2, PartSize: 100})
+	c.Assert(e, IsNil)
+	defer reader.Close()
+	parallelReader, ok := reader.(*parallelGetReader)
+	c.Assert(ok, Equals, true)
+
+	// Read the first part, once the next two are fetched the others
+	// wait for a free slot.
+	_, e = io.ReadFull(reader, make([]byte, 100))
+	c.Assert(e, IsNil)
+	for len(parallelReader.parts[1]) == 0 || len(parallelReader.parts[2]) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, e := ioutil.ReadAll(reader)
+		done <- e
+	}()
+	select {
+	case e = <-done:
+		c.Assert(e, Equals, context.Canceled)
+	case <-time.After(10 * time.Second):
+		c.Fatal("read of a cancelled parallel download did not return")
+	}
+}
+
+// Test presigned URLs are generated without transferring data.
+func (s *TestSuite) TestPresign(c *C) {
+	object := objectHandler{
+		resource: "/bucket/object",
+		data:     []byte("Hello, World"),
+	}
+	server := httptest.NewServer(object)
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+object.resource)
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodHead} {
+		presignedURL, err := s3c.Presign(context.Background(), method, 10*time.Minute)
+		c.Assert(err, IsNil)
+		u, e := url.Parse(presignedURL)
+		c.Assert(e, IsNil)
+		c.Assert(u.Path, Equals, object.resource)
+		c.Assert(u.Query().Get("X-Amz-Expires"), Equals, "600")
+		c.Assert(u.Query().Get("X-Amz-Signature"), Not(Equals), "")
+	}
+
+	_, err := s3c.Presign(context.Background(), http.MethodDelete, 10*time.Minute)
+	c.Assert(err, NotNil)
+}
+
+// Test object operations through a unix domain socket.
+func (s *TestSuite) TestObjectOperationsUnixSocket(c *C) {
+	object := objectHandler{
+		resource: "/bucket/object",
+		data:     []byte("Hello, World"),
+	}
+	socket := filepath.Join(c.MkDir(), "minio.sock")
+	l, e := net.Listen("unix", socket)
+	if e != nil {
+		c.Skip(e.Error())
+	}
+	server := httptest.NewUnstartedServer(object)
+	server.Listener = l
+	server.Start()
+	defer server.Close()
+
+	s3c := newTestS3Client(c, "http://localhost"+object.resource, func(conf *Config) {
+		conf.Socket = socket
+	})
+
+	n, err := s3c.Put(context.Background(), bytes.NewReader(object.data), int64(len(object.data)), nil, PutOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(object.data)))
+
+	reader, err := s3c.Get(context.Background(), GetOptions{})
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(data, DeepEquals, object.data)
+}
+
+// Test bucket names outside of the AWS rules with relaxed validation.
+func (s *TestSuite) TestMakeBucketRelaxedNames(c *C) {
+	var created string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.Header.Get("Authorization") != "" {
+			created = r.URL.Path
+		}
+	}))
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/My_Bucket")
+	c.Assert(s3c.MakeBucket(context.Background(), "", false, false), NotNil)
+
+	s3c = newTestS3Client(c, server.URL+"/My_Bucket", func(conf *Config) {
+		conf.RelaxedBucketNames = true
+	})
+	c.Assert(s3c.MakeBucket(context.Background(), "", false, false), IsNil)
+	c.Assert(created, Equals, "/My_Bucket/")
+}
+
+// Test bucket ACLs are read and set with ?acl requests.
+func (s *TestSuite) TestS3BucketACL(c *C) {
+	var acl string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Has("location"):
+			w.Write([]byte("<LocationConstraint></LocationConstraint>"))
+		case !query.Has("acl") || r.URL.Path != "/bucket/":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut:
+			acl = r.Header.Get("X-Amz-Acl")
+		default:
+			w.Write([]byte("<AccessControlPolicy><Owner><ID>1234</ID><DisplayName>owner</DisplayName></Owner><AccessControlList>" +
+				"<Grant><Grantee><ID>1234</ID><DisplayName>owner</DisplayName></Grantee><Permission>FULL_CONTROL</Permission></Grant>" +
+				"<Grant><Grantee><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant>" +
+				"</AccessControlList></AccessControlPolicy>"))
+		}
+	}))
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket")
+
+	owner, grants, err := s3c.GetBucketACL(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(owner, Equals, "owner")
+	c.Assert(grants, DeepEquals, []ClientGrant{
+		{Grantee: "owner", Permission: "FULL_CONTROL"},
+		{Grantee: "http://acs.amazonaws.com/groups/global/AllUsers", Permission: "READ"},
+	})
+
+	c.Assert(s3c.SetBucketACL(context.Background(), "public-read"), IsNil)
+	c.Assert(acl, Equals, "public-read")
+	c.Assert(s3c.SetBucketACL(context.Background(), "everyone"), NotNil)
+}
+
+// listV1Handler is an http.Handler listing objects one per page with
+// the V1 API only, ListObjectsV2 requests are refused or answered
+// with a V1 listing, after a first V2 page if v2Token is set. With a
+// delimiter the V1 listing is a single page and, like some servers, a
+// marker naming a common prefix lists it again.
+type listV1Handler struct {
+	keys    []string
+	refuse  bool
+	v2Token bool
+	v2Pages int32
+}
+
+func (h *listV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var token string
+	v2 := query.Get("list-type") == "2"
+	switch {
+	case query.Has("location"):
+		w.Write([]byte("<LocationConstraint></LocationConstraint>"))
+		return
+	case v2 && h.refuse:
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented</Message></Error>"))
+		return
+	case v2:
+		atomic.AddInt32(&h.v2Pages, 1)
+		if h.v2Token && query.Get("continuation-token") == "" {
+			token = "<NextContinuationToken>token</NextContinuationToken>"
+		}
+	}
+
+	marker := query.Get("marker")
+	delimiter := query.Get("delimiter")
+	var entries []string
+	for _, key := range h.keys {
+		if key <= marker {
+			continue
+		}
+		if i := strings.Index(key, delimiter); delimiter != "" && i >= 0 {
+			key = key[:i+1]
+		}
+		if len(entries) == 0 || entries[len(entries)-1] != key {
+			entries = append(entries, key)
+		}
+	}
+	truncated := len(entries) > 1 && (v2 || delimiter == "")
+	if truncated {
+		entries = entries[:1]
+	}
+	var contents string
+	for _, entry := range entries {
+		if delimiter != "" && strings.HasSuffix(entry, delimiter) {
+			contents += "<CommonPrefixes><Prefix>" + entry + "</Prefix></CommonPrefixes>"
+		} else {
+			contents += "<Contents><Key>" + entry + "</Key><Size>1</Size></Contents>"
+		}
+	}
+	fmt.Fprintf(w, "<ListBucketResult><Name>bucket</Name>%s%s<IsTruncated>%t</IsTruncated></ListBucketResult>", contents, token, truncated)
+}
+
+// Test ListObjectsV2 falls back to the V1 API on older servers.
+func (s *TestSuite) TestListObjectsV1Fallback(c *C) {
+	for _, refuse := range []bool{true, false} {
+		handler := &listV1Handler{keys: []string{"a", "b", "c"}, refuse: refuse}
+		server := httptest.NewServer(handler)
+
+		s3c := newTestS3Client(c, server.URL+"/bucket")
+
+		var keys []string
+		for object := range s3c.listObjectWrapper(context.Background(), "bucket", "", true, time.Time{}, false, false, false, -1) {
+			c.Assert(object.Err, IsNil)
+			keys = append(keys, object.Key)
+		}
+		c.Assert(keys, DeepEquals, handler.keys)
+		if !refuse {
+			c.Assert(atomic.LoadInt32(&handler.v2Pages), Equals, int32(1))
+		}
+
+		// The host is now listed with the V1 API only.
+		keys = nil
+		for object := range s3c.listObjectWrapper(context.Background(), "bucket", "", true, time.Time{}, false, false, false, -1) {
+			c.Assert(object.Err, IsNil)
+			keys = append(keys, object.Key)
+		}
+		c.Assert(keys, DeepEquals, handler.keys)
+		if !refuse {
+			c.Assert(atomic.LoadInt32(&handler.v2Pages), Equals, int32(1))
+		}
+		server.Close()
+	}
+}
+
+// Test a non-recursive listing falling back to the V1 API after a common
+// prefix does not list it twice.
+func (s *TestSuite) TestListObjectsV1FallbackPrefixes(c *C) {
+	handler := &listV1Handler{keys: []string{"dir/a", "dir/b", "z"}, v2Token: true}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	s3c := newTestS3Client(c, server.URL+"/bucket")
+
+	var keys []string
+	for object := range s3c.listObjectWrapper(context.Background(), "bucket", "", false, time.Time{}, false, false, false, -1) {
+		c.Assert(object.Err, IsNil)
+		keys = append(keys, object.Key)
+	}
+	c.Assert(keys, DeepEquals, []string{"dir/", "z"})
+	c.Assert(atomic.LoadInt32(&handler.v2Pages), Equals, int32(2))
+}
+
 var testSelectCompressionTypeCases = []struct {
 	opts            SelectObjectOpts
 	object          string
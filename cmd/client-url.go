@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/mimedb"
 )
@@ -104,7 +105,7 @@ func newClientURL(urlStr string) *ClientURL {
 			rest = "/"
 		}
 		host := getHost(authority)
-		if host != "" && (scheme == "http" || scheme == "https") {
+		if host != "" && (scheme == "http" || scheme == "https" || isBackendPluginScheme(scheme)) {
 			return &ClientURL{
 				Scheme:          scheme,
 				Type:            objectStorage,
@@ -122,6 +123,39 @@ func newClientURL(urlStr string) *ClientURL {
 	}
 }
 
+// recursiveURLSuffix - suffix selecting everything under a folder
+// or prefix, e.g. `play/mybucket/photos...` is the same as passing
+// `play/mybucket/photos` with --recursive.
+const recursiveURLSuffix = "..."
+
+// trimRecursiveURL removes the recursive suffix from the URL, returns
+// true if the suffix was present.
+func trimRecursiveURL(urlStr string) (string, bool) {
+	if !strings.HasSuffix(urlStr, recursiveURLSuffix) {
+		return urlStr, false
+	}
+	return strings.TrimSuffix(urlStr, recursiveURLSuffix), true
+}
+
+// trimRecursiveArgs removes the recursive suffix from the URL arguments
+// of cp, mv, rm, ls, du and diff, the command is then run with
+// --recursive if it has the flag. Other commands keep their arguments
+// as they are, secrets or values may end with the suffix.
+func trimRecursiveArgs(ctx *cli.Context) {
+	// Args shares its storage with the parsed arguments of the command.
+	args := ctx.Args()
+	var isRecursive bool
+	for i := range args {
+		var ok bool
+		args[i], ok = trimRecursiveURL(args[i])
+		isRecursive = isRecursive || ok
+	}
+	if isRecursive {
+		// Commands without --recursive ignore the suffix.
+		ctx.Set("recursive", "true")
+	}
+}
+
 // joinURLs join two input urls and returns a url
 func joinURLs(url1, url2 *ClientURL) *ClientURL {
 	var url1Path, url2Path string
@@ -17,7 +17,13 @@
 
 package cmd
 
-import . "gopkg.in/check.v1"
+import (
+	"flag"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+	. "gopkg.in/check.v1"
+)
 
 // TestURL - tests url parsing and fields.
 func (s *TestSuite) TestURL(c *C) {
@@ -52,3 +58,51 @@ func (s *TestSuite) TestURLJoinPath(c *C) {
 	url = urlJoinPath(url1, url2)
 	c.Assert(url, Equals, "http://s3.mycompany.io/dev/mybucket/bin/")
 }
+
+// TestTrimRecursiveURL - tests removal of the recursive `...` suffix.
+func (s *TestSuite) TestTrimRecursiveURL(c *C) {
+	url, ok := trimRecursiveURL("play/mybucket/photos...")
+	c.Assert(ok, Equals, true)
+	c.Assert(url, Equals, "play/mybucket/photos")
+
+	url, ok = trimRecursiveURL("play/mybucket/photos/...")
+	c.Assert(ok, Equals, true)
+	c.Assert(url, Equals, "play/mybucket/photos/")
+
+	url, ok = trimRecursiveURL("play/mybucket/photos")
+	c.Assert(ok, Equals, false)
+	c.Assert(url, Equals, "play/mybucket/photos")
+}
+
+// TestTrimRecursiveArgs - tests the recursive suffix of arguments
+// selects --recursive if the command has the flag.
+func (s *TestSuite) TestTrimRecursiveArgs(c *C) {
+	for _, hasFlag := range []bool{true, false} {
+		set := flag.NewFlagSet("ls", flag.ContinueOnError)
+		if hasFlag {
+			cli.BoolFlag{Name: "recursive, r"}.Apply(set)
+		}
+		c.Assert(set.Parse([]string{"dir1", "dir2...", "play/mybucket"}), IsNil)
+		ctx := cli.NewContext(nil, set, nil)
+		ctx.Command = cli.Command{Name: "ls"}
+
+		trimRecursiveArgs(ctx)
+		c.Assert([]string(ctx.Args()), DeepEquals, []string{"dir1", "dir2", "play/mybucket"})
+		c.Assert(ctx.Bool("recursive"), Equals, hasFlag)
+	}
+}
+
+// TestSetGlobalsKeepsArgs - tests arguments of other commands ending
+// with the recursive suffix are left untouched.
+func (s *TestSuite) TestSetGlobalsKeepsArgs(c *C) {
+	set := flag.NewFlagSet("add", flag.ContinueOnError)
+	cli.IntFlag{Name: "max-retry", Value: minio.MaxRetry}.Apply(set)
+	cli.DurationFlag{Name: "retry-delay", Value: minio.DefaultRetryUnit}.Apply(set)
+	cli.IntFlag{Name: "walk-workers", Value: defaultWalkWorkers}.Apply(set)
+	c.Assert(set.Parse([]string{"myminio", "newuser", "secret..."}), IsNil)
+	ctx := cli.NewContext(nil, set, nil)
+	ctx.Command = cli.Command{Name: "add"}
+
+	c.Assert(setGlobalsFromContext(ctx), IsNil)
+	c.Assert([]string(ctx.Args()), DeepEquals, []string{"myminio", "newuser", "secret..."})
+}
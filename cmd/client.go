@@ -47,6 +47,14 @@ const (
 type GetOptions struct {
 	SSE       encrypt.ServerSide
 	VersionID string
+	// Parallel > 1 downloads objects larger than PartSize with
+	// that many concurrent ranged requests.
+	Parallel int
+	PartSize int64
+	// Fail with ObjectNotModified instead of downloading the object
+	// if it still has this ETag or was not modified since this time.
+	IfNoneMatch     string
+	IfModifiedSince time.Time
 }
 
 // PutOptions holds options for PUT operation
@@ -58,6 +66,8 @@ type PutOptions struct {
 	storageClass          string
 	multipartSize         uint64
 	multipartThreads      uint
+	resumable             bool
+	sparse                bool
 }
 
 // StatOptions holds options of the HEAD operation
@@ -79,6 +89,16 @@ type ListOptions struct {
 	TimeRef           time.Time
 	ShowDir           DirOpt
 	Count             int
+	// Unordered recursive listings of local folders may return
+	// entries out of lexical order, walking folders concurrently.
+	Unordered bool
+	// MaxDepth limits recursive listings to the given number of levels
+	// below the listed prefix, folders at the last level are returned
+	// as folders unless ShowDir is DirNone. No limit if 0.
+	MaxDepth int
+	// RelativePaths lists local entries with paths relative to the
+	// listed folder, using forward slashes as object keys do.
+	RelativePaths bool
 }
 
 // CopyOptions holds options for copying operation
@@ -89,6 +109,7 @@ type CopyOptions struct {
 	metadata         map[string]string
 	disableMultipart bool
 	isPreserve       bool
+	hardLinks        bool
 	storageClass     string
 }
 
@@ -101,6 +122,7 @@ type Client interface {
 	// Bucket operations
 	MakeBucket(ctx context.Context, region string, ignoreExisting, withLock bool) *probe.Error
 	RemoveBucket(ctx context.Context, forceRemove bool) *probe.Error
+	StatBucket(ctx context.Context) (content *ClientContent, err *probe.Error)
 
 	// Object lock config
 	SetObjectLockConfig(ctx context.Context, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit) *probe.Error
@@ -129,7 +151,8 @@ type Client interface {
 
 	// I/O operations with expiration
 	ShareDownload(ctx context.Context, versionID string, expires time.Duration) (string, *probe.Error)
-	ShareUpload(context.Context, bool, time.Duration, string) (string, map[string]string, *probe.Error)
+	ShareUpload(context.Context, bool, time.Duration, string, int64, int64) (string, map[string]string, *probe.Error)
+	Presign(ctx context.Context, method string, expires time.Duration) (string, *probe.Error)
 
 	// Watch events
 	Watch(ctx context.Context, options WatchOptions) (*WatchObject, *probe.Error)
@@ -140,6 +163,13 @@ type Client interface {
 	GetURL() ClientURL
 	AddUserAgent(app, version string)
 
+	// Object ACL operations
+	GetObjectACL(ctx context.Context) (owner string, grants []ClientGrant, err *probe.Error)
+
+	// Bucket ACL operations
+	GetBucketACL(ctx context.Context) (owner string, grants []ClientGrant, err *probe.Error)
+	SetBucketACL(ctx context.Context, acl string) *probe.Error
+
 	// Tagging operations
 	GetTags(ctx context.Context, versionID string) (map[string]string, *probe.Error)
 	SetTags(ctx context.Context, versionID, tags string) *probe.Error
@@ -165,10 +195,42 @@ type Client interface {
 	// Bucket info operation
 	GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error)
 
+	// Total size and count of the objects under the URL
+	DiskUsage(ctx context.Context, timeRef time.Time, withVersions bool) (DiskUsageInfo, *probe.Error)
+
 	// Restore an object
 	Restore(ctx context.Context, versionID string, days int) *probe.Error
 }
 
+// DiskUsageInfo - total size and count of the objects under a prefix.
+type DiskUsageInfo struct {
+	Size  int64
+	Count int64
+}
+
+// listDiskUsage sums the objects listed recursively by the client,
+// links that cannot be followed are not counted.
+func listDiskUsage(ctx context.Context, clnt Client, opts ListOptions) (DiskUsageInfo, *probe.Error) {
+	var usage DiskUsageInfo
+	opts.Recursive = true
+	opts.ShowDir = DirNone
+	for content := range clnt.List(ctx, opts) {
+		if content.Err != nil {
+			switch content.Err.ToGoError().(type) {
+			case BrokenSymlink, TooManyLevelsSymlink:
+				continue
+			}
+			return usage, content.Err.Trace(clnt.GetURL().String())
+		}
+		if content.IsDeleteMarker || content.Type.IsDir() {
+			continue
+		}
+		usage.Size += content.Size
+		usage.Count++
+	}
+	return usage, nil
+}
+
 // ClientContent - Content container for content metadata
 type ClientContent struct {
 	URL          ClientURL
@@ -192,15 +254,25 @@ type ClientContent struct {
 	LegalHoldEnabled  bool
 	LegalHold         string
 	VersionID         string
+	UploadID          string
 	IsDeleteMarker    bool
 	IsLatest          bool
 	ReplicationStatus string
+	Owner             string
+	Grants            []ClientGrant
+	Tags              map[string]string
 
 	Restore *minio.RestoreInfo
 
 	Err *probe.Error
 }
 
+// ClientGrant - permission granted by an object ACL
+type ClientGrant struct {
+	Grantee    string `json:"grantee"`
+	Permission string `json:"permission"`
+}
+
 // Config - see http://docs.amazonwebservices.com/AmazonS3/latest/dev/index.html?RESTAuthentication.html
 type Config struct {
 	AccessKey    string
@@ -214,6 +286,11 @@ type Config struct {
 	Insecure     bool
 	Lookup       minio.BucketLookupType
 	Transport    *http.Transport
+	Socket       string
+	// Skip AWS bucket name rules on non-AWS endpoints.
+	RelaxedBucketNames bool
+	// Object listing API, "v1", "v2" or empty to detect it.
+	ListAPI string
 }
 
 // SelectObjectOpts - opts entered for select API
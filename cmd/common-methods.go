@@ -226,7 +226,21 @@ func getSourceStream(ctx context.Context, alias, urlStr, versionID string, fetch
 	if err != nil {
 		return nil, nil, err.Trace(alias, urlStr)
 	}
-	reader, err = sourceClnt.Get(ctx, GetOptions{SSE: sse, VersionID: versionID})
+	getOpts := GetOptions{SSE: sse, VersionID: versionID}
+	if v := env.Get("MC_DOWNLOAD_PART_SIZE", ""); v != "" {
+		partSize, e := humanize.ParseBytes(v)
+		if e != nil {
+			return nil, nil, probe.NewError(e)
+		}
+		getOpts.PartSize = int64(partSize)
+	}
+	parallel, e := strconv.Atoi(env.Get("MC_DOWNLOAD_PARALLEL_THREADS", "1"))
+	if e != nil {
+		return nil, nil, probe.NewError(e)
+	}
+	getOpts.Parallel = parallel
+
+	reader, err = sourceClnt.Get(ctx, getOpts)
 	if err != nil {
 		return nil, nil, err.Trace(alias, urlStr)
 	}
@@ -405,6 +419,26 @@ func getAllMetadata(ctx context.Context, sourceAlias, sourceURLStr string, srcSS
 	return filterMetadata(metadata), nil
 }
 
+// isSameEndpoint returns true if two aliases reach the same endpoint with
+// the same credentials, objects are then copied on the server side.
+func isSameEndpoint(firstAlias, secondAlias string) bool {
+	if firstAlias == secondAlias {
+		return true
+	}
+	if firstAlias == "" || secondAlias == "" {
+		return false
+	}
+	first, second := mustGetHostConfig(firstAlias), mustGetHostConfig(secondAlias)
+	if first == nil || second == nil {
+		return false
+	}
+	return strings.TrimSuffix(first.URL, "/") == strings.TrimSuffix(second.URL, "/") &&
+		first.AccessKey == second.AccessKey &&
+		first.SecretKey == second.SecretKey &&
+		first.SessionToken == second.SessionToken &&
+		first.Socket == second.Socket
+}
+
 // uploadSourceToTargetURL - uploads to targetURL from source.
 // optionally optimizes copy for object sizes <= 5GiB by using
 // server side copy operation.
@@ -470,7 +504,7 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	}
 
 	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias {
+	if isSameEndpoint(sourceAlias, targetAlias) {
 		// preserve new metadata and save existing ones.
 		if preserve {
 			currentMetadata, err := getAllMetadata(ctx, sourceAlias, sourceURL.String(), srcSSE, urls)
@@ -504,6 +538,7 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			metadata:         filterMetadata(metadata),
 			disableMultipart: urls.DisableMultipart,
 			isPreserve:       preserve,
+			hardLinks:        urls.HardLinks,
 			storageClass:     urls.TargetContent.StorageClass,
 		}
 
@@ -575,6 +610,7 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			md5:              urls.MD5,
 			disableMultipart: urls.DisableMultipart,
 			isPreserve:       preserve,
+			resumable:        urls.Resumable,
 			multipartSize:    multipartSize,
 			multipartThreads: uint(multipartThreads),
 		}
@@ -613,6 +649,10 @@ func newClientFromAlias(alias, urlStr string) (Client, *probe.Error) {
 		return fsClient, nil
 	}
 
+	if executable, ok := lookupBackendPlugin(newClientURL(urlStr).Scheme); ok {
+		return newPluginClient(executable, urlStr, hostCfg), nil
+	}
+
 	s3Config := NewS3Config(urlStr, hostCfg)
 
 	s3Client, err := S3New(s3Config)
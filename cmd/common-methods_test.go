@@ -21,6 +21,8 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/minio/mc/pkg/probe"
 )
 
 func TestGetDecodedKey(t *testing.T) {
@@ -69,3 +71,31 @@ func TestGetDecodedKey(t *testing.T) {
 		}
 	}
 }
+
+func TestIsSameEndpoint(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	config := newMcConfig()
+	config.Aliases = map[string]aliasConfigV10{
+		"first":  {URL: "https://s3.example.com", AccessKey: "access", SecretKey: "secret"},
+		"second": {URL: "https://s3.example.com/", AccessKey: "access", SecretKey: "secret"},
+		"other":  {URL: "https://s3.example.com", AccessKey: "other", SecretKey: "secret"},
+	}
+	loadMcConfig = func() (*configV10, *probe.Error) { return config, nil }
+
+	testCases := []struct {
+		first, second string
+		same          bool
+	}{
+		{"", "", true},
+		{"first", "first", true},
+		{"first", "second", true},
+		{"first", "other", false},
+		{"first", "", false},
+		{"first", "missing", false},
+	}
+	for i, testCase := range testCases {
+		if same := isSameEndpoint(testCase.first, testCase.second); same != testCase.same {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.same, same)
+		}
+	}
+}
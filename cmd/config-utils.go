@@ -52,7 +52,7 @@ func trimTrailingSeparator(hostURL string) string {
 func isValidHostURL(hostURL string) (ok bool) {
 	if strings.TrimSpace(hostURL) != "" {
 		url := newClientURL(hostURL)
-		if url.Scheme == "https" || url.Scheme == "http" {
+		if url.Scheme == "https" || url.Scheme == "http" || isBackendPluginScheme(url.Scheme) {
 			if url.Path == "/" {
 				ok = true
 			}
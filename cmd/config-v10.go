@@ -46,6 +46,17 @@ type aliasConfigV10 struct {
 	Path         string `json:"path"`
 	License      string `json:"license,omitempty"`
 	APIKey       string `json:"apiKey,omitempty"`
+	// Unix domain socket to connect to instead of the URL host.
+	Socket string `json:"socket,omitempty"`
+	// Validate bucket names with AWS rules, "on" (default) or "off".
+	// Strict validation is always kept for AWS endpoints.
+	StrictAWSNames string `json:"strictAwsNames,omitempty"`
+	// Object listing API, "v1" or "v2". If unset ListObjectsV2
+	// is used, falling back to V1 for servers without it.
+	ListAPI string `json:"listApi,omitempty"`
+	// Default command line flags for commands operating on
+	// this alias, flags set on the command line take precedence.
+	Flags map[string]string `json:"flags,omitempty"`
 }
 
 // configV10 config version.
@@ -27,6 +27,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/env"
 
@@ -340,3 +341,45 @@ func mustExpandAlias(aliasedURL string) (alias string, urlStr string, aliasCfg *
 	alias, urlStr, aliasCfg, _ = expandAlias(aliasedURL)
 	return alias, urlStr, aliasCfg
 }
+
+// applyAliasFlags sets the default flags configured for the aliases
+// of urls, explicitly set flags are kept as is. Commands taking aliased
+// URLs call it once their arguments are parsed.
+func applyAliasFlags(ctx *cli.Context, urls []string) {
+	for _, urlStr := range urls {
+		alias, _ := url2Alias(urlStr)
+		if alias == "" || !isValidAlias(alias) {
+			continue
+		}
+		if aliasCfg := mustGetHostConfig(alias); aliasCfg != nil && len(aliasCfg.Flags) > 0 {
+			applyDefaultFlags(ctx, aliasCfg.Flags)
+		}
+	}
+}
+
+// applyDefaultFlags sets all names of the command flags found in
+// defaults unless the flag is already set. Flags not supported by the
+// command are ignored, so one alias can carry defaults for several commands.
+// IsSet keeps reporting only the flags given on the command line.
+func applyDefaultFlags(ctx *cli.Context, defaults map[string]string) {
+	for _, flag := range ctx.Command.Flags {
+		var names []string
+		for _, name := range strings.Split(flag.GetName(), ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+
+		value, found, isSet := "", false, false
+		for _, name := range names {
+			if v, ok := defaults[name]; ok && !found {
+				value, found = v, true
+			}
+			isSet = isSet || ctx.IsSet(name)
+		}
+		if !found || isSet {
+			continue
+		}
+		for _, name := range names {
+			ctx.Set(name, value)
+		}
+	}
+}
@@ -17,7 +17,13 @@
 
 package cmd
 
-import "testing"
+import (
+	"flag"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
 
 // Tests valid host URL functionality.
 func TestParseEnvURLStr(t *testing.T) {
@@ -142,3 +148,80 @@ func TestParseEnvURLStrInvalid(t *testing.T) {
 		t.Fatalf("Expected failure")
 	}
 }
+
+func TestApplyDefaultFlags(t *testing.T) {
+	flags := []cli.Flag{
+		cli.StringFlag{Name: "storage-class, sc"},
+		cli.StringFlag{Name: "attr"},
+		cli.BoolFlag{Name: "recursive, r"},
+		cli.BoolFlag{Name: "preserve, a"},
+	}
+	set := flag.NewFlagSet("cp", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(set)
+	}
+	if e := set.Parse([]string{"--attr", "key=value", "--preserve"}); e != nil {
+		t.Fatal(e)
+	}
+	ctx := cli.NewContext(nil, set, nil)
+	ctx.Command = cli.Command{Name: "cp", Flags: flags}
+
+	applyDefaultFlags(ctx, map[string]string{
+		"sc":       "REDUCED_REDUNDANCY",
+		"attr":     "key=default",
+		"preserve": "false",
+		"parallel": "4",
+		"r":        "true",
+	})
+
+	if sc := ctx.String("storage-class"); sc != "REDUCED_REDUNDANCY" {
+		t.Errorf("expected default storage class, got %q", sc)
+	}
+	if !ctx.Bool("recursive") {
+		t.Errorf("expected default recursive flag")
+	}
+	if ctx.IsSet("storage-class") {
+		t.Errorf("expected defaults not to be reported as set on the command line")
+	}
+	if attr := ctx.String("attr"); attr != "key=value" {
+		t.Errorf("expected explicit attr to take precedence, got %q", attr)
+	}
+	if !ctx.Bool("preserve") {
+		t.Errorf("expected explicit preserve to take precedence")
+	}
+}
+
+func TestApplyAliasFlags(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) {
+		cfg := newMcConfig()
+		cfg.Aliases["gateway"] = aliasConfigV10{
+			URL:   "http://10.0.0.5:9000",
+			API:   "S3v4",
+			Path:  "auto",
+			Flags: map[string]string{"storage-class": "REDUCED_REDUNDANCY"},
+		}
+		return cfg, nil
+	}
+
+	flags := []cli.Flag{cli.StringFlag{Name: "storage-class, sc"}}
+	set := flag.NewFlagSet("cp", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(set)
+	}
+	if e := set.Parse([]string{"dir/", "gateway/bucket"}); e != nil {
+		t.Fatal(e)
+	}
+	ctx := cli.NewContext(nil, set, nil)
+	ctx.Command = cli.Command{Name: "cp", Flags: flags}
+
+	// Defaults are applied only for the given URLs.
+	applyAliasFlags(ctx, ctx.Args()[:1])
+	if sc := ctx.String("storage-class"); sc != "" {
+		t.Errorf("expected no default storage class, got %q", sc)
+	}
+	applyAliasFlags(ctx, ctx.Args())
+	if sc := ctx.String("storage-class"); sc != "REDUCED_REDUNDANCY" {
+		t.Errorf("expected default storage class of the alias, got %q", sc)
+	}
+}
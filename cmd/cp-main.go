@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	"github.com/fatih/color"
 	jsoniter "github.com/json-iterator/go"
@@ -82,14 +83,44 @@ var (
 			Name:  "disable-multipart",
 			Usage: "disable multipart upload feature",
 		},
+		cli.BoolFlag{
+			Name:  "hard-links",
+			Usage: "recreate hard links between local source files at a local target",
+		},
+		cli.BoolFlag{
+			Name:  "rename-case-collisions",
+			Usage: "copy sources differing only in case to a case-insensitive local target with a numbered suffix",
+		},
 		cli.BoolFlag{
 			Name:  "md5",
 			Usage: "force all upload(s) to calculate md5sum checksum",
 		},
+		cli.BoolFlag{
+			Name:  "continue-on-error",
+			Usage: "copy the remaining objects of a session when an object fails to copy",
+		},
+		cli.StringFlag{
+			Name:  "error-report",
+			Usage: "record failed objects into a report file",
+		},
+		cli.StringFlag{
+			Name:  "retry-from",
+			Usage: "copy again the failed objects recorded in a report file",
+		},
 		cli.StringFlag{
 			Name:  "tags",
 			Usage: "apply one or more tags to the uploaded objects",
 		},
+		cli.BoolFlag{
+			Name:  "queue",
+			Usage: "queue objects to a local spool when the target is unreachable, replay them with 'mc flush'",
+		},
+		cli.StringFlag{
+			Name:   "queue-max-size",
+			Value:  defaultQueueMaxSize,
+			Usage:  "limit the size of the data queued to the local spool",
+			EnvVar: "MC_QUEUE_MAX_SIZE",
+		},
 		cli.StringFlag{
 			Name:  rmFlag,
 			Usage: "retention mode to be applied on the object (governance, compliance)",
@@ -176,7 +207,8 @@ EXAMPLES:
   13. Copy a text file to an object storage and assign REDUCED_REDUNDANCY storage-class to the uploaded object.
       {{.Prompt}} {{.HelpName}} --storage-class REDUCED_REDUNDANCY myobject.txt play/mybucket
 
-  14. Copy a text file to an object storage and create or resume copy session.
+  14. Copy a folder to an object storage and create or resume copy session, re-running an interrupted command offers to resume it as well.
+      Large files interrupted during upload resume from their last uploaded part.
       {{.Prompt}} {{.HelpName}} --recursive --continue dir/ play/mybucket
 
   15. Copy a text file to an object storage and preserve the file system attribute as metadata.
@@ -197,6 +229,40 @@ EXAMPLES:
   20. Set tags to the uploaded objects
       {{.Prompt}} {{.HelpName}} -r --tags "category=prod&type=backup" ./data/ play/another-bucket/
 
+  21. Copy a local file, an object and a folder into a single target folder. Sources resolving to the same target name are reported as an error.
+      {{.Prompt}} {{.HelpName}} --recursive notes.txt play/mybucket/report.pdf backup/2015/ s3/archive/
+
+  22. Copy the folder 'photos' itself rather than its contents, a trailing slash copies only the contents of a folder.
+      {{.Prompt}} {{.HelpName}} --recursive ~/photos play/mybucket/
+
+  23. Copy a prefix recursively using the '...' suffix, same as --recursive.
+      {{.Prompt}} {{.HelpName}} play/mybucket/burningman2011/... s3/mybucket/
+
+  24. Copy an object over itself to restore a noncurrent version, or to rewrite it with another storage class.
+      {{.Prompt}} {{.HelpName}} --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" play/mybucket/report.pdf play/mybucket/report.pdf
+      {{.Prompt}} {{.HelpName}} --storage-class REDUCED_REDUNDANCY play/mybucket/report.pdf play/mybucket/report.pdf
+
+  25. Copy a folder recording failed objects into a report, then copy again only the failed objects.
+      {{.Prompt}} {{.HelpName}} --recursive --error-report failed.json dir/ play/mybucket
+      {{.Prompt}} {{.HelpName}} --retry-from failed.json --error-report failed.json
+
+  26. Copy a folder from a laptop, queuing the objects locally while the target is unreachable, then replay them later.
+      {{.Prompt}} {{.HelpName}} --recursive --queue --queue-max-size 5GiB ~/photos/ play/mybucket
+      {{.Prompt}} mc flush
+
+  27. Copy a local folder to another disk, linking the hard linked files of the source instead of copying them twice.
+      {{.Prompt}} {{.HelpName}} --recursive --hard-links /data/ /mnt/backup/data/
+
+  28. Copy the local files matching a pattern, '**' matches any number of folders. Quote the pattern to leave the expansion to mc.
+      {{.Prompt}} {{.HelpName}} '/data/logs/**/2015-*.gz' play/mybucket/logs/
+
+  29. Back up a local folder keeping the mode and owner of the files, then restore it, the owner is restored when running as root.
+      {{.Prompt}} {{.HelpName}} --recursive --preserve /home/ play/backup/home/
+      {{.Prompt}} {{.HelpName}} --recursive --preserve play/backup/home/ /home/
+
+  30. Download a bucket to a case-insensitive folder, objects differing only in case such as 'README' and 'readme' are saved as 'README' and 'readme (1)'.
+      {{.Prompt}} {{.HelpName}} --recursive --rename-case-collisions play/mybucket/ ~/mybucket/
+
 `,
 }
 
@@ -308,7 +374,8 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 		scanBar = scanBarFactory()
 	}
 
-	URLsCh := prepareCopyURLs(ctx, sourceURLs, targetURL, isRecursive, encKeyDB, olderThan, newerThan, parseRewindFlag(rewind), versionID)
+	renameCaseCollisions := session.Header.CommandBoolFlags["rename-case-collisions"]
+	URLsCh := prepareCopyURLs(ctx, sourceURLs, targetURL, isRecursive, encKeyDB, olderThan, newerThan, parseRewindFlag(rewind), versionID, renameCaseCollisions)
 	done := false
 	for !done {
 		select {
@@ -361,7 +428,19 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 	return
 }
 
-func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.Context, session *sessionV8, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) error {
+// isCopyEntryErr returns true if err is specific to a single entry of
+// the copy plan, such entries fail while the others are copied.
+func isCopyEntryErr(err *probe.Error) bool {
+	switch err.ToGoError().(type) {
+	case duplicateTargetErr, caseCollisionErr:
+		return true
+	}
+	return false
+}
+
+// doCopySession copies sourceURLs, as returned by checkCopySyntax, to the
+// last argument or resumes session if not nil.
+func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.Context, sourceURLs []string, session *sessionV8, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) error {
 	var isCopied func(string) bool
 	var totalObjects, totalBytes int64
 
@@ -377,16 +456,45 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		pg = newAccounter(totalBytes)
 	}
 
-	sourceURLs := cli.Args()[:len(cli.Args())-1]
-	targetURL := cli.Args()[len(cli.Args())-1] // Last one is target
+	// Failed objects of a previous run are read before the
+	// report is created, both may use the same file.
+	var retryEntries []failureReportEntry
+	retryFrom := cli.String("retry-from")
+	if retryFrom != "" {
+		var err *probe.Error
+		retryEntries, err = readFailureReport(retryFrom)
+		fatalIf(err.Trace(retryFrom), "Unable to read failure report.")
+	}
 
-	tgtClnt, err := newClient(targetURL)
-	fatalIf(err, "Unable to initialize `"+targetURL+"`.")
+	var report *failureReport
+	if reportPath := cli.String("error-report"); reportPath != "" {
+		var err *probe.Error
+		report, err = newFailureReport(reportPath)
+		fatalIf(err.Trace(reportPath), "Unable to create failure report.")
+		defer report.Close()
+	}
+	continueOnError := cli.Bool("continue-on-error")
+
+	var queue *offlineQueue
+	if cli.Bool("queue") {
+		var err *probe.Error
+		queue, err = newOfflineQueue(cli.String("queue-max-size"))
+		fatalIf(err, "Unable to open the offline queue.")
+		console.SetColor("Queued", color.New(color.FgYellow))
+	}
 
-	// Check if the target bucket has object locking enabled
+	var targetURL string
 	var withLock bool
-	if _, _, _, _, err = tgtClnt.GetObjectLockConfig(ctx); err == nil {
-		withLock = true
+	if retryFrom == "" {
+		targetURL = cli.Args()[len(cli.Args())-1] // Last one is target
+
+		tgtClnt, err := newClient(targetURL)
+		fatalIf(err, "Unable to initialize `"+targetURL+"`.")
+
+		// Check if the target bucket has object locking enabled
+		if _, _, _, _, err = tgtClnt.GetObjectLockConfig(ctx); err == nil {
+			withLock = true
+		}
 	}
 
 	if session != nil {
@@ -421,6 +529,43 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 				cpURLsCh <- cpURLs
 			}
 		}()
+	} else if retryFrom != "" {
+		go func() {
+			var scannedBytes, scannedObjects int64
+			// Object lock requires Content-MD5, targets may differ per entry.
+			bucketLocks := make(map[string]bool)
+			for _, entry := range retryEntries {
+				source, target := entry.resolve(entry.Source), entry.resolve(entry.Target)
+				alias, path := url2Alias(target)
+				lockKey := alias + "/" + strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+				entryWithLock, ok := bucketLocks[lockKey]
+				if !ok {
+					if tgtClnt, err := newClient(target); err == nil {
+						_, _, _, _, err = tgtClnt.GetObjectLockConfig(ctx)
+						entryWithLock = err == nil
+					}
+					bucketLocks[lockKey] = entryWithLock
+				}
+				// Entries are single objects copied to their target.
+				cpURLs := prepareCopyURLsTypeA(ctx, source, entry.VersionID, target, encKeyDB)
+				if cpURLs.Error != nil {
+					// Reported and recorded like failed copies.
+					cpURLs.SourceContent = &ClientContent{URL: *newClientURL(source), VersionID: entry.VersionID}
+					cpURLs.TargetContent = &ClientContent{URL: *newClientURL(target)}
+					cpURLsCh <- cpURLs
+					continue
+				}
+				scannedBytes += cpURLs.SourceContent.Size
+				scannedObjects++
+				pg.SetTotal(scannedBytes)
+				atomic.StoreInt64(&totalObjects, scannedObjects)
+				cpURLs.TotalCount = scannedObjects
+				cpURLs.TotalSize = scannedBytes
+				cpURLs.MD5 = entryWithLock
+				cpURLsCh <- cpURLs
+			}
+			close(cpURLsCh)
+		}()
 	} else {
 		// Access recursive flag inside the session header.
 		isRecursive := cli.Bool("recursive")
@@ -429,11 +574,23 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		rewind := cli.String("rewind")
 		versionID := cli.String("version-id")
 
+		// Totals grow while the sources are still being scanned, the
+		// overall percentage and ETA are an estimate until then.
+		if progressReader, ok := pg.(*progressBar); ok {
+			progressReader.SetEstimating(true)
+		}
+
 		go func() {
-			totalBytes := int64(0)
+			var scannedBytes, scannedObjects int64
 			for cpURLs := range prepareCopyURLs(ctx, sourceURLs, targetURL, isRecursive,
-				encKeyDB, olderThan, newerThan, parseRewindFlag(rewind), versionID) {
+				encKeyDB, olderThan, newerThan, parseRewindFlag(rewind), versionID, cli.Bool("rename-case-collisions")) {
 				if cpURLs.Error != nil {
+					if isCopyEntryErr(cpURLs.Error) {
+						// Reported and recorded like failed copies,
+						// the remaining sources are still copied.
+						cpURLsCh <- cpURLs
+						continue
+					}
 					// Print in new line and adjust to top so that we
 					// don't print over the ongoing scan bar
 					if !globalQuiet && !globalJSON {
@@ -449,12 +606,18 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 					}
 					break
 				} else {
-					totalBytes += cpURLs.SourceContent.Size
-					pg.SetTotal(totalBytes)
-					totalObjects++
+					scannedBytes += cpURLs.SourceContent.Size
+					scannedObjects++
+					pg.SetTotal(scannedBytes)
+					atomic.StoreInt64(&totalObjects, scannedObjects)
 				}
+				cpURLs.TotalCount = scannedObjects
+				cpURLs.TotalSize = scannedBytes
 				cpURLsCh <- cpURLs
 			}
+			if progressReader, ok := pg.(*progressBar); ok {
+				progressReader.SetEstimating(false)
+			}
 			close(cpURLsCh)
 		}()
 	}
@@ -481,11 +644,19 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 					return
 				}
 
-				// Save total count.
-				cpURLs.TotalCount = totalObjects
+				if cpURLs.Error != nil {
+					parallel.queueTask(func() URLs {
+						return cpURLs
+					}, 0, "")
+					continue
+				}
 
-				// Save totalSize.
-				cpURLs.TotalSize = totalBytes
+				// Totals of a session are known upfront, otherwise
+				// they are the running totals of the scan.
+				if session != nil {
+					cpURLs.TotalCount = totalObjects
+					cpURLs.TotalSize = totalBytes
+				}
 
 				// Initialize target metadata.
 				cpURLs.TargetContent.Metadata = make(map[string]string)
@@ -522,18 +693,21 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 					}
 				}
 
-				cpURLs.MD5 = cli.Bool("md5") || withLock
+				cpURLs.MD5 = cpURLs.MD5 || cli.Bool("md5") || withLock
 				cpURLs.DisableMultipart = cli.Bool("disable-multipart")
+				cpURLs.HardLinks = cli.Bool("hard-links")
+				// Uploads of sessions resume from their last part.
+				cpURLs.Resumable = session != nil
 
 				// Verify if previously copied, notify progress bar.
 				if isCopied != nil && isCopied(cpURLs.SourceContent.URL.String()) {
 					parallel.queueTask(func() URLs {
 						return doCopyFake(ctx, cpURLs, pg)
-					}, 0)
+					}, 0, taskHost(cpURLs))
 				} else {
 					parallel.queueTask(func() URLs {
 						return doCopy(ctx, cpURLs, pg, encKeyDB, isMvCmd, preserve)
-					}, cpURLs.SourceContent.Size)
+					}, cpURLs.SourceContent.Size, taskHost(cpURLs))
 				}
 			}
 		}
@@ -569,6 +743,22 @@ loop:
 				}
 				cpAllFilesErr = false
 			} else {
+				if queue != nil && isTargetUnreachable(cpURLs) {
+					entry, err := queue.add(ctx, cpURLs, encKeyDB, cli.Bool("preserve"))
+					if err == nil {
+						if !globalQuiet && !globalJSON {
+							console.Eraseline()
+						}
+						printMsg(queuedMessage{Source: entry.Source, Target: entry.Target, Size: entry.Size})
+						if session != nil {
+							session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+							session.Save()
+						}
+						cpAllFilesErr = false
+						continue loop
+					}
+					errorIf(err, "Unable to queue `%s`.", cpURLs.SourceContent.URL.String())
+				}
 
 				// Set exit status for any copy error
 				retErr = exitStatus(globalErrorExitStatus)
@@ -580,6 +770,7 @@ loop:
 				}
 				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
 					fmt.Sprintf("Failed to copy `%s`.", cpURLs.SourceContent.URL.String()))
+				report.add(cpURLs)
 				if isErrIgnored(cpURLs.Error) {
 					cpAllFilesErr = false
 					continue loop
@@ -598,10 +789,11 @@ loop:
 					}
 				}
 
-				if session != nil {
+				if session != nil && !continueOnError {
 					// For critical errors we should exit. Session
 					// can be resumed after the user figures out
 					// the  problem.
+					report.Close()
 					session.copyCloseAndDie(session.Header.CommandBoolFlags["session"])
 				}
 			}
@@ -609,7 +801,8 @@ loop:
 	}
 
 	if progressReader, ok := pg.(*progressBar); ok {
-		if (errSeen && totalObjects == 1) || (cpAllFilesErr && totalObjects > 1) {
+		objects := atomic.LoadInt64(&totalObjects)
+		if (errSeen && objects == 1) || (cpAllFilesErr && objects > 1) {
 			console.Eraseline()
 		} else if progressReader.ProgressBar.Get() > 0 {
 			progressReader.ProgressBar.Finish()
@@ -625,6 +818,9 @@ loop:
 
 // mainCopy is the entry point for cp command.
 func mainCopy(cliCtx *cli.Context) error {
+	trimRecursiveArgs(cliCtx)
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelCopy := context.WithCancel(globalContext)
 	defer cancelCopy()
 
@@ -639,8 +835,15 @@ func mainCopy(cliCtx *cli.Context) error {
 		fatalIf(err, "Unable to parse attribute %v", cliCtx.String("attr"))
 	}
 
+	// Retry the failed objects of a previous run.
+	if cliCtx.String("retry-from") != "" {
+		checkCopyRetrySyntax(cliCtx)
+		console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+		return doCopySession(ctx, cancelCopy, cliCtx, nil, nil, encKeyDB, false)
+	}
+
 	// check 'copy' cli arguments.
-	checkCopySyntax(ctx, cliCtx, encKeyDB, false)
+	sourceURLs := checkCopySyntax(ctx, cliCtx, encKeyDB, false)
 
 	// Additional command specific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
@@ -668,8 +871,9 @@ func mainCopy(cliCtx *cli.Context) error {
 
 	var session *sessionV8
 
-	if cliCtx.Bool("continue") {
-		sessionID := getHash("cp", os.Args[1:])
+	// Re-running an interrupted command offers to resume its session.
+	sessionID := getCommandSessionID(cliCtx, getHash("cp", os.Args[1:]))
+	if cliCtx.Bool("continue") || promptResumeSession(sessionID) {
 		if isSessionExists(sessionID) {
 			session, err = loadSessionV8(sessionID)
 			fatalIf(err.Trace(sessionID), "Unable to load session.")
@@ -696,6 +900,8 @@ func mainCopy(cliCtx *cli.Context) error {
 			session.Header.UserMetaData = userMetaMap
 			session.Header.CommandBoolFlags["md5"] = cliCtx.Bool("md5")
 			session.Header.CommandBoolFlags["disable-multipart"] = cliCtx.Bool("disable-multipart")
+			session.Header.CommandBoolFlags["hard-links"] = cliCtx.Bool("hard-links")
+			session.Header.CommandBoolFlags["rename-case-collisions"] = cliCtx.Bool("rename-case-collisions")
 
 			var e error
 			if session.Header.RootPath, e = os.Getwd(); e != nil {
@@ -703,12 +909,12 @@ func mainCopy(cliCtx *cli.Context) error {
 				fatalIf(probe.NewError(e), "Unable to get current working folder.")
 			}
 
-			// extract URLs.
-			session.Header.CommandArgs = cliCtx.Args()
+			// extract URLs, with the patterns expanded.
+			session.Header.CommandArgs = append(sourceURLs, cliCtx.Args()[cliCtx.NArg()-1])
 		}
 	}
 
-	e := doCopySession(ctx, cancelCopy, cliCtx, session, encKeyDB, false)
+	e := doCopySession(ctx, cancelCopy, cliCtx, sourceURLs, session, encKeyDB, false)
 	if session != nil {
 		session.Delete()
 	}
@@ -18,8 +18,16 @@
 package cmd
 
 import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 )
 
 func TestParseMetaData(t *testing.T) {
@@ -74,3 +82,53 @@ func TestParseMetaData(t *testing.T) {
 		}
 	}
 }
+
+func TestCopyDuplicateTargets(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newMcConfig(), nil }
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	root, e := ioutil.TempDir("", "cp-main-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"a/file", "b/file", "b/other", "tgt/.keep"} {
+		path := filepath.Join(root, name)
+		if e = os.MkdirAll(filepath.Dir(path), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(name), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	sourceURLs := []string{filepath.Join(root, "a", "file"), filepath.Join(root, "b", "file"), filepath.Join(root, "b", "other")}
+	targetURL := filepath.Join(root, "tgt") + string(filepath.Separator)
+
+	set := flag.NewFlagSet("cp", flag.ContinueOnError)
+	if e = set.Parse(append(sourceURLs, targetURL)); e != nil {
+		t.Fatal(e)
+	}
+	ctx, cancelCopy := context.WithCancel(context.Background())
+	defer cancelCopy()
+
+	e = doCopySession(ctx, cancelCopy, cli.NewContext(nil, set, nil), sourceURLs, nil, nil, false)
+	exitErr, ok := e.(*cli.ExitError)
+	if !ok || exitErr.ExitCode() != globalErrorExitStatus {
+		t.Errorf("expected exit status %d, got %v", globalErrorExitStatus, e)
+	}
+
+	// The first source wins, the others are still copied.
+	for name, expected := range map[string]string{"file": "a/file", "other": "b/other"} {
+		data, e := ioutil.ReadFile(filepath.Join(root, "tgt", name))
+		if e != nil {
+			t.Errorf("expected `%s` to be copied: %s", name, e)
+			continue
+		}
+		if string(data) != expected {
+			t.Errorf("expected `%s` to contain `%s`, got `%s`", name, expected, data)
+		}
+	}
+}
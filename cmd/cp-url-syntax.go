@@ -28,7 +28,22 @@ import (
 	"github.com/minio/pkg/console"
 )
 
-func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) {
+// checkCopyRetrySyntax - validate a retry of the objects recorded in a failure report.
+func checkCopyRetrySyntax(cliCtx *cli.Context) {
+	if cliCtx.Args().Present() {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--retry-from does not accept source and target arguments, they are read from the report.")
+	}
+	for _, flag := range []string{"continue", "recursive", "version-id", "rewind", "older-than", "newer-than"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(), "--retry-from cannot be used with --"+flag+".")
+		}
+	}
+	checkMD5Flag(cliCtx)
+}
+
+// checkCopySyntax validates the arguments of cp and mv, it returns the
+// source URLs with their glob patterns expanded.
+func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) []string {
 	if len(cliCtx.Args()) < 2 {
 		if isMvCmd {
 			cli.ShowCommandHelpAndExit(cliCtx, "mv", 1) // last argument is exit code.
@@ -42,12 +57,15 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errDummy().Trace(cliCtx.Args()...), "Unable to parse source and target arguments.")
 	}
 
-	srcURLs := URLs[:len(URLs)-1]
+	expandedURLs := expandGlobURLs(ctx, URLs[:len(URLs)-1])
+	srcURLs := expandedURLs
 	tgtURL := URLs[len(URLs)-1]
 	isRecursive := cliCtx.Bool("recursive")
 	timeRef := parseRewindFlag(cliCtx.String("rewind"))
 	versionID := cliCtx.String("version-id")
 
+	checkMD5Flag(cliCtx, cliCtx.Args()...)
+
 	if versionID != "" && len(srcURLs) > 1 {
 		fatalIf(errDummy().Trace(cliCtx.Args()...), "Unable to pass --version flag with multiple copy sources arguments.")
 	}
@@ -78,6 +96,15 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		}
 	}
 
+	// Fail before transferring anything if the target bucket is missing.
+	if clnt, err := newClient(tgtURL); err == nil && clnt.GetURL().Type == objectStorage {
+		if _, err = clnt.StatBucket(ctx); err != nil {
+			if _, ok := err.ToGoError().(BucketDoesNotExist); ok {
+				fatalIf(err.Trace(tgtURL), "Unable to validate target `"+tgtURL+"`.")
+			}
+		}
+	}
+
 	if cliCtx.String(rdFlag) != "" && cliCtx.String(rmFlag) == "" {
 		fatalIf(errInvalidArgument().Trace(), fmt.Sprintf("Both object retention flags `--%s` and `--%s` are required.\n", rdFlag, rmFlag))
 	}
@@ -103,7 +130,7 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		if len(srcURLs) != 1 {
 			fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
 		}
-		checkCopySyntaxTypeA(ctx, srcURLs[0], versionID, tgtURL, encKeyDB, isMvCmd, timeRef)
+		checkCopySyntaxTypeA(ctx, cliCtx, srcURLs[0], versionID, tgtURL, encKeyDB, isMvCmd, timeRef)
 	case copyURLsTypeB: // File -> Folder.
 		// Check source.
 		if len(srcURLs) != 1 {
@@ -122,16 +149,47 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 	if cliCtx.Bool("preserve") && runtime.GOOS == "windows" {
 		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
 	}
+	return expandedURLs
+}
+
+// Flags of cp changing the object copied, a copy of an object onto
+// itself with any of them rewrites the object in place.
+var copyRewriteFlags = []string{"attr", "tags", "storage-class", "encrypt", "encrypt-key", rmFlag, rdFlag, lhFlag}
+
+// checkCopyOntoItself returns why copying an object onto itself is not
+// allowed, or an empty string if the copy changes the object: it restores
+// a noncurrent version or sets metadata, storage class or encryption.
+func checkCopyOntoItself(cliCtx *cli.Context, srcURL, versionID string, isMvCmd bool) string {
+	if isMvCmd {
+		return "Moving `" + srcURL + "` onto itself is not allowed."
+	}
+	if versionID != "" {
+		return ""
+	}
+	for _, flag := range copyRewriteFlags {
+		if cliCtx.String(flag) != "" {
+			return ""
+		}
+	}
+	return "Copying `" + srcURL + "` onto itself does not change it, use --version-id to restore an older version or set metadata, storage class or encryption to rewrite it."
 }
 
 // checkCopySyntaxTypeA verifies if the source and target are valid file arguments.
-func checkCopySyntaxTypeA(ctx context.Context, srcURL, versionID string, tgtURL string, keys map[string][]prefixSSEPair, isMvCmd bool, timeRef time.Time) {
+func checkCopySyntaxTypeA(ctx context.Context, cliCtx *cli.Context, srcURL, versionID string, tgtURL string, keys map[string][]prefixSSEPair, isMvCmd bool, timeRef time.Time) {
 	_, srcContent, err := url2Stat(ctx, srcURL, versionID, false, keys, timeRef)
 	fatalIf(err.Trace(srcURL), "Unable to stat source `"+srcURL+"`.")
 
 	if !srcContent.Type.IsRegular() {
 		fatalIf(errInvalidArgument().Trace(), "Source `"+srcURL+"` is not a file.")
 	}
+
+	_, expandedSrcURL, _ := mustExpandAlias(srcURL)
+	_, expandedTgtURL, _ := mustExpandAlias(tgtURL)
+	if expandedSrcURL == expandedTgtURL {
+		if msg := checkCopyOntoItself(cliCtx, srcURL, versionID, isMvCmd); msg != "" {
+			fatalIf(errInvalidArgument().Trace(srcURL, tgtURL), msg)
+		}
+	}
 }
 
 // checkCopySyntaxTypeB verifies if the source is a valid file and target is a valid folder.
@@ -0,0 +1,62 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckCopyOntoItself(t *testing.T) {
+	testCases := []struct {
+		args      []string
+		versionID string
+		isMvCmd   bool
+		allowed   bool
+	}{
+		// Copies not changing the object.
+		{nil, "", false, false},
+		{nil, "", true, false},
+		{[]string{"--attr", "k=v"}, "", true, false},
+		// Restoring a noncurrent version.
+		{nil, "3ddac055-89a7-40fa-8cd3-530a5581b6b8", false, true},
+		// Rewriting the object in place.
+		{[]string{"--attr", "k=v"}, "", false, true},
+		{[]string{"--tags", "k=v"}, "", false, true},
+		{[]string{"--storage-class", "REDUCED_REDUNDANCY"}, "", false, true},
+		{[]string{"--encrypt", "play/mybucket"}, "", false, true},
+		{[]string{"--encrypt-key", "play/mybucket=32byteslongsecretkeymustbegiven1"}, "", false, true},
+		{[]string{"--" + rmFlag, "governance", "--" + rdFlag, "1d"}, "", false, true},
+	}
+
+	for i, testCase := range testCases {
+		set := flag.NewFlagSet("cp", flag.ContinueOnError)
+		for _, name := range copyRewriteFlags {
+			cli.StringFlag{Name: name}.Apply(set)
+		}
+		if e := set.Parse(testCase.args); e != nil {
+			t.Fatal(e)
+		}
+		msg := checkCopyOntoItself(cli.NewContext(nil, set, nil), "play/mybucket/object", testCase.versionID, testCase.isMvCmd)
+		if allowed := msg == ""; allowed != testCase.allowed {
+			t.Errorf("Test %d: expected allowed %t, got %q", i+1, testCase.allowed, msg)
+		}
+	}
+}
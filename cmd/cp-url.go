@@ -19,6 +19,8 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -158,7 +160,7 @@ func makeCopyContentTypeB(sourceAlias string, sourceContent *ClientContent, targ
 
 // SINGLE SOURCE - Type C: copy(d1..., d2) -> []copy(d1/f, d1/d2/f) -> []A
 // prepareCopyRecursiveURLTypeC - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRecursive bool, timeRef time.Time, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRecursive, unordered bool, timeRef time.Time, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
 	// Extract alias before fiddling with the clientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
 	// Find alias and expanded clientURL.
@@ -173,14 +175,15 @@ func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRe
 			return
 		}
 
-		for sourceContent := range sourceClient.List(ctx, ListOptions{Recursive: isRecursive, TimeRef: timeRef, ShowDir: DirNone}) {
+		for sourceContent := range sourceClient.List(ctx, ListOptions{Recursive: isRecursive, TimeRef: timeRef, ShowDir: DirNone, Unordered: unordered}) {
 			if sourceContent.Err != nil {
 				// Listing failed.
 				copyURLsCh <- URLs{Error: sourceContent.Err.Trace(sourceClient.GetURL().String())}
 				continue
 			}
 
-			if !sourceContent.Type.IsRegular() {
+			// Links are listed as they are with --symlinks link.
+			if !sourceContent.Type.IsRegular() && sourceContent.Type&os.ModeSymlink == 0 {
 				// Source is not a regular file. Skip it for copy.
 				continue
 			}
@@ -193,11 +196,16 @@ func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRe
 }
 
 // makeCopyContentTypeC - CopyURLs content for copying.
+//
+// Follows rsync semantics, a source with a trailing separator such as
+// `dir/` copies the contents of the folder into the target, while `dir`
+// copies the folder itself i.e target/dir/... This applies equally to
+// local folders, buckets and prefixes.
 func makeCopyContentTypeC(sourceAlias string, sourceURL ClientURL, sourceContent *ClientContent, targetAlias string, targetURL string, encKeyDB map[string][]prefixSSEPair) URLs {
 	newSourceURL := sourceContent.URL
 	pathSeparatorIndex := strings.LastIndex(sourceURL.Path, string(sourceURL.Separator))
 	newSourceSuffix := filepath.ToSlash(newSourceURL.Path)
-	if pathSeparatorIndex > 1 {
+	if pathSeparatorIndex > 0 {
 		sourcePrefix := filepath.ToSlash(sourceURL.Path[:pathSeparatorIndex])
 		newSourceSuffix = strings.TrimPrefix(newSourceSuffix, sourcePrefix)
 	}
@@ -207,12 +215,25 @@ func makeCopyContentTypeC(sourceAlias string, sourceURL ClientURL, sourceContent
 
 // MULTI-SOURCE - Type D: copy([](f|d...), d) -> []B
 // prepareCopyURLsTypeE - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeD(ctx context.Context, sourceURLs []string, targetURL string, isRecursive bool, timeRef time.Time, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+func prepareCopyURLsTypeD(ctx context.Context, sourceURLs []string, targetURL string, isRecursive, unordered bool, timeRef time.Time, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs) {
 		defer close(copyURLsCh)
+		// Remember which source produced every target, sources of different
+		// kinds may resolve to the same name in the target folder and must
+		// not silently overwrite each other.
+		targetSources := make(map[string]string)
 		for _, sourceURL := range sourceURLs {
-			for cpURLs := range prepareCopyURLsTypeC(ctx, sourceURL, targetURL, isRecursive, timeRef, encKeyDB) {
+			for cpURLs := range prepareCopyURLsTypeC(ctx, sourceURL, targetURL, isRecursive, unordered, timeRef, encKeyDB) {
+				if cpURLs.Error == nil {
+					srcURL := cpURLs.SourceContent.URL.String()
+					tgtURL := cpURLs.TargetContent.URL.String()
+					if prevURL, ok := targetSources[tgtURL]; ok {
+						cpURLs = cpURLs.WithError(errDuplicateTarget(prevURL, srcURL, tgtURL).Trace(srcURL))
+					} else {
+						targetSources[tgtURL] = srcURL
+					}
+				}
 				copyURLsCh <- cpURLs
 			}
 		}
@@ -220,11 +241,62 @@ func prepareCopyURLsTypeD(ctx context.Context, sourceURLs []string, targetURL st
 	return copyURLsCh
 }
 
+// caseCollisions detects the local targets of a copy plan differing
+// only in case, they overwrite each other on case-insensitive filesystems.
+type caseCollisions struct {
+	rename      bool
+	insensitive bool
+	// Lower-cased target paths to the source copied there, nil until
+	// the first local target is checked.
+	sources map[string]string
+}
+
+// check returns cpURLs with an error if its target differs only in case
+// from an earlier target, or renamed with a numbered suffix if rename is
+// set.
+func (c *caseCollisions) check(cpURLs URLs) URLs {
+	if cpURLs.Error != nil || cpURLs.TargetContent == nil || cpURLs.TargetContent.URL.Type != fileSystem {
+		return cpURLs
+	}
+	tgtPath := cpURLs.TargetContent.URL.Path
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+		c.insensitive = isCaseInsensitiveDir(filepath.Dir(tgtPath))
+	}
+	if !c.insensitive {
+		return cpURLs
+	}
+	srcURL := cpURLs.SourceContent.URL.String()
+	if prevURL, ok := c.sources[strings.ToLower(tgtPath)]; ok {
+		if !c.rename {
+			return cpURLs.WithError(errCaseCollision(prevURL, srcURL, tgtPath).Trace(srcURL))
+		}
+		ext := filepath.Ext(tgtPath)
+		base := strings.TrimSuffix(tgtPath, ext)
+		for i := 1; ; i++ {
+			renamed := fmt.Sprintf("%s (%d)%s", base, i, ext)
+			if _, ok = c.sources[strings.ToLower(renamed)]; !ok {
+				tgtPath = renamed
+				break
+			}
+		}
+		cpURLs.TargetContent.URL.Path = tgtPath
+	}
+	c.sources[strings.ToLower(tgtPath)] = srcURL
+	return cpURLs
+}
+
 // prepareCopyURLs - prepares target and source clientURLs for copying.
-func prepareCopyURLs(ctx context.Context, sourceURLs []string, targetURL string, isRecursive bool, encKeyDB map[string][]prefixSSEPair, olderThan, newerThan string, timeRef time.Time, versionID string) chan URLs {
+func prepareCopyURLs(ctx context.Context, sourceURLs []string, targetURL string, isRecursive bool, encKeyDB map[string][]prefixSSEPair, olderThan, newerThan string, timeRef time.Time, versionID string, renameCaseCollisions bool) chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs, encKeyDB map[string][]prefixSSEPair, timeRef time.Time) {
 		defer close(copyURLsCh)
+		// Patterns are already expanded by checkCopySyntax.
+
+		// Renamed collisions are numbered in listing order, which must be
+		// the same for every run and resumed session.
+		unordered := !renameCaseCollisions
+
 		cpType, cpVersion, err := guessCopyURLType(ctx, sourceURLs, targetURL, isRecursive, encKeyDB, timeRef, versionID)
 		fatalIf(err.Trace(), "Unable to guess the type of copy operation.")
 
@@ -234,11 +306,11 @@ func prepareCopyURLs(ctx context.Context, sourceURLs []string, targetURL string,
 		case copyURLsTypeB:
 			copyURLsCh <- prepareCopyURLsTypeB(ctx, sourceURLs[0], cpVersion, targetURL, encKeyDB)
 		case copyURLsTypeC:
-			for cURLs := range prepareCopyURLsTypeC(ctx, sourceURLs[0], targetURL, isRecursive, timeRef, encKeyDB) {
+			for cURLs := range prepareCopyURLsTypeC(ctx, sourceURLs[0], targetURL, isRecursive, unordered, timeRef, encKeyDB) {
 				copyURLsCh <- cURLs
 			}
 		case copyURLsTypeD:
-			for cURLs := range prepareCopyURLsTypeD(ctx, sourceURLs, targetURL, isRecursive, timeRef, encKeyDB) {
+			for cURLs := range prepareCopyURLsTypeD(ctx, sourceURLs, targetURL, isRecursive, unordered, timeRef, encKeyDB) {
 				copyURLsCh <- cURLs
 			}
 		default:
@@ -249,6 +321,7 @@ func prepareCopyURLs(ctx context.Context, sourceURLs []string, targetURL string,
 	finalCopyURLsCh := make(chan URLs)
 	go func() {
 		defer close(finalCopyURLsCh)
+		collisions := caseCollisions{rename: renameCaseCollisions}
 		for cpURLs := range copyURLsCh {
 			// Skip objects older than --older-than parameter if specified
 			if olderThan != "" && isOlder(cpURLs.SourceContent.Time, olderThan) {
@@ -260,7 +333,7 @@ func prepareCopyURLs(ctx context.Context, sourceURLs []string, targetURL string,
 				continue
 			}
 
-			finalCopyURLsCh <- cpURLs
+			finalCopyURLsCh <- collisions.check(cpURLs)
 		}
 	}()
 
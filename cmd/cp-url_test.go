@@ -0,0 +1,171 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestMakeCopyContentTypeC(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses unix style path separators")
+	}
+
+	testCases := []struct {
+		sourceURL  string
+		contentURL string
+		targetURL  string
+		expected   string
+	}{
+		// Local folder contents vs the folder itself.
+		{"dir/", "dir/file", "tgt", "tgt/file"},
+		{"dir", "dir/file", "tgt", "tgt/dir/file"},
+		{"dir/", "dir/sub/file", "tgt", "tgt/sub/file"},
+		{"dir", "dir/sub/file", "tgt", "tgt/dir/sub/file"},
+		// Single character folder names behave the same way.
+		{"d/", "d/file", "tgt", "tgt/file"},
+		{"d", "d/file", "tgt", "tgt/d/file"},
+		// Nested and absolute local folders.
+		{"src/dir/", "src/dir/file", "tgt", "tgt/file"},
+		{"src/dir", "src/dir/file", "tgt", "tgt/dir/file"},
+		{"/src/dir/", "/src/dir/file", "/tgt", "/tgt/file"},
+		{"/src/dir", "/src/dir/file", "/tgt", "/tgt/dir/file"},
+		// Local folder to object storage.
+		{"dir/", "dir/file", "http://localhost:9000/bucket", "http://localhost:9000/bucket/file"},
+		{"dir", "dir/file", "http://localhost:9000/bucket", "http://localhost:9000/bucket/dir/file"},
+		// Buckets.
+		{"http://localhost:9000/bucket/", "http://localhost:9000/bucket/file", "tgt", "tgt/file"},
+		{"http://localhost:9000/bucket", "http://localhost:9000/bucket/file", "tgt", "tgt/bucket/file"},
+		{"http://localhost:9000/b/", "http://localhost:9000/b/file", "tgt", "tgt/file"},
+		{"http://localhost:9000/b", "http://localhost:9000/b/file", "tgt", "tgt/b/file"},
+		// Prefixes.
+		{"http://localhost:9000/bucket/dir/", "http://localhost:9000/bucket/dir/file", "tgt", "tgt/file"},
+		{"http://localhost:9000/bucket/dir", "http://localhost:9000/bucket/dir/file", "tgt", "tgt/dir/file"},
+		{"http://localhost:9000/bucket/dir/", "http://localhost:9000/bucket/dir/file", "http://localhost:9000/other", "http://localhost:9000/other/file"},
+		{"http://localhost:9000/bucket/dir", "http://localhost:9000/bucket/dir/file", "http://localhost:9000/other", "http://localhost:9000/other/dir/file"},
+	}
+
+	for i, testCase := range testCases {
+		sourceContent := &ClientContent{URL: *newClientURL(testCase.contentURL)}
+		cpURLs := makeCopyContentTypeC("", *newClientURL(testCase.sourceURL), sourceContent, "", testCase.targetURL, nil)
+		if got := cpURLs.TargetContent.URL.String(); got != testCase.expected {
+			t.Errorf("Test %d: copy of `%s` from `%s` into `%s`, expected `%s`, got `%s`",
+				i+1, testCase.contentURL, testCase.sourceURL, testCase.targetURL, testCase.expected, got)
+		}
+	}
+}
+
+func TestCaseCollisions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses unix style path separators")
+	}
+
+	newURLs := func(source, target string) URLs {
+		return URLs{
+			SourceContent: &ClientContent{URL: *newClientURL(source)},
+			TargetContent: &ClientContent{URL: *newClientURL(target)},
+		}
+	}
+
+	testCases := []struct {
+		rename      bool
+		insensitive bool
+		targets     []string
+		expected    []string // Empty for a collision error.
+	}{
+		{false, false, []string{"tgt/README.md", "tgt/readme.md"}, []string{"tgt/README.md", "tgt/readme.md"}},
+		{false, true, []string{"tgt/README.md", "tgt/readme.md", "tgt/other.md"}, []string{"tgt/README.md", "", "tgt/other.md"}},
+		{true, true, []string{"tgt/README.md", "tgt/readme.md", "tgt/Readme.md"}, []string{"tgt/README.md", "tgt/readme (1).md", "tgt/Readme (2).md"}},
+		{true, true, []string{"tgt/dir/a", "tgt/DIR/A"}, []string{"tgt/dir/a", "tgt/DIR/A (1)"}},
+	}
+
+	for i, testCase := range testCases {
+		collisions := caseCollisions{rename: testCase.rename, insensitive: testCase.insensitive, sources: map[string]string{}}
+		for j, target := range testCase.targets {
+			cpURLs := collisions.check(newURLs("http://localhost:9000/bucket/"+target, target))
+			if testCase.expected[j] == "" {
+				if cpURLs.Error == nil {
+					t.Errorf("Test %d: expected `%s` to collide", i+1, target)
+				} else if !isCopyEntryErr(cpURLs.Error) || cpURLs.SourceContent == nil || cpURLs.TargetContent == nil {
+					t.Errorf("Test %d: expected `%s` to be reported as a failed copy", i+1, target)
+				}
+				continue
+			}
+			if cpURLs.Error != nil {
+				t.Errorf("Test %d: unexpected error for `%s`: %s", i+1, target, cpURLs.Error)
+				continue
+			}
+			if got := cpURLs.TargetContent.URL.Path; got != testCase.expected[j] {
+				t.Errorf("Test %d: expected `%s` to be copied to `%s`, got `%s`", i+1, target, testCase.expected[j], got)
+			}
+		}
+	}
+}
+
+func TestPrepareCopyURLsTypeDDuplicates(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newMcConfig(), nil }
+
+	root, e := ioutil.TempDir("", "cp-url-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	// Two files of the same name in different folders, and another file.
+	for _, name := range []string{"a/file", "b/file", "b/other"} {
+		path := filepath.Join(root, name)
+		if e = os.MkdirAll(filepath.Dir(path), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(name), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	sourceURLs := []string{filepath.Join(root, "a", "file"), filepath.Join(root, "b", "file"), filepath.Join(root, "b", "other")}
+	targetURL := filepath.Join(root, "tgt") + string(filepath.Separator)
+
+	var targets []string
+	var errs int
+	for cpURLs := range prepareCopyURLsTypeD(context.Background(), sourceURLs, targetURL, false, true, time.Time{}, nil) {
+		if cpURLs.Error != nil {
+			if !strings.Contains(cpURLs.Error.ToGoError().Error(), "both resolve to target") {
+				t.Fatalf("unexpected error %s", cpURLs.Error)
+			}
+			errs++
+			continue
+		}
+		targets = append(targets, cpURLs.TargetContent.URL.Path)
+	}
+	if errs != 1 {
+		t.Errorf("expected one duplicate target error, got %d", errs)
+	}
+	expected := []string{filepath.Join(root, "tgt", "file"), filepath.Join(root, "tgt", "other")}
+	if len(targets) != len(expected) || targets[0] != expected[0] || targets[1] != expected[1] {
+		t.Errorf("expected targets %v, got %v", expected, targets)
+	}
+}
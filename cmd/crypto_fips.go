@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build fips
+// +build fips
+
+package cmd
+
+import (
+	"crypto/tls"
+
+	// Restricts all TLS configurations to FIPS approved settings,
+	// including the TLS 1.3 suites which cannot be configured.
+	_ "crypto/tls/fipsonly"
+)
+
+// globalFIPS is set for FIPS builds, only FIPS approved algorithms are
+// used. MD5 sums are neither computed nor sent, unless the server requires
+// Content-MD5 as for buckets with object locking.
+const globalFIPS = true
+
+// tlsCipherSuites returns the FIPS approved TLS 1.2 cipher suites.
+func tlsCipherSuites() []uint16 {
+	return []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}
+}
+
+// tlsCurvePreferences returns the FIPS approved elliptic curves.
+func tlsCurvePreferences() []tls.CurveID {
+	return []tls.CurveID{tls.CurveP256, tls.CurveP384}
+}
@@ -198,6 +198,9 @@ func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
 
 // mainDiff main for 'diff'.
 func mainDiff(cliCtx *cli.Context) error {
+	trimRecursiveArgs(cliCtx)
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelDiff := context.WithCancel(globalContext)
 	defer cancelDiff()
 
@@ -218,8 +221,8 @@ func mainDiff(cliCtx *cli.Context) error {
 	console.SetColor("DiffMMSourceMTime", color.New(color.FgYellow, color.Bold))
 
 	URLs := cliCtx.Args()
-	firstURL := URLs.Get(0)
-	secondURL := URLs.Get(1)
+	firstURL := URLs[0]
+	secondURL := URLs[1]
 
 	return doDiffMain(ctx, firstURL, secondURL)
 }
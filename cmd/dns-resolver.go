@@ -0,0 +1,182 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// Static HOST[:PORT] to IP overrides set via --resolve.
+	globalResolveOverrides map[string]string
+
+	// DNS server used instead of the system resolver, set via --dns-resolver.
+	globalDNSResolver string
+
+	// Cache of endpoint lookups, nil unless --dns-cache-ttl is set.
+	globalDNSCache *dnsCache
+)
+
+// parseResolveOverrides parses HOST[:PORT]=IP entries.
+func parseResolveOverrides(entries []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid resolve entry `%s`, expected HOST[:PORT]=IP", entry)
+		}
+		if net.ParseIP(parts[1]) == nil {
+			return nil, fmt.Errorf("invalid IP address `%s` in resolve entry `%s`", parts[1], entry)
+		}
+		overrides[strings.ToLower(parts[0])] = parts[1]
+	}
+	return overrides, nil
+}
+
+// dnsCacheEntry holds the addresses of a host and when they expire.
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsCache remembers host lookups for a fixed duration so that heavily
+// parallel transfers do not resolve the same endpoint for every request.
+type dnsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]dnsCacheEntry
+	lookup  func(ctx context.Context, host string) ([]string, error)
+}
+
+func newDNSCache(ttl time.Duration, lookup func(ctx context.Context, host string) ([]string, error)) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		entries: make(map[string]dnsCacheEntry),
+		lookup:  lookup,
+	}
+}
+
+// LookupHost returns the cached addresses of host, resolving it again
+// once the cached entry expired. Failed lookups are not cached.
+func (c *dnsCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, e := c.lookup(ctx, host)
+	if e != nil {
+		return nil, e
+	}
+
+	c.mu.Lock()
+	c.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// newResolver returns the resolver used for endpoint lookups.
+func newResolver(server string) *net.Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	if _, _, e := net.SplitHostPort(server); e != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// setGlobalResolver configures how endpoints are resolved.
+func setGlobalResolver(resolve []string, server string, cacheTTL time.Duration) error {
+	overrides, e := parseResolveOverrides(resolve)
+	if e != nil {
+		return e
+	}
+	globalResolveOverrides = overrides
+	globalDNSResolver = server
+
+	globalDNSCache = nil
+	if cacheTTL > 0 {
+		globalDNSCache = newDNSCache(cacheTTL, newResolver(server).LookupHost)
+	}
+	return nil
+}
+
+// newDialContext returns the dial function of the endpoint transports,
+// honoring --resolve, --dns-resolver and --dns-cache-ttl.
+func newDialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if len(globalResolveOverrides) == 0 && globalDNSResolver == "" && globalDNSCache == nil {
+		return dialer.DialContext
+	}
+
+	overrides := globalResolveOverrides
+	cache := globalDNSCache
+	lookup := newResolver(globalDNSResolver).LookupHost
+	if cache != nil {
+		lookup = cache.LookupHost
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, e := net.SplitHostPort(addr)
+		if e != nil {
+			return nil, e
+		}
+
+		lowerHost := strings.ToLower(host)
+		if ip, ok := overrides[net.JoinHostPort(lowerHost, port)]; ok {
+			return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		}
+		if ip, ok := overrides[lowerHost]; ok {
+			return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		}
+		if net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		addrs, e := lookup(ctx, host)
+		if e != nil {
+			return nil, e
+		}
+
+		// Try all addresses until one of them answers.
+		var conn net.Conn
+		for _, ip := range addrs {
+			conn, e = dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if e == nil {
+				return conn, nil
+			}
+		}
+		if e == nil {
+			e = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return nil, e
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseResolveOverrides(t *testing.T) {
+	testCases := []struct {
+		entries   []string
+		overrides map[string]string
+		success   bool
+	}{
+		{nil, map[string]string{}, true},
+		{[]string{"minio.local:9000=10.10.75.1"}, map[string]string{"minio.local:9000": "10.10.75.1"}, true},
+		{[]string{"MinIO.local=::1"}, map[string]string{"minio.local": "::1"}, true},
+		{[]string{"minio.local"}, nil, false},
+		{[]string{"=10.10.75.1"}, nil, false},
+		{[]string{"minio.local=minio.remote"}, nil, false},
+	}
+
+	for i, testCase := range testCases {
+		overrides, e := parseResolveOverrides(testCase.entries)
+		if (e == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, e)
+		}
+		if len(overrides) != len(testCase.overrides) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.overrides, overrides)
+		}
+		for k, v := range testCase.overrides {
+			if overrides[k] != v {
+				t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.overrides, overrides)
+			}
+		}
+	}
+}
+
+func TestDNSCache(t *testing.T) {
+	var lookups int
+	cache := newDNSCache(time.Hour, func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"10.10.75.1"}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		addrs, e := cache.LookupHost(context.Background(), "minio.local")
+		if e != nil || len(addrs) != 1 || addrs[0] != "10.10.75.1" {
+			t.Fatalf("unexpected lookup result %v, %v", addrs, e)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected a single lookup, got %d", lookups)
+	}
+
+	// Lookups are repeated once entries expired.
+	cache.ttl = 0
+	cache.entries["minio.local"] = dnsCacheEntry{expires: time.Now()}
+	cache.LookupHost(context.Background(), "minio.local")
+	cache.LookupHost(context.Background(), "minio.local")
+	if lookups != 3 {
+		t.Fatalf("expected expired entries to be resolved again, got %d lookups", lookups)
+	}
+}
+
+func TestDialContextResolveOverride(t *testing.T) {
+	l, e := net.Listen("tcp", "127.0.0.1:0")
+	if e != nil {
+		t.Skip(e)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	if e = setGlobalResolver([]string{"minio.invalid:" + port + "=127.0.0.1"}, "", 0); e != nil {
+		t.Fatal(e)
+	}
+	defer setGlobalResolver(nil, "", 0)
+
+	conn, e := newDialContext(&net.Dialer{Timeout: time.Second})(context.Background(), "tcp", "minio.invalid:"+port)
+	if e != nil {
+		t.Fatal(e)
+	}
+	conn.Close()
+}
@@ -189,6 +189,9 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 
 // main for du command.
 func mainDu(cliCtx *cli.Context) error {
+	trimRecursiveArgs(cliCtx)
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	if !cliCtx.Args().Present() {
 		cli.ShowCommandHelpAndExit(cliCtx, "du", 1)
 	}
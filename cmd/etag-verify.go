@@ -0,0 +1,234 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// partMD5 is the MD5 sum of a part, only valid if the part was
+// read sequentially from its beginning.
+type partMD5 struct {
+	sync.Mutex
+	hash   hash.Hash
+	next   int64
+	broken bool
+}
+
+func (p *partMD5) write(start, off int64, b []byte) {
+	p.Lock()
+	defer p.Unlock()
+	switch {
+	case off == start:
+		// (Re)started from the beginning, e.g. a retried part.
+		p.hash = md5.New()
+		p.broken = false
+	case off != p.next || p.hash == nil:
+		p.broken = true
+		return
+	}
+	p.hash.Write(b)
+	p.next = off + int64(len(b))
+}
+
+func (p *partMD5) sum() []byte {
+	p.Lock()
+	defer p.Unlock()
+	if p.broken || p.hash == nil {
+		return nil
+	}
+	return p.hash.Sum(nil)
+}
+
+// etagHasher computes the MD5 sums of an upload, as a whole and per
+// part, to verify the ETag returned by the server.
+type etagHasher struct {
+	mu       sync.Mutex
+	partSize int64
+	whole    partMD5
+	parts    map[int64]*partMD5
+}
+
+func (h *etagHasher) part(n int64) *partMD5 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, ok := h.parts[n]
+	if !ok {
+		p = &partMD5{}
+		h.parts[n] = p
+	}
+	return p
+}
+
+// write accounts data read at offset off.
+func (h *etagHasher) write(off int64, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	h.whole.write(0, off, b)
+	if h.partSize <= 0 {
+		// Single part upload, the whole sum is enough.
+		return
+	}
+	for len(b) > 0 {
+		n := off / h.partSize
+		end := (n + 1) * h.partSize
+		chunk := b
+		if int64(len(chunk)) > end-off {
+			chunk = chunk[:end-off]
+		}
+		h.part(n).write(n*h.partSize, off, chunk)
+		off += int64(len(chunk))
+		b = b[len(chunk):]
+	}
+}
+
+// verify compares the ETag returned for the upload with the data read, it
+// returns false only if the ETag is known not to match.
+func (h *etagHasher) verify(etag string) (expected string, ok bool) {
+	etag = strings.Trim(etag, "\"")
+	if !isMD5ETag(etag) {
+		// Not derived from MD5 sums, e.g. of servers using other ETags.
+		return "", true
+	}
+
+	if i := strings.LastIndex(etag, "-"); i >= 0 {
+		count, e := strconv.Atoi(etag[i+1:])
+		if e != nil || count <= 0 || count != len(h.parts) {
+			return "", true
+		}
+		sums := md5.New()
+		for n := int64(0); n < int64(count); n++ {
+			p, found := h.parts[n]
+			if !found {
+				return "", true
+			}
+			sum := p.sum()
+			if sum == nil {
+				return "", true
+			}
+			sums.Write(sum)
+		}
+		expected = hex.EncodeToString(sums.Sum(nil)) + "-" + strconv.Itoa(count)
+		return expected, strings.EqualFold(expected, etag)
+	}
+
+	sum := h.whole.sum()
+	if sum == nil {
+		return "", true
+	}
+	expected = hex.EncodeToString(sum)
+	return expected, strings.EqualFold(expected, etag)
+}
+
+// isMD5ETag returns true if etag has the form of an MD5 sum, followed by
+// the number of parts for multipart uploads.
+func isMD5ETag(etag string) bool {
+	if i := strings.LastIndex(etag, "-"); i >= 0 {
+		etag = etag[:i]
+	}
+	if len(etag) != hex.EncodedLen(md5.Size) {
+		return false
+	}
+	_, e := hex.DecodeString(etag)
+	return e == nil
+}
+
+// etagReader hashes the data read by an upload.
+type etagReader struct {
+	*etagHasher
+	reader io.Reader
+	offset int64
+}
+
+func (r *etagReader) Read(b []byte) (int, error) {
+	n, e := r.reader.Read(b)
+	r.write(r.offset, b[:n])
+	r.offset += int64(n)
+	return n, e
+}
+
+type etagReadSeeker struct {
+	*etagReader
+}
+
+func (r etagReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	off, e := r.reader.(io.Seeker).Seek(offset, whence)
+	if e == nil {
+		r.offset = off
+	}
+	return off, e
+}
+
+type etagReaderAt struct {
+	*etagReader
+}
+
+func (r etagReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, e := r.reader.(io.ReaderAt).ReadAt(b, off)
+	r.write(off, b[:n])
+	return n, e
+}
+
+type etagReadSeekerAt struct {
+	etagReadSeeker
+}
+
+func (r etagReadSeekerAt) ReadAt(b []byte, off int64) (int, error) {
+	return etagReaderAt{r.etagReader}.ReadAt(b, off)
+}
+
+// newETagReader wraps reader to hash the data of an upload with parts of
+// partSize, zero for single part uploads. The wrapper keeps the io.Seeker and io.ReaderAt interfaces of
+// reader so that minio-go picks the same upload strategy.
+func newETagReader(reader io.Reader, partSize int64) (io.Reader, *etagHasher) {
+	h := &etagHasher{partSize: partSize, parts: make(map[int64]*partMD5)}
+	r := &etagReader{etagHasher: h, reader: reader}
+
+	_, seeker := reader.(io.Seeker)
+	_, readerAt := reader.(io.ReaderAt)
+	switch v := reader.(type) {
+	case *minio.Object:
+		// minio-go does not read objects in parallel.
+		readerAt = false
+	case *os.File:
+		switch v.Name() {
+		case "/dev/stdin", "/dev/stdout", "/dev/stderr":
+			readerAt = false
+		}
+	}
+
+	switch {
+	case seeker && readerAt:
+		return etagReadSeekerAt{etagReadSeeker{r}}, h
+	case seeker:
+		return etagReadSeeker{r}, h
+	case readerAt:
+		return etagReaderAt{r}, h
+	}
+	return r, h
+}
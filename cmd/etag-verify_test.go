@@ -0,0 +1,96 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"testing"
+)
+
+func TestETagReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	partSize := int64(32)
+
+	wholeSum := md5.Sum(data)
+	wholeETag := hex.EncodeToString(wholeSum[:])
+
+	sums := md5.New()
+	var parts int
+	for off := int64(0); off < int64(len(data)); off += partSize {
+		end := off + partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		sum := md5.Sum(data[off:end])
+		sums.Write(sum[:])
+		parts++
+	}
+	multipartETag := hex.EncodeToString(sums.Sum(nil)) + "-" + strconv.Itoa(parts)
+
+	// Sequential single part upload.
+	reader, hasher := newETagReader(bytes.NewReader(data), 0)
+	if _, ok := reader.(io.ReaderAt); !ok {
+		t.Fatal("expected io.ReaderAt to be kept")
+	}
+	if _, ok := reader.(io.Seeker); !ok {
+		t.Fatal("expected io.Seeker to be kept")
+	}
+	ioutil.ReadAll(reader)
+	if _, ok := hasher.verify(`"` + wholeETag + `"`); !ok {
+		t.Fatal("expected single part ETag to match")
+	}
+	if _, ok := hasher.verify("9af2f8218b150c351ad802c6f3d66abe"); ok {
+		t.Fatal("expected mismatch of a corrupted upload")
+	}
+
+	// Parts read in parallel, the first part being retried.
+	reader, hasher = newETagReader(bytes.NewReader(data), partSize)
+	readerAt := reader.(io.ReaderAt)
+	for _, n := range []int64{3, 1, 0, 2} {
+		sr := io.NewSectionReader(readerAt, n*partSize, partSize)
+		if n == 0 {
+			io.CopyN(ioutil.Discard, sr, 10)
+			sr.Seek(0, io.SeekStart)
+		}
+		ioutil.ReadAll(sr)
+	}
+	if expected, ok := hasher.verify(multipartETag); !ok {
+		t.Fatalf("expected multipart ETag %s to match, computed %s", multipartETag, expected)
+	}
+	if _, ok := hasher.verify("00000000000000000000000000000000-" + strconv.Itoa(parts)); ok {
+		t.Fatal("expected multipart ETag mismatch")
+	}
+
+	// Streams without io.ReaderAt remain so.
+	reader, _ = newETagReader(ioutil.NopCloser(bytes.NewReader(data)), partSize)
+	if _, ok := reader.(io.ReaderAt); ok {
+		t.Fatal("unexpected io.ReaderAt")
+	}
+
+	// Unknown ETag formats are not verified.
+	for _, etag := range []string{"not-an-etag", "da39a3ee5e6b4b0d3255bfef95601890afd80709"} {
+		if _, ok := hasher.verify(etag); !ok {
+			t.Fatalf("expected ETag %s to be skipped", etag)
+		}
+	}
+}
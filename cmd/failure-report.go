@@ -0,0 +1,139 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// failureReportEntry is one failed copy recorded in a failure report.
+type failureReportEntry struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	VersionID string    `json:"versionId,omitempty"`
+	Error     string    `json:"error"`
+	// Folder the copy ran in, relative local paths are resolved against it.
+	Root string `json:"root,omitempty"`
+}
+
+// resolve returns urlStr of the entry, relative local paths are joined
+// to the root of the entry instead of the current folder.
+func (entry failureReportEntry) resolve(urlStr string) string {
+	if entry.Root == "" || filepath.IsAbs(urlStr) || newClientURL(urlStr).Type != fileSystem {
+		return urlStr
+	}
+	if _, _, aliasCfg := mustExpandAlias(urlStr); aliasCfg != nil {
+		return urlStr
+	}
+	return filepath.Join(entry.Root, urlStr)
+}
+
+// failureReport records failed copies as JSON lines, so that a later
+// run can retry only the failed objects. A nil report records nothing.
+type failureReport struct {
+	mutex sync.Mutex
+	file  *os.File
+	enc   *json.Encoder
+	root  string
+}
+
+// newFailureReport creates or truncates the report file.
+func newFailureReport(path string) (*failureReport, *probe.Error) {
+	file, e := os.Create(path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	root, _ := os.Getwd()
+	return &failureReport{file: file, enc: json.NewEncoder(file), root: root}, nil
+}
+
+// reportURL returns the URL of an object as recorded in a report, keys
+// are kept verbatim so that `//` and trailing separators survive.
+func reportURL(alias string, u ClientURL) string {
+	switch {
+	case alias != "":
+		return alias + u.Path
+	case u.Type == fileSystem:
+		return u.Path
+	default:
+		return u.String()
+	}
+}
+
+// add records the failed copy of urls.
+func (r *failureReport) add(urls URLs) {
+	if r == nil || urls.Error == nil || urls.SourceContent == nil || urls.TargetContent == nil {
+		return
+	}
+	entry := failureReportEntry{
+		Time:      UTCNow(),
+		Source:    reportURL(urls.SourceAlias, urls.SourceContent.URL),
+		Target:    reportURL(urls.TargetAlias, urls.TargetContent.URL),
+		VersionID: urls.SourceContent.VersionID,
+		Error:     urls.Error.ToGoError().Error(),
+		Root:      r.root,
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	errorIf(probe.NewError(r.enc.Encode(entry)), "Unable to record failed copy of `%s`.", entry.Source)
+}
+
+// Close closes the report file.
+func (r *failureReport) Close() *probe.Error {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return probe.NewError(r.file.Close())
+}
+
+// readFailureReport reads all entries of a failure report.
+func readFailureReport(path string) ([]failureReportEntry, *probe.Error) {
+	file, e := os.Open(path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer file.Close()
+
+	var entries []failureReportEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry failureReportEntry
+		if e = json.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			return nil, probe.NewError(e).Trace(path)
+		}
+		entries = append(entries, entry)
+	}
+	if e = scanner.Err(); e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	return entries, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestFailureReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failed.json")
+	report, err := newFailureReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := URLs{
+		SourceAlias:   "",
+		SourceContent: &ClientContent{URL: *newClientURL("dir/a.txt"), VersionID: "v1"},
+		TargetAlias:   "play",
+		TargetContent: &ClientContent{URL: *newClientURL("/mybucket/a.txt")},
+		Error:         probe.NewError(errors.New("connection reset")),
+	}
+	report.add(failed)
+	// Successful copies are not recorded.
+	report.add(URLs{SourceContent: failed.SourceContent, TargetContent: failed.TargetContent})
+	if err = report.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readFailureReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Source != "dir/a.txt" || entry.Target != "play/mybucket/a.txt" || entry.VersionID != "v1" || entry.Error != "connection reset" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	// Relative local paths are resolved against the folder of the copy.
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newMcConfig(), nil }
+	wd, e := os.Getwd()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if source := entry.resolve(entry.Source); source != filepath.Join(wd, "dir/a.txt") {
+		t.Errorf("expected %s to be resolved against %s", source, wd)
+	}
+	if target := entry.resolve(entry.Target); target != "play/mybucket/a.txt" {
+		t.Errorf("expected aliased %s to be kept", target)
+	}
+
+	// Object keys are recorded verbatim.
+	for _, key := range []string{"/mybucket/a//b.txt", "/mybucket/dir/"} {
+		u := *newClientURL(key)
+		if got := reportURL("play", u); got != "play"+key {
+			t.Errorf("expected key %s to be kept, got %s", key, got)
+		}
+	}
+	if got := reportURL("", *newClientURL("https://play.min.io/mybucket/a//b.txt")); got != "https://play.min.io/mybucket/a//b.txt" {
+		t.Errorf("expected URL to be kept, got %s", got)
+	}
+
+	// A nil report records nothing.
+	var nilReport *failureReport
+	nilReport.add(failed)
+	if err = nilReport.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
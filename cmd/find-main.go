@@ -212,6 +212,8 @@ type findContext struct {
 
 // mainFind - handler for mc find commands
 func mainFind(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelFind := context.WithCancel(globalContext)
 	defer cancelFind()
 
@@ -262,8 +262,15 @@ func doFind(ctxCtx context.Context, ctx *findContext) error {
 
 	var prevKeyName string
 
+	lstOptions := ListOptions{Recursive: true, ShowDir: DirFirst}
+	// Paths are trimmed at maxDepth, do not list deeper unless the
+	// deeper entries are needed to match on their time or size.
+	if ctx.olderThan == "" && ctx.newerThan == "" && ctx.largerSize == 0 && ctx.smallerSize == 0 {
+		lstOptions.MaxDepth = int(ctx.maxDepth)
+	}
+
 	// iterate over all content which is within the given directory
-	for content := range ctx.clnt.List(globalContext, ListOptions{Recursive: true, ShowDir: DirFirst}) {
+	for content := range ctx.clnt.List(globalContext, lstOptions) {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
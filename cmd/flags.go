@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"github.com/minio/cli"
+	minio "github.com/minio/minio-go/v7"
 )
 
 // Collection of mc flags currently supported
@@ -48,6 +49,99 @@ var globalFlags = []cli.Flag{
 		Name:  "insecure",
 		Usage: "disable SSL certificate verification",
 	},
+	cli.StringFlag{
+		Name:   "ca-bundle",
+		Usage:  "PEM file of CA certificates to trust besides the system and certs folder ones",
+		EnvVar: "MC_CA_BUNDLE",
+	},
+	cli.StringFlag{
+		Name:   "client-cert",
+		Usage:  "PEM certificate presented to endpoints requiring TLS client authentication",
+		EnvVar: "MC_CLIENT_CERT",
+	},
+	cli.StringFlag{
+		Name:   "client-key",
+		Usage:  "PEM private key of --client-cert",
+		EnvVar: "MC_CLIENT_KEY",
+	},
+	cli.StringFlag{
+		Name:   "proxy",
+		Usage:  "proxy for all endpoints, e.g. socks5://localhost:1080",
+		EnvVar: "MC_PROXY",
+	},
+	cli.StringSliceFlag{
+		Name:   "resolve",
+		Usage:  "resolves HOST[:PORT] to an IP address, e.g. minio.local:9000=10.10.75.1",
+		EnvVar: "MC_RESOLVE",
+	},
+	cli.StringFlag{
+		Name:   "dns-resolver",
+		Usage:  "DNS server (HOST[:PORT]) used to resolve endpoints",
+		EnvVar: "MC_DNS_RESOLVER",
+	},
+	cli.DurationFlag{
+		Name:   "dns-cache-ttl",
+		Usage:  "cache endpoint DNS lookups for the given duration, e.g. 1m",
+		EnvVar: "MC_DNS_CACHE_TTL",
+	},
+	cli.IntFlag{
+		Name:   "max-retry",
+		Usage:  "maximum attempts for requests failing with a transient error",
+		Value:  minio.MaxRetry,
+		EnvVar: "MC_MAX_RETRY",
+	},
+	cli.DurationFlag{
+		Name:   "retry-delay",
+		Usage:  "base delay of the exponential backoff between attempts",
+		Value:  minio.DefaultRetryUnit,
+		EnvVar: "MC_RETRY_DELAY",
+	},
+	cli.StringFlag{
+		Name:   "symlinks",
+		Usage:  "symbolic links in local folders, 'follow' links to folders too, 'skip' all links or 'link' to copy them as links to local folders, refused for object storage targets",
+		EnvVar: "MC_SYMLINKS",
+	},
+	cli.StringFlag{
+		Name:   "special-files",
+		Usage:  "FIFOs, sockets and devices in local folders, 'skip' with a warning, 'fail' or 'metadata' to copy them empty",
+		EnvVar: "MC_SPECIAL_FILES",
+	},
+	cli.BoolFlag{
+		Name:   "skip-hidden",
+		Usage:  "skip dotfiles and junk files such as Thumbs.db in local folders",
+		EnvVar: "MC_SKIP_HIDDEN",
+	},
+	cli.BoolFlag{
+		Name:   "trash",
+		Usage:  "move removed local files to the trash instead of deleting them",
+		EnvVar: "MC_TRASH",
+	},
+	cli.IntFlag{
+		Name:   "walk-workers",
+		Usage:  "number of local folders read concurrently by recursive listings",
+		Value:  defaultWalkWorkers,
+		EnvVar: "MC_WALK_WORKERS",
+	},
+	cli.BoolFlag{
+		Name:   "direct-io",
+		Usage:  "read local files bypassing the page cache, linux only",
+		EnvVar: "MC_DIRECT_IO",
+	},
+	cli.BoolFlag{
+		Name:   "verify-etag",
+		Usage:  "verify the MD5 based ETags returned for uploads, not available in FIPS mode",
+		EnvVar: "MC_VERIFY_ETAG",
+	},
+	cli.StringFlag{
+		Name:   "limit-upload",
+		Usage:  "limit upload rates to no more than KiB/s, MiB/s, GiB/s",
+		EnvVar: "MC_LIMIT_UPLOAD",
+	},
+	cli.StringFlag{
+		Name:   "limit-download",
+		Usage:  "limit download rates to no more than KiB/s, MiB/s, GiB/s",
+		EnvVar: "MC_LIMIT_DOWNLOAD",
+	},
 }
 
 // Flags common across all I/O commands such as cp, mirror, stat, pipe etc.
@@ -0,0 +1,168 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	jsoncolor "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var flushFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "list",
+		Usage: "list the queued objects without replaying them",
+	},
+	cli.BoolFlag{
+		Name:  "discard",
+		Usage: "remove all queued objects without replaying them",
+	},
+}
+
+var flushCmd = cli.Command{
+	Name:         "flush",
+	Usage:        "replay transfers queued while the target was unreachable",
+	Action:       mainFlush,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(flushFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Upload the objects queued by 'mc cp --queue' in the order they were queued, a replayed
+  object is removed from the queue. Replaying stops at the first target that is still
+  unreachable, so that it can be run whenever connectivity returns.
+
+EXAMPLES:
+  1. Replay all queued transfers.
+     {{.Prompt}} {{.HelpName}}
+
+  2. List the queued transfers.
+     {{.Prompt}} {{.HelpName}} --list
+
+  3. Drop all queued transfers.
+     {{.Prompt}} {{.HelpName}} --discard
+`,
+}
+
+// flushMessage container for replayed or listed transfers.
+type flushMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+	Op     string `json:"operation"`
+}
+
+// String colorized flush message.
+func (f flushMessage) String() string {
+	switch f.Op {
+	case "list":
+		return console.Colorize("Flush", fmt.Sprintf("%10s `%s` -> `%s`", humanize.IBytes(uint64(f.Size)), f.Source, f.Target))
+	case "discard":
+		return console.Colorize("Flush", fmt.Sprintf("Discarded `%s` -> `%s`", f.Source, f.Target))
+	}
+	return console.Colorize("Flush", fmt.Sprintf("`%s` -> `%s`", f.Source, f.Target))
+}
+
+// JSON jsonified flush message.
+func (f flushMessage) JSON() string {
+	f.Status = "success"
+	flushMessageBytes, e := jsoncolor.MarshalIndent(f, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(flushMessageBytes)
+}
+
+// checkFlushSyntax - validate all the passed arguments
+func checkFlushSyntax(cliCtx *cli.Context) {
+	if cliCtx.NArg() != 0 {
+		cli.ShowCommandHelpAndExit(cliCtx, "flush", 1) // last argument is exit code
+	}
+	if cliCtx.Bool("list") && cliCtx.Bool("discard") {
+		fatalIf(errInvalidArgument().Trace(), "--list and --discard cannot be used together.")
+	}
+}
+
+// mainFlush is the entry point for flush command.
+func mainFlush(cliCtx *cli.Context) error {
+	ctx, cancelFlush := context.WithCancel(globalContext)
+	defer cancelFlush()
+
+	checkFlushSyntax(cliCtx)
+
+	console.SetColor("Flush", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	dir, err := getQueueDir()
+	fatalIf(err, "Unable to locate the offline queue.")
+	entries, err := listQueueEntries(dir)
+	fatalIf(err, "Unable to read the offline queue.")
+
+	var retErr error
+	for _, entry := range entries {
+		msg := flushMessage{Source: entry.Source, Target: entry.Target, Size: entry.Size}
+		switch {
+		case cliCtx.Bool("list"):
+			msg.Op = "list"
+			printMsg(msg)
+			continue
+		case cliCtx.Bool("discard"):
+			msg.Op = "discard"
+			if err = removeQueueEntry(dir, entry); err != nil {
+				errorIf(err, "Unable to remove `%s` from the queue.", entry.Source)
+				retErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			printMsg(msg)
+			continue
+		}
+
+		urls := uploadSourceToTargetURL(ctx, replayURLs(dir, entry), nil, encKeyDB, false)
+		if urls.Error != nil {
+			errorIf(urls.Error.Trace(entry.Source), "Failed to copy `%s`.", entry.Source)
+			retErr = exitStatus(globalErrorExitStatus)
+			if isTargetUnreachable(urls) {
+				// Keep the order of the remaining transfers.
+				break
+			}
+			continue
+		}
+		msg.Op = "copy"
+		printMsg(msg)
+		if err = removeQueueEntry(dir, entry); err != nil {
+			errorIf(err, "Unable to remove `%s` from the queue.", entry.Source)
+			retErr = exitStatus(globalErrorExitStatus)
+		}
+	}
+	return retErr
+}
@@ -0,0 +1,129 @@
+//go:build linux
+// +build linux
+
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// Offsets and sizes of O_DIRECT reads are aligned to this size.
+	directIOAlign = 4096
+	// Size of the blocks read by O_DIRECT readers.
+	directIOBlockSize = 4 * 1024 * 1024
+)
+
+// directReader reads a file opened with O_DIRECT through an aligned
+// buffer, the page cache is bypassed.
+type directReader struct {
+	file *os.File
+	buf  []byte
+	data []byte // unread data of buf, read at pos
+	pos  int64
+	eof  bool
+}
+
+// openDirect opens a file for reads bypassing the page cache, files
+// on filesystems without O_DIRECT support are opened as usual.
+func openDirect(fpath string) (sourceFile, error) {
+	file, e := os.OpenFile(fpath, os.O_RDONLY|syscall.O_DIRECT, 0)
+	if errors.Is(e, syscall.EINVAL) {
+		return os.Open(fpath)
+	}
+	if e != nil {
+		return nil, e
+	}
+	return &directReader{file: file, buf: alignedBuffer(directIOBlockSize)}, nil
+}
+
+// alignedBuffer returns a buffer of size bytes starting at an address
+// aligned to directIOAlign.
+func alignedBuffer(size int) []byte {
+	buf := make([]byte, size+directIOAlign)
+	offset := 0
+	if rem := int(uintptr(unsafe.Pointer(&buf[0])) & (directIOAlign - 1)); rem != 0 {
+		offset = directIOAlign - rem
+	}
+	return buf[offset : offset+size]
+}
+
+func (r *directReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		if e := r.fill(); e != nil {
+			return 0, e
+		}
+		if len(r.data) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	r.pos += int64(n)
+	return n, nil
+}
+
+// fill reads the aligned block holding pos.
+func (r *directReader) fill() error {
+	base := r.pos &^ (directIOAlign - 1)
+	n, e := r.file.ReadAt(r.buf, base)
+	if e == io.EOF {
+		r.eof = true
+	} else if e != nil {
+		return e
+	}
+	if skip := int(r.pos - base); skip < n {
+		r.data = r.buf[skip:n]
+	}
+	return nil
+}
+
+func (r *directReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		st, e := r.file.Stat()
+		if e != nil {
+			return 0, e
+		}
+		offset += st.Size()
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos, r.data, r.eof = offset, nil, false
+	return offset, nil
+}
+
+func (r *directReader) Stat() (os.FileInfo, error) {
+	return r.file.Stat()
+}
+
+func (r *directReader) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,65 @@
+//go:build linux
+// +build linux
+
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirectReader(t *testing.T) {
+	data := make([]byte, 2*directIOBlockSize+12345)
+	rand.New(rand.NewSource(1)).Read(data)
+	fpath := filepath.Join(t.TempDir(), "object")
+	if e := os.WriteFile(fpath, data, 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	reader, e := openDirect(fpath)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer reader.Close()
+
+	got, e := io.ReadAll(reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expected %d bytes, read %d different bytes", len(data), len(got))
+	}
+
+	// Seek to an unaligned offset of the last block.
+	offset := int64(directIOBlockSize + 777)
+	if _, e = reader.Seek(offset, io.SeekStart); e != nil {
+		t.Fatal(e)
+	}
+	if got, e = io.ReadAll(reader); e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(got, data[offset:]) {
+		t.Fatalf("expected %d bytes from %d, read %d different bytes", len(data)-int(offset), offset, len(got))
+	}
+}
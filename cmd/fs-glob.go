@@ -0,0 +1,173 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	xfilepath "github.com/minio/filepath"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// globAnyFolders is the pattern element matching any number of folders.
+const globAnyFolders = "**"
+
+// hasGlobMeta returns true if the path contains any of the `*?[` glob
+// characters.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// matchGlob reports whether the path elements match the pattern
+// elements, `**` matching zero or more elements. If partial is true it
+// reports whether elems may be the folders leading to a match instead.
+func matchGlob(pattern, elems []string, partial bool) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == globAnyFolders {
+			if partial {
+				return true
+			}
+			for i := 0; i <= len(elems); i++ {
+				if matchGlob(pattern[1:], elems[i:], false) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elems) == 0 {
+			return partial
+		}
+		if matched, e := filepath.Match(pattern[0], elems[0]); e != nil || !matched {
+			return false
+		}
+		pattern, elems = pattern[1:], elems[1:]
+	}
+	return !partial && len(elems) == 0
+}
+
+// globFS lists the local files and folders matching pattern in lexical
+// order. `*`, `?` and `[...]` match within a path element as with
+// filepath.Match while a `**` element matches any number of folders,
+// e.g. /data/logs/**/2015-*.gz.
+func globFS(ctx context.Context, pattern string) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+
+		// Walk from the last folder before the first pattern element.
+		elems := strings.Split(filepath.ToSlash(pattern), "/")
+		i := 0
+		for i < len(elems) && !hasGlobMeta(elems[i]) {
+			i++
+		}
+		root := strings.Join(elems[:i], "/")
+		switch {
+		case i == 0:
+			root = "."
+		case root == "" || strings.HasSuffix(root, ":"):
+			// Root folder or a Windows drive.
+			root += "/"
+		}
+		root = filepath.FromSlash(root)
+		elems = elems[i:]
+
+		visitFS := func(fp string, fi os.FileInfo, e error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if e != nil {
+				if fi == nil {
+					// Nothing matches under a missing folder.
+					return nil
+				}
+				// Unreadable folders are reported and skipped.
+				if os.IsPermission(e) {
+					e = PathInsufficientPermission{Path: fp}
+				}
+				contentCh <- &ClientContent{Err: probe.NewError(e).Trace(fp)}
+				return nil
+			}
+			rel, e := filepath.Rel(root, fp)
+			if e != nil || rel == "." {
+				return nil
+			}
+			if isIgnoredFile(fi.Name()) {
+				if fi.IsDir() && globalSkipHidden && isHiddenFile(fi.Name()) {
+					return xfilepath.ErrSkipDir
+				}
+				return nil
+			}
+			relElems := strings.Split(filepath.ToSlash(rel), "/")
+			if matchGlob(elems, relElems, false) {
+				contentCh <- &ClientContent{
+					URL:  *newClientURL(fp),
+					Time: fi.ModTime(),
+					Size: fi.Size(),
+					Type: fi.Mode(),
+				}
+			}
+			if fi.IsDir() && !matchGlob(elems, relElems, true) {
+				return xfilepath.ErrSkipDir
+			}
+			return nil
+		}
+		if e := newFSWalker(globalWalkWorkers, true, visitFS).Walk(root); e != nil {
+			contentCh <- &ClientContent{Err: probe.NewError(e)}
+		}
+	}()
+	return contentCh
+}
+
+// expandGlobURLs replaces the local URLs holding glob patterns with the
+// files and folders they match. An existing path is never expanded and a
+// pattern matching nothing is kept as is so that it is reported as
+// missing. Folders that cannot be read are reported and skipped.
+func expandGlobURLs(ctx context.Context, urlStrs []string) []string {
+	expandedURLs := make([]string, 0, len(urlStrs))
+	for _, urlStr := range urlStrs {
+		if !hasGlobMeta(urlStr) {
+			expandedURLs = append(expandedURLs, urlStr)
+			continue
+		}
+		// Object names may hold any glob character.
+		if _, _, aliasCfg := mustExpandAlias(urlStr); aliasCfg != nil || newClientURL(urlStr).Type != fileSystem {
+			expandedURLs = append(expandedURLs, urlStr)
+			continue
+		}
+		if _, e := os.Lstat(urlStr); e == nil {
+			expandedURLs = append(expandedURLs, urlStr)
+			continue
+		}
+		var matches []string
+		for content := range globFS(ctx, urlStr) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(urlStr), "Unable to expand `"+urlStr+"`.")
+				continue
+			}
+			matches = append(matches, content.URL.Path)
+		}
+		if len(matches) == 0 {
+			matches = append(matches, urlStr)
+		}
+		expandedURLs = append(expandedURLs, matches...)
+	}
+	return expandedURLs
+}
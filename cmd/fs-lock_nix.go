@@ -0,0 +1,42 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock on the file, the lock is
+// held until the returned closer is closed even if the file is closed
+// first.
+func lockFile(f *os.File) (io.Closer, error) {
+	fd, e := syscall.Dup(int(f.Fd()))
+	if e != nil {
+		return nil, e
+	}
+	if e = syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB); e != nil {
+		syscall.Close(fd)
+		return nil, e
+	}
+	return os.NewFile(uintptr(fd), f.Name()), nil
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// File in the config folder caching the MD5 sums of local files, sync is
+// the only command comparing local files by content.
+const globalMD5CacheFile = "md5-cache.json"
+
+// Sums not used for this long are dropped from the cache.
+const md5CacheExpiry = 30 * 24 * time.Hour
+
+// md5CacheEntry is the MD5 sum of a local file in a given state, the sum
+// is reused as long as the file keeps the same size, modification time
+// and inode.
+type md5CacheEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+	Dev     uint64 `json:"dev,omitempty"`
+	Inode   uint64 `json:"inode,omitempty"`
+	MD5     string `json:"md5"`
+	Used    int64  `json:"used"`
+}
+
+// md5Cache holds the sums of local files by absolute path, it is loaded on
+// first use and written back by saveMD5Cache.
+var md5Cache struct {
+	sync.Mutex
+	loaded  bool
+	dirty   bool
+	entries map[string]*md5CacheEntry
+}
+
+func getMD5CachePath() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalMD5CacheFile), nil
+}
+
+// loadMD5Cache reads the cache once, a missing or unreadable cache is
+// empty. It must be called with md5Cache locked.
+func loadMD5Cache() {
+	if md5Cache.loaded {
+		return
+	}
+	md5Cache.loaded = true
+	md5Cache.entries = make(map[string]*md5CacheEntry)
+	path, err := getMD5CachePath()
+	if err != nil {
+		return
+	}
+	if data, e := ioutil.ReadFile(path); e == nil {
+		if e = json.Unmarshal(data, &md5Cache.entries); e != nil || md5Cache.entries == nil {
+			md5Cache.entries = make(map[string]*md5CacheEntry)
+		}
+	}
+}
+
+// saveMD5Cache atomically replaces the cache file if sums were added or
+// used, dropping the expired ones.
+func saveMD5Cache() *probe.Error {
+	md5Cache.Lock()
+	defer md5Cache.Unlock()
+	if !md5Cache.dirty {
+		return nil
+	}
+	expired := time.Now().Add(-md5CacheExpiry).Unix()
+	for fpath, entry := range md5Cache.entries {
+		if entry.Used < expired {
+			delete(md5Cache.entries, fpath)
+		}
+	}
+	path, err := getMD5CachePath()
+	if err != nil {
+		return err.Trace()
+	}
+	if e := os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return probe.NewError(e)
+	}
+	data, e := json.Marshal(md5Cache.entries)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	tmpPath := path + ".tmp"
+	if e = ioutil.WriteFile(tmpPath, data, 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.Rename(tmpPath, path); e != nil {
+		return probe.NewError(e)
+	}
+	md5Cache.dirty = false
+	return nil
+}
+
+// localMD5 returns the hex encoded MD5 sum of a local file, reading the
+// file only if it changed since its sum was cached.
+func localMD5(fpath string) (string, error) {
+	if abs, e := filepath.Abs(fpath); e == nil {
+		fpath = abs
+	}
+	st, e := os.Stat(fpath)
+	if e != nil {
+		return "", e
+	}
+	id, _ := getFileID(st)
+	state := md5CacheEntry{Size: st.Size(), ModTime: st.ModTime().UnixNano(), Dev: id.dev, Inode: id.ino}
+	now := time.Now()
+
+	md5Cache.Lock()
+	loadMD5Cache()
+	entry, ok := md5Cache.entries[fpath]
+	if ok && entry.Size == state.Size && entry.ModTime == state.ModTime && entry.Dev == state.Dev && entry.Inode == state.Inode {
+		entry.Used = now.Unix()
+		md5Cache.dirty = true
+		md5Cache.Unlock()
+		return entry.MD5, nil
+	}
+	md5Cache.Unlock()
+
+	f, e := os.Open(fpath)
+	if e != nil {
+		return "", e
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, e = io.Copy(h, f); e != nil {
+		return "", e
+	}
+	state.MD5 = hex.EncodeToString(h.Sum(nil))
+
+	// Writes right after a sum was computed may keep the same modification
+	// time, the sums of recently modified files are not cached.
+	if now.Sub(st.ModTime()) > 2*time.Second {
+		state.Used = now.Unix()
+		md5Cache.Lock()
+		md5Cache.entries[fpath] = &state
+		md5Cache.dirty = true
+		md5Cache.Unlock()
+	}
+	return state.MD5, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLocalMD5(t *testing.T) {
+	root, e := ioutil.TempDir("", "md5-cache-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	defer func(configDir string) { mcCustomConfigDir = configDir }(mcCustomConfigDir)
+	mcCustomConfigDir = root
+	resetCache := func() {
+		md5Cache.loaded, md5Cache.dirty, md5Cache.entries = false, false, nil
+	}
+	resetCache()
+	defer resetCache()
+
+	fpath := filepath.Join(root, "file")
+	mtime := time.Now().Add(-time.Hour)
+	write := func(data string, mtime time.Time) {
+		if e := ioutil.WriteFile(fpath, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.Chtimes(fpath, mtime, mtime); e != nil {
+			t.Fatal(e)
+		}
+	}
+	sumOf := func(data string) string {
+		sum := md5.Sum([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+	check := func(expected string) {
+		t.Helper()
+		sum, e := localMD5(fpath)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if sum != expected {
+			t.Fatalf("expected sum %s, got %s", expected, sum)
+		}
+	}
+
+	write("hello", mtime)
+	check(sumOf("hello"))
+
+	// The cached sum is used while the size and modification time match.
+	write("jello", mtime)
+	check(sumOf("hello"))
+	write("jello", mtime.Add(time.Second))
+	check(sumOf("jello"))
+
+	// Sums are kept across runs.
+	if err := saveMD5Cache(); err != nil {
+		t.Fatal(err)
+	}
+	resetCache()
+	write("hello", mtime.Add(time.Second))
+	check(sumOf("jello"))
+
+	// The sums of recently modified files are not cached.
+	write("hello", time.Now())
+	check(sumOf("hello"))
+	if entry := md5Cache.entries[fpath]; entry != nil && entry.MD5 == sumOf("hello") {
+		t.Fatal("expected the sum of a recently modified file not to be cached")
+	}
+}
@@ -22,9 +22,20 @@ package cmd
 
 import (
 	"path/filepath"
+	"strings"
 	"syscall"
 )
 
+// Device names Windows reserves in every directory, with or without
+// an extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
 func normalizePath(path string) string {
 	if filepath.VolumeName(path) == "" && filepath.HasPrefix(path, "\\") {
 		var err error
@@ -35,3 +46,22 @@ func normalizePath(path string) string {
 	}
 	return path
 }
+
+// hasReservedName reports whether an element of path is a name the
+// OS does not allow for files. A bare device name such as NUL or CON:
+// names the device itself and is allowed.
+func hasReservedName(path string) bool {
+	path = strings.TrimPrefix(path, filepath.VolumeName(path))
+	if reservedNames[strings.ToUpper(strings.TrimSuffix(path, ":"))] {
+		return false
+	}
+	for _, name := range strings.FieldsFunc(path, func(r rune) bool { return r == '\\' || r == '/' }) {
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		}
+		if reservedNames[strings.ToUpper(strings.TrimRight(name, " "))] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+//go:build windows
+// +build windows
+
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestHasReservedName(t *testing.T) {
+	testCases := []struct {
+		path     string
+		reserved bool
+	}{
+		{`C:\data\file.txt`, false},
+		{`C:\data\con`, true},
+		{`C:\data\CON.txt`, true},
+		{`C:\data\aux \file`, true},
+		{`data\lpt1.tar.gz`, true},
+		{`C:\data\console.txt`, false},
+		{`C:\data\com10`, false},
+		{`data/nul/file`, true},
+		// Devices themselves.
+		{`NUL`, false},
+		{`nul`, false},
+		{`CON:`, false},
+		{`\\.\NUL`, false},
+	}
+	for _, testCase := range testCases {
+		if reserved := hasReservedName(testCase.path); reserved != testCase.reserved {
+			t.Errorf("hasReservedName(%q) = %t, expected %t", testCase.path, reserved, testCase.reserved)
+		}
+	}
+}
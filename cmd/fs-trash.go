@@ -0,0 +1,186 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// moveToTrash moves the local file or folder to the trash of the user
+// instead of deleting it, following the XDG trash specification, or in
+// ~/.Trash on macOS. Files on another device than the home folder are
+// moved to the trash at the top of their own filesystem.
+func moveToTrash(path string) error {
+	absPath, e := filepath.Abs(path)
+	if e != nil {
+		return e
+	}
+	fi, e := os.Lstat(absPath)
+	if e != nil {
+		return e
+	}
+	if runtime.GOOS == "windows" {
+		return errors.New("moving files to the recycle bin is not supported on windows")
+	}
+	home, e := os.UserHomeDir()
+	if e != nil {
+		return e
+	}
+	name := filepath.Base(absPath)
+
+	homeTrash := filepath.Join(home, ".Trash")
+	if runtime.GOOS != "darwin" {
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		homeTrash = filepath.Join(dataHome, "Trash")
+	}
+	trashDir, infoPath := homeTrash, absPath
+	if !isSameDevice(fi, homeTrash) {
+		if trashDir, infoPath, e = topTrashDir(absPath, fi); e != nil {
+			return e
+		}
+	}
+
+	if runtime.GOOS == "darwin" {
+		if e = os.MkdirAll(trashDir, 0o700); e != nil {
+			return e
+		}
+		for i := 1; ; i++ {
+			trashPath := filepath.Join(trashDir, trashName(name, i))
+			if _, e = os.Lstat(trashPath); os.IsNotExist(e) {
+				return trashRename(absPath, trashPath)
+			}
+		}
+	}
+
+	filesDir := filepath.Join(trashDir, "files")
+	infoDir := filepath.Join(trashDir, "info")
+	for _, dir := range []string{filesDir, infoDir} {
+		if e = os.MkdirAll(dir, 0o700); e != nil {
+			return e
+		}
+	}
+	// The info file is created first, reserving the name in the trash.
+	for i := 1; ; i++ {
+		infoFile := filepath.Join(infoDir, trashName(name, i)+".trashinfo")
+		f, e := os.OpenFile(infoFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if os.IsExist(e) {
+			continue
+		}
+		if e != nil {
+			return e
+		}
+		_, e = fmt.Fprintf(f, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+			(&url.URL{Path: infoPath}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+		if closeErr := f.Close(); e == nil {
+			e = closeErr
+		}
+		if e == nil {
+			e = trashRename(absPath, filepath.Join(filesDir, trashName(name, i)))
+		}
+		if e != nil {
+			os.Remove(infoFile)
+		}
+		return e
+	}
+}
+
+// topTrashDir returns the trash of the user at the top of the filesystem
+// of path, $topdir/.Trash/$uid if the administrator created a sticky
+// $topdir/.Trash, $topdir/.Trash-$uid otherwise, or $topdir/.Trashes/$uid
+// on macOS. The path recorded in its info files is relative to $topdir.
+func topTrashDir(path string, fi os.FileInfo) (trashDir, infoPath string, e error) {
+	topDir := mountPoint(path, fi)
+	if infoPath, e = filepath.Rel(topDir, path); e != nil {
+		return "", "", e
+	}
+	uid := strconv.Itoa(os.Getuid())
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(topDir, ".Trashes", uid), infoPath, nil
+	}
+	if st, e := os.Lstat(filepath.Join(topDir, ".Trash")); e == nil && st.IsDir() && st.Mode()&os.ModeSticky != 0 {
+		return filepath.Join(topDir, ".Trash", uid), infoPath, nil
+	}
+	return filepath.Join(topDir, ".Trash-"+uid), infoPath, nil
+}
+
+// mountPoint returns the topmost folder of path on the same device.
+func mountPoint(path string, fi os.FileInfo) string {
+	id, ok := getFileID(fi)
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		parentFi, e := os.Stat(parent)
+		if e != nil || !ok {
+			return dir
+		}
+		if parentID, _ := getFileID(parentFi); parentID.dev != id.dev {
+			return dir
+		}
+	}
+}
+
+// isSameDevice returns true if path, or its closest existing parent, is
+// on the device of fi.
+func isSameDevice(fi os.FileInfo, path string) bool {
+	id, ok := getFileID(fi)
+	if !ok {
+		return true
+	}
+	for {
+		if pathFi, e := os.Stat(path); e == nil {
+			pathID, _ := getFileID(pathFi)
+			return pathID.dev == id.dev
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return true
+		}
+		path = parent
+	}
+}
+
+// trashRename moves path to the trash, with a clear error if they are on
+// different devices.
+func trashRename(path, trashPath string) error {
+	e := os.Rename(path, trashPath)
+	if errors.Is(e, syscall.EXDEV) {
+		return fmt.Errorf("unable to move `%s` to the trash `%s` on another device", path, filepath.Dir(trashPath))
+	}
+	return e
+}
+
+// trashName returns the name of the i-th file named name in the trash.
+func trashName(name string, i int) string {
+	if i == 1 {
+		return name
+	}
+	return fmt.Sprintf("%s.%d", name, i)
+}
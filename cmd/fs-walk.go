@@ -0,0 +1,215 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	xfilepath "github.com/minio/filepath"
+)
+
+// defaultWalkWorkers is the number of local folders read concurrently
+// by recursive listings unless set with --walk-workers.
+const defaultWalkWorkers = 4
+
+// fsWalker walks a local folder like xfilepath.Walk, reading up to
+// workers folders concurrently. An ordered walker calls walkFn in the
+// order of the entries returned by readDir and only reads the folders
+// ahead, otherwise walkFn is called concurrently for different folders.
+type fsWalker struct {
+	walkFn  xfilepath.WalkFunc
+	ordered bool
+	// sem bounds the folders read by background workers, it is nil
+	// when walking sequentially.
+	sem chan struct{}
+}
+
+func newFSWalker(workers int, ordered bool, walkFn xfilepath.WalkFunc) *fsWalker {
+	w := &fsWalker{walkFn: walkFn, ordered: ordered}
+	if workers > 1 {
+		// The walking goroutine is a worker as well.
+		w.sem = make(chan struct{}, workers-1)
+	}
+	return w
+}
+
+// Walk walks the file tree rooted at root, calling walkFn for each file
+// or folder in the tree, including root.
+func (w *fsWalker) Walk(root string) error {
+	info, e := os.Lstat(root)
+	if e != nil {
+		return w.walkFn(root, nil, e)
+	}
+	if w.ordered {
+		return w.walkOrdered(root, info, nil)
+	}
+	s := &walkState{}
+	e = w.walkUnordered(root, info, s)
+	s.wg.Wait()
+	if e == nil {
+		e = s.err
+	}
+	return e
+}
+
+// dirListing is a folder read ahead of an ordered walk.
+type dirListing struct {
+	done chan struct{}
+	fis  []os.FileInfo
+	err  error
+}
+
+// readAhead reads the folder in the background, it returns nil if all
+// the workers are busy.
+func (w *fsWalker) readAhead(path string) *dirListing {
+	select {
+	case w.sem <- struct{}{}:
+	default:
+		return nil
+	}
+	l := &dirListing{done: make(chan struct{})}
+	go func() {
+		defer close(l.done)
+		l.fis, l.err = readDir(path)
+		<-w.sem
+	}()
+	return l
+}
+
+func (w *fsWalker) walkOrdered(path string, info os.FileInfo, l *dirListing) error {
+	if e := w.walkFn(path, info, nil); e != nil {
+		return walkSkipped(info, e)
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	var fis []os.FileInfo
+	var e error
+	if l != nil {
+		<-l.done
+		fis, e = l.fis, l.err
+	} else {
+		fis, e = readDir(path)
+	}
+	if e != nil {
+		return w.walkFn(path, info, e)
+	}
+
+	listings := make([]*dirListing, len(fis))
+	next := 0
+	for i, fi := range fis {
+		// Keep the idle workers reading the following sub-folders.
+		if next <= i {
+			next = i + 1
+		}
+		for ; next < len(fis); next++ {
+			if !fis[next].IsDir() {
+				continue
+			}
+			if listings[next] = w.readAhead(filepath.Join(path, fis[next].Name())); listings[next] == nil {
+				break
+			}
+		}
+		if e = w.walkOrdered(filepath.Join(path, fi.Name()), fi, listings[i]); e != nil {
+			if e == xfilepath.ErrSkipDir || e == xfilepath.ErrSkipFile {
+				return nil
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+// walkState collects the first error of the folders walked by the
+// background workers of an unordered walk.
+type walkState struct {
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	err error
+}
+
+func (s *walkState) setErr(e error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = e
+	}
+	s.mu.Unlock()
+}
+
+func (s *walkState) failed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err != nil
+}
+
+func (w *fsWalker) walkUnordered(path string, info os.FileInfo, s *walkState) error {
+	// Stop walking once a worker failed.
+	if s.failed() {
+		return nil
+	}
+	if e := w.walkFn(path, info, nil); e != nil {
+		return walkSkipped(info, e)
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	fis, e := readDir(path)
+	if e != nil {
+		return w.walkFn(path, info, e)
+	}
+	for _, fi := range fis {
+		fp := filepath.Join(path, fi.Name())
+		if fi.IsDir() {
+			select {
+			case w.sem <- struct{}{}:
+				s.wg.Add(1)
+				go func(fp string, fi os.FileInfo) {
+					defer s.wg.Done()
+					if e := w.walkUnordered(fp, fi, s); e != nil && e != xfilepath.ErrSkipDir && e != xfilepath.ErrSkipFile {
+						s.setErr(e)
+					}
+					<-w.sem
+				}(fp, fi)
+				continue
+			default:
+			}
+		}
+		if e = w.walkUnordered(fp, fi, s); e != nil {
+			if e == xfilepath.ErrSkipDir || e == xfilepath.ErrSkipFile {
+				return nil
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+// walkSkipped returns nil if walkFn skipped the folder or file at path.
+func walkSkipped(info os.FileInfo, e error) error {
+	if info.Mode().IsDir() && e == xfilepath.ErrSkipDir {
+		return nil
+	}
+	if info.Mode().IsRegular() && e == xfilepath.ErrSkipFile {
+		return nil
+	}
+	return e
+}
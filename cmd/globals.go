@@ -20,10 +20,17 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/url"
+	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/limiter"
+	minio "github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/console"
 )
 
@@ -65,6 +72,21 @@ var (
 	globalDevMode        = false  // dev flag set via command line
 	globalSubnetProxyURL *url.URL // Proxy to be used for communication with subnet
 
+	globalLimitUpload   *limiter.Limiter // Upload bandwidth limit set via command line
+	globalLimitDownload *limiter.Limiter // Download bandwidth limit set via command line
+
+	globalSymlinks    = symlinksDefault    // Symlink policy of local listings set via command line
+	globalSkipHidden  = false              // Skip hidden files of local listings set via command line
+	globalWalkWorkers = defaultWalkWorkers // Folders read concurrently by local listings set via command line
+	globalTrash       = false              // Move removed local files to the trash set via command line
+	globalDirectIO    = false              // Read local files bypassing the page cache set via command line
+	globalVerifyETag  = false              // Verify the ETags returned for uploads set via command line
+
+	globalMaxRetry   = minio.MaxRetry         // Attempts of failed requests set via command line
+	globalRetryDelay = minio.DefaultRetryUnit // Base delay between attempts set via command line
+
+	globalSpecialFiles = specialFilesSkip // Policy for FIFOs, sockets and devices of local listings set via command line
+
 	globalContext, globalCancel = context.WithCancel(context.Background())
 )
 
@@ -74,6 +96,9 @@ var (
 
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
+
+	// Client certificate presented to endpoints requesting one
+	globalClientCerts []tls.Certificate
 )
 
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
@@ -114,5 +139,132 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 	}
 
 	setGlobals(quiet, debug, json, noColor, insecure, devMode, proxyURL)
-	return nil
+
+	proxy := ctx.String("proxy")
+	if proxy == "" {
+		proxy = ctx.GlobalString("proxy")
+	}
+	if globalProxyURL, e = parseProxyURL(proxy); e != nil {
+		return e
+	}
+
+	resolve := ctx.StringSlice("resolve")
+	if !ctx.IsSet("resolve") {
+		resolve = ctx.GlobalStringSlice("resolve")
+	}
+	dnsResolver := ctx.String("dns-resolver")
+	if dnsResolver == "" {
+		dnsResolver = ctx.GlobalString("dns-resolver")
+	}
+	dnsCacheTTL := ctx.Duration("dns-cache-ttl")
+	if dnsCacheTTL == 0 {
+		dnsCacheTTL = ctx.GlobalDuration("dns-cache-ttl")
+	}
+
+	// Transient errors are retried by minio-go with a jittered exponential
+	// backoff, requests with a non seekable body are sent only once.
+	maxRetry := ctx.GlobalInt("max-retry")
+	if ctx.IsSet("max-retry") {
+		maxRetry = ctx.Int("max-retry")
+	}
+	retryDelay := ctx.GlobalDuration("retry-delay")
+	if ctx.IsSet("retry-delay") {
+		retryDelay = ctx.Duration("retry-delay")
+	}
+	if maxRetry < 1 {
+		return errors.New("--max-retry must be at least 1")
+	}
+	if retryDelay <= 0 {
+		return errors.New("--retry-delay must be positive")
+	}
+	globalMaxRetry = maxRetry
+	globalRetryDelay = retryDelay
+
+	symlinks := ctx.String("symlinks")
+	if symlinks == "" {
+		symlinks = ctx.GlobalString("symlinks")
+	}
+	switch symlinks {
+	case symlinksDefault, symlinksFollow, symlinksSkip, symlinksLink:
+		globalSymlinks = symlinks
+	default:
+		return fmt.Errorf("Unrecognized --symlinks value `%s`. Valid options are `[follow, skip, link]`", symlinks)
+	}
+
+	specialFiles := ctx.String("special-files")
+	if specialFiles == "" {
+		specialFiles = ctx.GlobalString("special-files")
+	}
+	switch specialFiles {
+	case "":
+	case specialFilesSkip, specialFilesFail, specialFilesMetadata:
+		globalSpecialFiles = specialFiles
+	default:
+		return fmt.Errorf("Unrecognized --special-files value `%s`. Valid options are `[skip, fail, metadata]`", specialFiles)
+	}
+
+	globalSkipHidden = ctx.Bool("skip-hidden") || ctx.GlobalBool("skip-hidden")
+	globalTrash = ctx.Bool("trash") || ctx.GlobalBool("trash")
+
+	walkWorkers := ctx.GlobalInt("walk-workers")
+	if ctx.IsSet("walk-workers") {
+		walkWorkers = ctx.Int("walk-workers")
+	}
+	if walkWorkers < 1 {
+		return errors.New("--walk-workers must be at least 1")
+	}
+	globalWalkWorkers = walkWorkers
+
+	globalDirectIO = ctx.Bool("direct-io") || ctx.GlobalBool("direct-io")
+
+	globalVerifyETag = ctx.Bool("verify-etag") || ctx.GlobalBool("verify-etag")
+	if globalFIPS && globalVerifyETag {
+		return errors.New("Unable to use --verify-etag in FIPS mode, MD5 is not a FIPS approved algorithm")
+	}
+
+	caBundle := ctx.String("ca-bundle")
+	if caBundle == "" {
+		caBundle = ctx.GlobalString("ca-bundle")
+	}
+	if caBundle != "" {
+		if e = loadCABundle(caBundle); e != nil {
+			return fmt.Errorf("Unable to load --ca-bundle `%s`: %v", caBundle, e)
+		}
+	}
+	clientCert := ctx.String("client-cert")
+	if clientCert == "" {
+		clientCert = ctx.GlobalString("client-cert")
+	}
+	clientKey := ctx.String("client-key")
+	if clientKey == "" {
+		clientKey = ctx.GlobalString("client-key")
+	}
+	if e = loadClientCert(clientCert, clientKey); e != nil {
+		return e
+	}
+
+	if globalLimitUpload, e = parseBandwidthLimit(ctx, "limit-upload"); e != nil {
+		return e
+	}
+	if globalLimitDownload, e = parseBandwidthLimit(ctx, "limit-download"); e != nil {
+		return e
+	}
+	return setGlobalResolver(resolve, dnsResolver, dnsCacheTTL)
+}
+
+// parseBandwidthLimit returns the limiter for a rate such as 1MiB or
+// 1MiB/s, nil if the flag is not set.
+func parseBandwidthLimit(ctx *cli.Context, name string) (*limiter.Limiter, error) {
+	value := ctx.String(name)
+	if value == "" {
+		value = ctx.GlobalString(name)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	bytesPerSec, e := humanize.ParseBytes(strings.TrimSuffix(value, "/s"))
+	if e != nil {
+		return nil, fmt.Errorf("Unable to parse --%s value `%s`: %v", name, value, e)
+	}
+	return limiter.New(bytesPerSec), nil
 }
@@ -183,6 +183,8 @@ func parseHeadSyntax(ctx *cli.Context) (args []string, versionID string, timeRef
 
 // mainHead is the main entry point for head command.
 func mainHead(ctx *cli.Context) error {
+	applyAliasFlags(ctx, ctx.Args())
+
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
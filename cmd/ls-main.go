@@ -53,6 +53,10 @@ var (
 			Name:  "summarize",
 			Usage: "display summary information (number of objects, total size)",
 		},
+		cli.BoolFlag{
+			Name:  "owner",
+			Usage: "display the owner of each object",
+		},
 	}
 )
 
@@ -102,6 +106,12 @@ EXAMPLES:
 
   9. List all objects on mybucket, summarize the number of objects and total size.
      {{.Prompt}} {{.HelpName}} --summarize s3/mybucket/
+
+  10. List all contents under a prefix recursively using the '...' suffix, same as --recursive.
+     {{.Prompt}} {{.HelpName}} s3/mybucket/photos/...
+
+  11. List all objects on mybucket along with their owner.
+     {{.Prompt}} {{.HelpName}} --owner s3/mybucket/
 `,
 }
 
@@ -147,7 +157,7 @@ func parseRewindFlag(rewind string) (timeRef time.Time) {
 }
 
 // checkListSyntax - validate all the passed arguments
-func checkListSyntax(ctx context.Context, cliCtx *cli.Context) ([]string, bool, bool, bool, time.Time, bool) {
+func checkListSyntax(ctx context.Context, cliCtx *cli.Context) ([]string, bool, bool, bool, time.Time, bool, bool) {
 	args := cliCtx.Args()
 	if !cliCtx.Args().Present() {
 		args = []string{"."}
@@ -162,17 +172,21 @@ func checkListSyntax(ctx context.Context, cliCtx *cli.Context) ([]string, bool,
 	isIncomplete := cliCtx.Bool("incomplete")
 	withOlderVersions := cliCtx.Bool("versions")
 	isSummary := cliCtx.Bool("summarize")
+	showOwner := cliCtx.Bool("owner")
 
 	timeRef := parseRewindFlag(cliCtx.String("rewind"))
 	if timeRef.IsZero() && withOlderVersions {
 		timeRef = time.Now().UTC()
 	}
 
-	return args, isRecursive, isIncomplete, isSummary, timeRef, withOlderVersions
+	return args, isRecursive, isIncomplete, isSummary, timeRef, withOlderVersions, showOwner
 }
 
 // mainList - is a handler for mc ls command
 func mainList(cliCtx *cli.Context) error {
+	trimRecursiveArgs(cliCtx)
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelList := context.WithCancel(globalContext)
 	defer cancelList()
 
@@ -182,13 +196,16 @@ func mainList(cliCtx *cli.Context) error {
 	console.SetColor("PUT", color.New(color.FgGreen))
 	console.SetColor("VersionID", color.New(color.FgHiBlue))
 	console.SetColor("VersionOrd", color.New(color.FgHiMagenta))
+	console.SetColor("Latest", color.New(color.FgHiGreen))
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Owner", color.New(color.FgMagenta))
+	console.SetColor("SC", color.New(color.FgBlue))
 	console.SetColor("Summarize", color.New(color.Bold))
 
 	// check 'ls' cliCtx arguments.
-	args, isRecursive, isIncomplete, isSummary, timeRef, withOlderVersions := checkListSyntax(ctx, cliCtx)
+	args, isRecursive, isIncomplete, isSummary, timeRef, withOlderVersions, showOwner := checkListSyntax(ctx, cliCtx)
 
 	var cErr error
 	for _, targetURL := range args {
@@ -203,7 +220,7 @@ func mainList(cliCtx *cli.Context) error {
 				fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
 			}
 		}
-		if e := doList(ctx, clnt, isRecursive, isIncomplete, isSummary, timeRef, withOlderVersions); e != nil {
+		if e := doList(ctx, clnt, isRecursive, isIncomplete, isSummary, timeRef, withOlderVersions, showOwner); e != nil {
 			cErr = e
 		}
 	}
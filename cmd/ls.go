@@ -49,13 +49,29 @@ type contentMessage struct {
 	VersionID      string `json:"versionId,omitempty"`
 	VersionOrd     int    `json:"versionOrdinal,omitempty"`
 	VersionIndex   int    `json:"versionIndex,omitempty"`
+	IsLatest       bool   `json:"isLatest,omitempty"`
 	IsDeleteMarker bool   `json:"isDeleteMarker,omitempty"`
+	Owner          string `json:"owner,omitempty"`
+	UploadID       string `json:"uploadId,omitempty"`
+	StorageClass   string `json:"storageClass,omitempty"`
+
+	showOwner bool
 }
 
 // String colorized string message.
 func (c contentMessage) String() string {
 	message := console.Colorize("Time", fmt.Sprintf("[%s]", c.Time.Format(printDate)))
 	message += console.Colorize("Size", fmt.Sprintf("%7s", strings.Join(strings.Fields(humanize.IBytes(uint64(c.Size))), "")))
+	if c.showOwner {
+		owner := c.Owner
+		if owner == "" {
+			owner = "-"
+		}
+		message += console.Colorize("Owner", fmt.Sprintf(" %-12s", owner))
+	}
+	if c.StorageClass != "" {
+		message += console.Colorize("SC", " "+c.StorageClass)
+	}
 	fileDesc := ""
 
 	if c.VersionID != "" {
@@ -65,6 +81,11 @@ func (c contentMessage) String() string {
 		} else {
 			fileDesc += console.Colorize("PUT", " PUT")
 		}
+		if c.IsLatest {
+			fileDesc += console.Colorize("Latest", " LATEST")
+		} else {
+			fileDesc += "       "
+		}
 	}
 
 	fileDesc += " " + c.Key
@@ -138,6 +159,10 @@ func generateContentMessages(clntURL ClientURL, ctnts []*ClientContent, printAll
 		contentMsg.Key = getKey(c)
 		contentMsg.VersionID = c.VersionID
 		contentMsg.IsDeleteMarker = c.IsDeleteMarker
+		contentMsg.IsLatest = c.IsLatest
+		contentMsg.Owner = c.Owner
+		contentMsg.UploadID = c.UploadID
+		contentMsg.StorageClass = c.StorageClass
 		contentMsg.VersionOrd = nrVersions - i
 		// URL is empty by default
 		// Set it to either relative dir (host) or public url (remote)
@@ -186,16 +211,17 @@ func (s summaryMessage) JSON() string {
 }
 
 // Pretty print the list of versions belonging to one object
-func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions, isSummary bool) {
+func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions, isSummary, showOwner bool) {
 	sortObjectVersions(ctntVersions)
 	msgs := generateContentMessages(clntURL, ctntVersions, printAllVersions)
 	for _, msg := range msgs {
+		msg.showOwner = showOwner
 		printMsg(msg)
 	}
 }
 
 // doList - list all entities inside a folder.
-func doList(ctx context.Context, clnt Client, isRecursive, isIncomplete, isSummary bool, timeRef time.Time, withOlderVersions bool) error {
+func doList(ctx context.Context, clnt Client, isRecursive, isIncomplete, isSummary bool, timeRef time.Time, withOlderVersions, showOwner bool) error {
 	var (
 		lastPath          string
 		perObjectVersions []*ClientContent
@@ -239,7 +265,7 @@ func doList(ctx context.Context, clnt Client, isRecursive, isIncomplete, isSumma
 
 		if lastPath != content.URL.Path {
 			// Print any object in the current list before reinitializing it
-			printObjectVersions(clnt.GetURL(), perObjectVersions, withOlderVersions, isSummary)
+			printObjectVersions(clnt.GetURL(), perObjectVersions, withOlderVersions, isSummary, showOwner)
 			lastPath = content.URL.Path
 			perObjectVersions = []*ClientContent{}
 		}
@@ -249,7 +275,7 @@ func doList(ctx context.Context, clnt Client, isRecursive, isIncomplete, isSumma
 		totalObjects++
 	}
 
-	printObjectVersions(clnt.GetURL(), perObjectVersions, withOlderVersions, isSummary)
+	printObjectVersions(clnt.GetURL(), perObjectVersions, withOlderVersions, isSummary, showOwner)
 
 	if isSummary {
 		printMsg(summaryMessage{
@@ -16,3 +16,89 @@
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateContentMessagesVersions(t *testing.T) {
+	now := time.Now()
+	clntURL := *newClientURL("http://localhost:9000/bucket/")
+	versions := []*ClientContent{
+		{URL: *newClientURL("http://localhost:9000/bucket/object"), VersionID: "v1", Time: now.Add(-2 * time.Hour)},
+		{URL: *newClientURL("http://localhost:9000/bucket/object"), VersionID: "v3", Time: now, IsLatest: true, IsDeleteMarker: true},
+		{URL: *newClientURL("http://localhost:9000/bucket/object"), VersionID: "v2", Time: now.Add(-time.Hour)},
+	}
+
+	sortObjectVersions(versions)
+	msgs := generateContentMessages(clntURL, versions, true)
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(msgs))
+	}
+
+	expected := []struct {
+		versionID      string
+		versionOrd     int
+		isLatest       bool
+		isDeleteMarker bool
+	}{
+		{"v3", 3, true, true},
+		{"v2", 2, false, false},
+		{"v1", 1, false, false},
+	}
+	for i, msg := range msgs {
+		if msg.Key != "object" {
+			t.Errorf("Test %d: expected key `object`, got `%s`", i+1, msg.Key)
+		}
+		if msg.VersionID != expected[i].versionID || msg.VersionOrd != expected[i].versionOrd {
+			t.Errorf("Test %d: expected version %s (v%d), got %s (v%d)", i+1, expected[i].versionID, expected[i].versionOrd, msg.VersionID, msg.VersionOrd)
+		}
+		if msg.IsLatest != expected[i].isLatest {
+			t.Errorf("Test %d: expected latest %v, got %v", i+1, expected[i].isLatest, msg.IsLatest)
+		}
+		if msg.IsDeleteMarker != expected[i].isDeleteMarker {
+			t.Errorf("Test %d: expected delete marker %v, got %v", i+1, expected[i].isDeleteMarker, msg.IsDeleteMarker)
+		}
+	}
+
+	// Only the latest version is printed when not asked for all versions.
+	if msgs = generateContentMessages(clntURL, versions, false); len(msgs) != 1 || !msgs[0].IsLatest {
+		t.Fatalf("expected only the latest version, got %v", msgs)
+	}
+}
+
+func TestContentMessageOwner(t *testing.T) {
+	clntURL := *newClientURL("http://localhost:9000/bucket/")
+	contents := []*ClientContent{
+		{URL: *newClientURL("http://localhost:9000/bucket/object"), Owner: "minio"},
+	}
+
+	msgs := generateContentMessages(clntURL, contents, false)
+	if len(msgs) != 1 || msgs[0].Owner != "minio" {
+		t.Fatalf("expected owner `minio`, got %v", msgs)
+	}
+	if strings.Contains(msgs[0].String(), "minio") {
+		t.Errorf("expected no owner column by default, got %q", msgs[0].String())
+	}
+	msgs[0].showOwner = true
+	if !strings.Contains(msgs[0].String(), "minio") {
+		t.Errorf("expected owner column, got %q", msgs[0].String())
+	}
+}
+
+func TestContentMessageStorageClass(t *testing.T) {
+	clntURL := *newClientURL("http://localhost:9000/bucket/")
+	contents := []*ClientContent{
+		{URL: *newClientURL("http://localhost:9000/bucket/object"), StorageClass: "REDUCED_REDUNDANCY"},
+	}
+
+	msgs := generateContentMessages(clntURL, contents, false)
+	if len(msgs) != 1 || msgs[0].StorageClass != "REDUCED_REDUNDANCY" {
+		t.Fatalf("expected storage class `REDUCED_REDUNDANCY`, got %v", msgs)
+	}
+	if !strings.Contains(msgs[0].String(), "REDUCED_REDUNDANCY") {
+		t.Errorf("expected storage class column, got %q", msgs[0].String())
+	}
+}
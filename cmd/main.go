@@ -423,6 +423,8 @@ var appCmds = []cli.Command{
 	rbCmd,
 	cpCmd,
 	mirrorCmd,
+	syncCmd,
+	flushCmd,
 	catCmd,
 	headCmd,
 	pipeCmd,
@@ -436,6 +438,7 @@ var appCmds = []cli.Command{
 	retentionCmd,
 	legalHoldCmd,
 	diffCmd,
+	manifestCmd,
 	rmCmd,
 	versionCmd,
 	ilmCmd,
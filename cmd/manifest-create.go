@@ -0,0 +1,145 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var manifestCreateFlags = []cli.Flag{
+	manifestKeyFlag,
+}
+
+var manifestCreateCmd = cli.Command{
+	Name:         "create",
+	Usage:        "write a signed listing of a bucket or folder",
+	Action:       mainManifestCreate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(manifestCreateFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET MANIFEST
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Read every object under TARGET and write its key, size and SHA-256 checksum into the
+  local file MANIFEST. The manifest is signed with the secret key, so that it can be used
+  with 'mc manifest verify' to prove that a copy of TARGET is complete and intact.
+
+ENVIRONMENT VARIABLES:
+  MC_MANIFEST_KEY:  secret key to sign the manifest
+
+EXAMPLES:
+  1. Write the manifest of a local folder before migrating it.
+     {{.Prompt}} {{.HelpName}} --key mysecret ~/Photos photos.manifest
+
+  2. Write the manifest of a prefix, taking the key from the environment.
+     {{.Prompt}} export MC_MANIFEST_KEY=mysecret
+     {{.Prompt}} {{.HelpName}} play/mybucket/photos photos.manifest
+`,
+}
+
+// manifestCreateMessage container for manifest create messages.
+type manifestCreateMessage struct {
+	Status   string `json:"status"`
+	Source   string `json:"source"`
+	Manifest string `json:"manifest"`
+	Objects  int    `json:"objects"`
+	Size     int64  `json:"size"`
+}
+
+// String colorized manifest create message.
+func (m manifestCreateMessage) String() string {
+	return console.Colorize("ManifestCreate", fmt.Sprintf("Manifest of `%s` written to `%s` (%d objects, %s).",
+		m.Source, m.Manifest, m.Objects, humanize.IBytes(uint64(m.Size))))
+}
+
+// JSON jsonified manifest create message.
+func (m manifestCreateMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkManifestCreateSyntax - validate all the passed arguments
+func checkManifestCreateSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(cliCtx, "create", 1) // last argument is exit code
+	}
+}
+
+// mainManifestCreate is the handle for "mc manifest create" command.
+func mainManifestCreate(cliCtx *cli.Context) error {
+	ctx, cancelManifestCreate := context.WithCancel(globalContext)
+	defer cancelManifestCreate()
+
+	checkManifestCreateSyntax(cliCtx)
+	key := getManifestKey(cliCtx)
+
+	console.SetColor("ManifestCreate", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	args := cliCtx.Args()
+	target, manifestPath := args[0], args[1]
+
+	alias, rootURL, objects, err := listManifestObjects(ctx, target)
+	fatalIf(err, "Unable to list `"+target+"`.")
+
+	m := &manifest{
+		Version:   manifestVersion,
+		Source:    target,
+		Created:   time.Now().UTC(),
+		Algorithm: manifestAlgorithm,
+		Objects:   make([]manifestEntry, 0, len(objects)),
+	}
+	var totalSize int64
+	for _, object := range objects {
+		checksum, size, err := computeChecksum(ctx, alias, urlJoinPath(rootURL, object.key), encKeyDB)
+		fatalIf(err, "Unable to read `"+object.key+"`.")
+		m.Objects = append(m.Objects, manifestEntry{Key: object.key, Size: size, Checksum: checksum})
+		totalSize += size
+	}
+	m.sign(key)
+
+	fatalIf(saveManifest(manifestPath, m), "Unable to write the manifest `"+manifestPath+"`.")
+
+	printMsg(manifestCreateMessage{
+		Source:   target,
+		Manifest: manifestPath,
+		Objects:  len(m.Objects),
+		Size:     totalSize,
+	})
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var manifestVerifyFlags = []cli.Flag{
+	manifestKeyFlag,
+}
+
+var manifestVerifyCmd = cli.Command{
+	Name:         "verify",
+	Usage:        "check a bucket or folder against a signed listing",
+	Action:       mainManifestVerify,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(manifestVerifyFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] MANIFEST TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Check the signature of MANIFEST, then read every object it lists under TARGET and compare
+  its size and SHA-256 checksum. Missing, different and unexpected objects are reported, the
+  command fails if any listed object is missing or different.
+
+ENVIRONMENT VARIABLES:
+  MC_MANIFEST_KEY:  secret key to verify the manifest
+
+EXAMPLES:
+  1. Verify that a local folder was completely migrated to a bucket.
+     {{.Prompt}} {{.HelpName}} --key mysecret photos.manifest play/mybucket/photos
+
+  2. Verify a bucket and print the results in JSON format.
+     {{.Prompt}} {{.HelpName}} --json --key mysecret photos.manifest s3/mybucket/photos
+`,
+}
+
+// manifestVerifyMessage container for the result of one object.
+type manifestVerifyMessage struct {
+	Status  string `json:"status"`
+	Key     string `json:"key"`
+	Result  string `json:"result"`
+	Details string `json:"details,omitempty"`
+}
+
+// String colorized manifest verify message.
+func (m manifestVerifyMessage) String() string {
+	msg := fmt.Sprintf("%-10s `%s`", m.Result, m.Key)
+	if m.Details != "" {
+		msg += ": " + m.Details
+	}
+	return console.Colorize("ManifestVerify", msg)
+}
+
+// JSON jsonified manifest verify message.
+func (m manifestVerifyMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// manifestVerifySummary container for the result of the whole verification.
+type manifestVerifySummary struct {
+	Status     string `json:"status"`
+	Target     string `json:"target"`
+	Verified   int    `json:"verified"`
+	Missing    int    `json:"missing"`
+	Different  int    `json:"different"`
+	Unexpected int    `json:"unexpected"`
+}
+
+// String colorized manifest verify summary.
+func (m manifestVerifySummary) String() string {
+	msg := fmt.Sprintf("`%s`: %d verified, %d missing, %d different, %d unexpected.",
+		m.Target, m.Verified, m.Missing, m.Different, m.Unexpected)
+	if m.Missing > 0 || m.Different > 0 {
+		return console.Colorize("ManifestFailed", msg)
+	}
+	return console.Colorize("ManifestOK", msg)
+}
+
+// JSON jsonified manifest verify summary.
+func (m manifestVerifySummary) JSON() string {
+	m.Status = "success"
+	if m.Missing > 0 || m.Different > 0 {
+		m.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkManifestVerifySyntax - validate all the passed arguments
+func checkManifestVerifySyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(cliCtx, "verify", 1) // last argument is exit code
+	}
+}
+
+// mainManifestVerify is the handle for "mc manifest verify" command.
+func mainManifestVerify(cliCtx *cli.Context) error {
+	ctx, cancelManifestVerify := context.WithCancel(globalContext)
+	defer cancelManifestVerify()
+
+	checkManifestVerifySyntax(cliCtx)
+	key := getManifestKey(cliCtx)
+
+	console.SetColor("ManifestVerify", color.New(color.FgYellow))
+	console.SetColor("ManifestOK", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ManifestFailed", color.New(color.FgRed, color.Bold))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	args := cliCtx.Args()
+	manifestPath, target := args[0], args[1]
+
+	m, err := loadManifest(manifestPath)
+	fatalIf(err, "Unable to load the manifest `"+manifestPath+"`.")
+	if !m.verifySignature(key) {
+		fatalIf(errInvalidArgument().Trace(manifestPath), "Invalid signature, the manifest was modified or signed with another key.")
+	}
+
+	alias, rootURL, objects, err := listManifestObjects(ctx, target)
+	fatalIf(err, "Unable to list `"+target+"`.")
+	found := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		found[object.key] = false
+	}
+
+	summary := manifestVerifySummary{Target: target}
+	for _, entry := range m.Objects {
+		if _, ok := found[entry.Key]; !ok {
+			summary.Missing++
+			printMsg(manifestVerifyMessage{Key: entry.Key, Result: "missing"})
+			continue
+		}
+		found[entry.Key] = true
+
+		checksum, size, err := computeChecksum(ctx, alias, urlJoinPath(rootURL, entry.Key), encKeyDB)
+		if err != nil {
+			errorIf(err, "Unable to read `"+entry.Key+"`.")
+			summary.Different++
+			continue
+		}
+		switch {
+		case size != entry.Size:
+			summary.Different++
+			printMsg(manifestVerifyMessage{Key: entry.Key, Result: "different",
+				Details: fmt.Sprintf("expected %d bytes, found %d bytes", entry.Size, size)})
+		case checksum != entry.Checksum:
+			summary.Different++
+			printMsg(manifestVerifyMessage{Key: entry.Key, Result: "different",
+				Details: fmt.Sprintf("expected checksum %s, found %s", entry.Checksum, checksum)})
+		default:
+			summary.Verified++
+		}
+	}
+	for _, object := range objects {
+		if !found[object.key] {
+			summary.Unexpected++
+			printMsg(manifestVerifyMessage{Key: object.key, Result: "unexpected"})
+		}
+	}
+
+	printMsg(summary)
+	if summary.Missing > 0 || summary.Different > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
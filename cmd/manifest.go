@@ -0,0 +1,185 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Current version of the manifest format.
+const manifestVersion = "1"
+
+// Only supported checksum algorithm of manifest entries.
+const manifestAlgorithm = "sha256"
+
+var manifestKeyFlag = cli.StringFlag{
+	Name:   "key",
+	Usage:  "secret key to sign and verify the manifest",
+	EnvVar: "MC_MANIFEST_KEY",
+}
+
+// manifestEntry is one object of a manifest.
+type manifestEntry struct {
+	Key      string `json:"key"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// manifest is a signed listing of a prefix or directory.
+type manifest struct {
+	Version   string          `json:"version"`
+	Source    string          `json:"source"`
+	Created   time.Time       `json:"created"`
+	Algorithm string          `json:"algorithm"`
+	Objects   []manifestEntry `json:"objects"`
+	Signature string          `json:"signature"`
+}
+
+// computeSignature returns the HMAC of every field except the signature.
+func (m manifest) computeSignature(key string) string {
+	m.Signature = ""
+	data, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal the manifest.")
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sign signs the manifest with key.
+func (m *manifest) sign(key string) {
+	m.Signature = m.computeSignature(key)
+}
+
+// verifySignature returns true if the manifest was signed with key and
+// not modified since.
+func (m manifest) verifySignature(key string) bool {
+	return hmac.Equal([]byte(m.Signature), []byte(m.computeSignature(key)))
+}
+
+// saveManifest writes a manifest into a local file.
+func saveManifest(path string, m *manifest) *probe.Error {
+	data, e := json.MarshalIndent(m, "", "  ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = ioutil.WriteFile(path, data, 0o644); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// loadManifest reads a manifest from a local file.
+func loadManifest(path string) (*manifest, *probe.Error) {
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	m := &manifest{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if e = decoder.Decode(m); e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	if m.Version != manifestVersion {
+		return nil, errInvalidArgument().Trace(path, m.Version)
+	}
+	if m.Algorithm != manifestAlgorithm {
+		return nil, errInvalidArgument().Trace(path, m.Algorithm)
+	}
+	return m, nil
+}
+
+// manifestObject is an object found under the root of a manifest.
+type manifestObject struct {
+	key     string
+	content *ClientContent
+}
+
+// listManifestObjects lists all objects under a prefix or directory sorted
+// by their key relative to it.
+func listManifestObjects(ctx context.Context, aliasedURL string) (alias, rootURL string, objects []manifestObject, err *probe.Error) {
+	separator := string(newClientURL(aliasedURL).Separator)
+	if !strings.HasSuffix(aliasedURL, separator) {
+		aliasedURL += separator
+	}
+	alias, rootURL, _ = mustExpandAlias(aliasedURL)
+	clnt, err := newClientFromAlias(alias, rootURL)
+	if err != nil {
+		return "", "", nil, err.Trace(aliasedURL)
+	}
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			return "", "", nil, content.Err.Trace(aliasedURL)
+		}
+		if !content.Type.IsRegular() {
+			continue
+		}
+		key := filepath.ToSlash(strings.TrimPrefix(content.URL.String(), rootURL))
+		objects = append(objects, manifestObject{key: key, content: content})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].key < objects[j].key })
+	return alias, rootURL, objects, nil
+}
+
+// computeChecksum reads an object and returns its checksum and size.
+func computeChecksum(ctx context.Context, alias, urlStr string, encKeyDB map[string][]prefixSSEPair) (string, int64, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return "", 0, err.Trace(urlStr)
+	}
+	sse := getSSE(filepath.ToSlash(filepath.Join(alias, clnt.GetURL().Path)), encKeyDB[alias])
+	reader, err := clnt.Get(ctx, GetOptions{SSE: sse})
+	if err != nil {
+		return "", 0, err.Trace(urlStr)
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	n, e := io.Copy(hasher, reader)
+	if e != nil {
+		return "", 0, probe.NewError(e).Trace(urlStr)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), n, nil
+}
+
+// getManifestKey returns the signing key or exits if none was given.
+func getManifestKey(cliCtx *cli.Context) string {
+	key := cliCtx.String("key")
+	if key == "" {
+		key = os.Getenv("MC_MANIFEST_KEY")
+	}
+	if key == "" {
+		fatalIf(errInvalidArgument().Trace(), "A signing key is required, use --key or MC_MANIFEST_KEY.")
+	}
+	return key
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManifestSignature(t *testing.T) {
+	m := &manifest{
+		Version:   manifestVersion,
+		Source:    "play/mybucket",
+		Created:   time.Now().UTC(),
+		Algorithm: manifestAlgorithm,
+		Objects: []manifestEntry{
+			{Key: "a.txt", Size: 4, Checksum: "f2ca1bb6c7e907d06dafe4687e579fce76b37e4e93b7605022da52e6ccc26fd2"},
+		},
+	}
+	m.sign("secret")
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := saveManifest(path, m); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.verifySignature("secret") {
+		t.Fatal("expected saved manifest to verify")
+	}
+	if loaded.verifySignature("other") {
+		t.Fatal("expected manifest to fail with another key")
+	}
+
+	loaded.Objects[0].Size = 5
+	if loaded.verifySignature("secret") {
+		t.Fatal("expected modified manifest to fail")
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
@@ -41,6 +42,10 @@ var mbFlags = []cli.Flag{
 		Name:  "with-lock, l",
 		Usage: "enable object lock",
 	},
+	cli.StringFlag{
+		Name:  "acl",
+		Usage: "set a canned ACL on the bucket, one of 'private', 'public-read', 'public-read-write' or 'authenticated-read'",
+	},
 }
 
 // make a bucket.
@@ -81,6 +86,9 @@ EXAMPLES:
 
   7. Create a new bucket on Amazon S3 cloud storage in region 'us-west-2' with object lock enabled.
      {{.Prompt}} {{.HelpName}} --with-lock --region=us-west-2 s3/myregionbucket
+
+  8. Create a new bucket readable by everyone.
+     {{.Prompt}} {{.HelpName}} --acl public-read myminio/mypublicbucket
 `,
 }
 
@@ -109,6 +117,9 @@ func checkMakeBucketSyntax(cliCtx *cli.Context) {
 	if !cliCtx.Args().Present() {
 		cli.ShowCommandHelpAndExit(cliCtx, "mb", 1) // last argument is exit code
 	}
+	if acl := cliCtx.String("acl"); acl != "" && !isCannedBucketACL(acl) {
+		fatalIf(errInvalidArgument().Trace(acl), "Unknown canned ACL `"+acl+"`, expected one of "+strings.Join(cannedBucketACLs, ", ")+".")
+	}
 }
 
 // mainMakeBucket is entry point for mb command.
@@ -123,6 +134,7 @@ func mainMakeBucket(cli *cli.Context) error {
 	region := cli.String("region")
 	ignoreExisting := cli.Bool("p")
 	withLock := cli.Bool("l")
+	acl := cli.String("acl")
 
 	var cErr error
 	for _, targetURL := range cli.Args() {
@@ -152,6 +164,14 @@ func mainMakeBucket(cli *cli.Context) error {
 			continue
 		}
 
+		if acl != "" {
+			if err = clnt.SetBucketACL(ctx, acl); err != nil {
+				errorIf(err.Trace(targetURL, acl), "Unable to set the ACL of bucket `"+targetURL+"`.")
+				cErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+		}
+
 		// Successfully created a bucket.
 		printMsg(makeBucketMessage{Status: "success", Bucket: targetURL})
 	}
@@ -79,6 +79,14 @@ var (
 			Name:  "md5",
 			Usage: "force all upload(s) to calculate md5sum checksum",
 		},
+		cli.StringFlag{
+			Name:  "error-report",
+			Usage: "record failed objects into a report file, retry them with 'mc cp --retry-from'",
+		},
+		cli.BoolFlag{
+			Name:  "continue-on-error",
+			Usage: "keep watching and mirroring the remaining objects when an object fails to copy, instead of restarting",
+		},
 		cli.BoolFlag{
 			Name:   "multi-master",
 			Usage:  "enable multi-master multi-site setup",
@@ -92,6 +100,10 @@ var (
 			Name:  "disable-multipart",
 			Usage: "disable multipart upload feature",
 		},
+		cli.BoolFlag{
+			Name:  "hard-links",
+			Usage: "recreate hard links between local source files at a local target",
+		},
 		cli.StringSliceFlag{
 			Name:  "exclude",
 			Usage: "exclude object(s) that match specified object name pattern",
@@ -197,6 +209,16 @@ EXAMPLES:
   16. Cross mirror between sites in a active-active deployment.
       Site-A: {{.Prompt}} {{.HelpName}} --active-active siteA siteB
       Site-B: {{.Prompt}} {{.HelpName}} --active-active siteB siteA
+
+  17. Mirror a local folder recording failed objects into a report, then copy again only the failed objects.
+      {{.Prompt}} {{.HelpName}} --error-report failed.json localdir/ play/mybucket
+      {{.Prompt}} mc cp --retry-from failed.json
+
+  18. Mirror a local folder to another disk, recreating the hard links of the source.
+      {{.Prompt}} {{.HelpName}} --hard-links /data/ /mnt/backup/data/
+
+  19. Mirror a local folder to a bucket keeping the mode and owner of the files, the owner is restored when mirroring back as root.
+      {{.Prompt}} {{.HelpName}} --preserve /home/ play/backup/home/
 `,
 }
 
@@ -255,6 +277,9 @@ type mirrorJob struct {
 	targetURL string
 
 	opts mirrorOptions
+
+	// Failed copies are recorded here, if set
+	report *failureReport
 }
 
 // mirrorMessage container for file mirror messages
@@ -461,6 +486,7 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	})
 	sURLs.MD5 = mj.opts.md5
 	sURLs.DisableMultipart = mj.opts.disableMultipart
+	sURLs.HardLinks = mj.opts.hardLinks
 
 	now := time.Now()
 	ret := uploadSourceToTargetURL(ctx, sURLs, mj.status, mj.opts.encKeyDB, mj.opts.isMetadata)
@@ -488,6 +514,7 @@ func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
 				if !isErrIgnored(sURLs.Error) {
 					errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
 						fmt.Sprintf("Failed to copy `%s`.", sURLs.SourceContent.URL.String()))
+					mj.report.add(sURLs)
 					errDuringMirror = true
 				}
 			case sURLs.TargetContent != nil:
@@ -504,7 +531,7 @@ func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
 				}
 				errDuringMirror = true
 			}
-			if mj.opts.activeActive {
+			if mj.opts.activeActive && !mj.opts.continueOnError {
 				close(mj.stopCh)
 				break
 			}
@@ -592,7 +619,7 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 			}
 			mj.parallel.queueTask(func() URLs {
 				return mj.doMirrorWatch(ctx, targetPath, tgtSSE, mirrorURL)
-			}, mirrorURL.SourceContent.Size)
+			}, mirrorURL.SourceContent.Size, taskHost(mirrorURL))
 		} else if event.Type == notification.ObjectRemovedDelete {
 			if strings.Contains(event.UserAgent, uaMirrorAppName) {
 				continue
@@ -611,7 +638,7 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 			if mirrorURL.TargetContent != nil && (mj.opts.isRemove || mj.opts.activeActive) {
 				mj.parallel.queueTask(func() URLs {
 					return mj.doRemove(ctx, mirrorURL)
-				}, 0)
+				}, 0, taskHost(mirrorURL))
 			}
 		} else if event.Type == notification.BucketCreatedAll {
 			mirrorURL := URLs{
@@ -660,7 +687,7 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, stopParallel func()) {
 			if err != nil {
 				mj.parallel.queueTask(func() URLs {
 					return URLs{Error: err}
-				}, 0)
+				}, 0, "")
 			}
 		case <-globalContext.Done():
 			stopParallel()
@@ -717,11 +744,11 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 			if sURLs.SourceContent != nil {
 				mj.parallel.queueTask(func() URLs {
 					return mj.doMirror(ctx, sURLs)
-				}, sURLs.SourceContent.Size)
+				}, sURLs.SourceContent.Size, taskHost(sURLs))
 			} else if sURLs.TargetContent != nil && mj.opts.isRemove {
 				mj.parallel.queueTask(func() URLs {
 					return mj.doRemove(ctx, sURLs)
-				}, 0)
+				}, 0, taskHost(sURLs))
 			}
 		case <-globalContext.Done():
 			stopParallel()
@@ -851,7 +878,7 @@ func getEventPathURLWin(srcURL, eventPath string) string {
 }
 
 // runMirror - mirrors all buckets to another S3 server
-func runMirror(ctx context.Context, cancelMirror context.CancelFunc, srcURL, dstURL string, cli *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
+func runMirror(ctx context.Context, cancelMirror context.CancelFunc, srcURL, dstURL string, cli *cli.Context, encKeyDB map[string][]prefixSSEPair, report *failureReport) bool {
 	// Parse metadata.
 	userMetadata := make(map[string]string)
 	if cli.String("attr") != "" {
@@ -887,6 +914,8 @@ func runMirror(ctx context.Context, cancelMirror context.CancelFunc, srcURL, dst
 		isMetadata:       isMetadata,
 		md5:              cli.Bool("md5"),
 		disableMultipart: cli.Bool("disable-multipart"),
+		hardLinks:        cli.Bool("hard-links"),
+		continueOnError:  cli.Bool("continue-on-error"),
 		excludeOptions:   cli.StringSlice("exclude"),
 		olderThan:        cli.String("older-than"),
 		newerThan:        cli.String("newer-than"),
@@ -898,6 +927,7 @@ func runMirror(ctx context.Context, cancelMirror context.CancelFunc, srcURL, dst
 
 	// Create a new mirror job and execute it
 	mj := newMirrorJob(srcURL, dstURL, mopts)
+	mj.report = report
 
 	preserve := cli.Bool("preserve")
 
@@ -989,6 +1019,8 @@ func runMirror(ctx context.Context, cancelMirror context.CancelFunc, srcURL, dst
 
 // Main entry point for mirror command.
 func mainMirror(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
 
@@ -1002,6 +1034,13 @@ func mainMirror(cliCtx *cli.Context) error {
 	// check 'mirror' cli arguments.
 	srcURL, tgtURL := checkMirrorSyntax(ctx, cliCtx, encKeyDB)
 
+	var report *failureReport
+	if reportPath := cliCtx.String("error-report"); reportPath != "" {
+		report, err = newFailureReport(reportPath)
+		fatalIf(err.Trace(reportPath), "Unable to create failure report.")
+		defer report.Close()
+	}
+
 	if prometheusAddress := cliCtx.String("monitoring-address"); prometheusAddress != "" {
 		http.Handle("/metrics", promhttp.Handler())
 		go func() {
@@ -1017,7 +1056,7 @@ func mainMirror(cliCtx *cli.Context) error {
 		case <-ctx.Done():
 			return exitStatus(globalErrorExitStatus)
 		default:
-			errorDetected := runMirror(ctx, cancelMirror, srcURL, tgtURL, cliCtx, encKeyDB)
+			errorDetected := runMirror(ctx, cancelMirror, srcURL, tgtURL, cliCtx, encKeyDB, report)
 			if cliCtx.Bool("watch") || cliCtx.Bool("multi-master") || cliCtx.Bool("active-active") {
 				mirrorRestarts.Inc()
 				time.Sleep(time.Duration(r.Float64() * float64(2*time.Second)))
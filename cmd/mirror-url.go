@@ -45,6 +45,8 @@ func checkMirrorSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[st
 	srcURL = URLs[0]
 	tgtURL = URLs[1]
 
+	checkMD5Flag(cliCtx, URLs...)
+
 	if cliCtx.Bool("force") && cliCtx.Bool("remove") {
 		errorIf(errInvalidArgument().Trace(URLs...), "`--force` is deprecated, please use `--overwrite` instead with `--remove` for the same functionality.")
 	} else if cliCtx.Bool("force") {
@@ -207,7 +209,8 @@ type mirrorOptions struct {
 	isWatch, isRemove, isMetadata     bool
 	excludeOptions                    []string
 	encKeyDB                          map[string][]prefixSSEPair
-	md5, disableMultipart             bool
+	md5, disableMultipart, hardLinks  bool
+	continueOnError                   bool
 	olderThan, newerThan              string
 	storageClass                      string
 	userMetadata                      map[string]string
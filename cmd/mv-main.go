@@ -212,6 +212,9 @@ var rmManager = &removeManager{
 
 // mainMove is the entry point for mv command.
 func mainMove(cliCtx *cli.Context) error {
+	trimRecursiveArgs(cliCtx)
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelMove := context.WithCancel(globalContext)
 	defer cancelMove()
 
@@ -227,7 +230,7 @@ func mainMove(cliCtx *cli.Context) error {
 	}
 
 	// check 'copy' cli arguments.
-	checkCopySyntax(ctx, cliCtx, encKeyDB, true)
+	sourceURLs := checkCopySyntax(ctx, cliCtx, encKeyDB, true)
 
 	if cliCtx.NArg() == 2 {
 		args := cliCtx.Args()
@@ -240,7 +243,7 @@ func mainMove(cliCtx *cli.Context) error {
 
 	// Check if source URLs does not have object locking enabled
 	// since we cannot move them (remove them from the source)
-	for _, urlStr := range cliCtx.Args()[:cliCtx.NArg()-1] {
+	for _, urlStr := range sourceURLs {
 		client, err := newClient(urlStr)
 		if err != nil {
 			fatalIf(err.Trace(), "Unable to parse the provided url.")
@@ -276,8 +279,9 @@ func mainMove(cliCtx *cli.Context) error {
 
 	var session *sessionV8
 
-	if cliCtx.Bool("continue") {
-		sessionID := getHash("mv", cliCtx.Args())
+	// Re-running an interrupted command offers to resume its session.
+	sessionID := getCommandSessionID(cliCtx, getHash("mv", cliCtx.Args()))
+	if cliCtx.Bool("continue") || promptResumeSession(sessionID) {
 		if isSessionExists(sessionID) {
 			session, err = loadSessionV8(sessionID)
 			fatalIf(err.Trace(sessionID), "Unable to load session.")
@@ -304,12 +308,12 @@ func mainMove(cliCtx *cli.Context) error {
 				fatalIf(probe.NewError(e), "Unable to get current working folder.")
 			}
 
-			// extract URLs.
-			session.Header.CommandArgs = cliCtx.Args()
+			// extract URLs, with the patterns expanded.
+			session.Header.CommandArgs = append(sourceURLs, cliCtx.Args()[cliCtx.NArg()-1])
 		}
 	}
 
-	e := doCopySession(ctx, cancelMove, cliCtx, session, encKeyDB, true)
+	e := doCopySession(ctx, cancelMove, cliCtx, sourceURLs, session, encKeyDB, true)
 	if session != nil {
 		session.Delete()
 	}
@@ -0,0 +1,281 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	jsoncolor "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// Folder in the config folder holding the queued transfers.
+const globalQueueDir = "queue"
+
+// Default size cap of the queued data.
+const defaultQueueMaxSize = "1GiB"
+
+// Suffixes of the files of a queued transfer.
+const (
+	queueEntrySuffix = ".json"
+	queueDataSuffix  = ".data"
+)
+
+// queueEntry is a transfer queued while its target was unreachable, the
+// source data is spooled next to it.
+type queueEntry struct {
+	ID       string            `json:"id"`
+	Source   string            `json:"source"`
+	Target   string            `json:"target"`
+	Size     int64             `json:"size"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	URLs     URLs              `json:"urls"`
+}
+
+// offlineQueue spools transfers to a local folder until `mc flush`
+// replays them. The size of the spooled data is capped.
+type offlineQueue struct {
+	mutex   sync.Mutex
+	dir     string
+	maxSize int64
+	used    int64
+}
+
+// getQueueDir returns the folder holding the queued transfers.
+func getQueueDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalQueueDir), nil
+}
+
+// newOfflineQueue opens the queue with a size cap such as "1GiB".
+func newOfflineQueue(maxSize string) (*offlineQueue, *probe.Error) {
+	size, e := humanize.ParseBytes(maxSize)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(maxSize)
+	}
+	dir, err := getQueueDir()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if e = os.MkdirAll(dir, 0o700); e != nil {
+		return nil, probe.NewError(e)
+	}
+	entries, err := listQueueEntries(dir)
+	if err != nil {
+		return nil, err.Trace(dir)
+	}
+	q := &offlineQueue{dir: dir, maxSize: int64(size)}
+	for _, entry := range entries {
+		q.used += entry.Size
+	}
+	return q, nil
+}
+
+// add spools the source of urls and records the transfer. The size of
+// the source is reserved while it is spooled, so that transfers are
+// queued in parallel without exceeding the size cap.
+func (q *offlineQueue) add(ctx context.Context, urls URLs, encKeyDB map[string][]prefixSSEPair, preserve bool) (entry *queueEntry, err *probe.Error) {
+	reserved := urls.SourceContent.Size
+
+	q.mutex.Lock()
+	if q.used+reserved > q.maxSize {
+		q.mutex.Unlock()
+		return nil, errQueueFull(q.maxSize).Trace(urls.SourceContent.URL.String())
+	}
+	q.used += reserved
+	q.mutex.Unlock()
+	defer func() {
+		if err != nil {
+			q.mutex.Lock()
+			q.used -= reserved
+			q.mutex.Unlock()
+		}
+	}()
+
+	sourcePath := filepath.ToSlash(filepath.Join(urls.SourceAlias, urls.SourceContent.URL.Path))
+	reader, metadata, err := getSourceStream(ctx, urls.SourceAlias, urls.SourceContent.URL.String(),
+		urls.SourceContent.VersionID, true, getSSE(sourcePath, encKeyDB[urls.SourceAlias]), preserve)
+	if err != nil {
+		return nil, err.Trace(sourcePath)
+	}
+	defer reader.Close()
+
+	entry = &queueEntry{
+		ID:       fmt.Sprintf("%019d-%s", UTCNow().UnixNano(), newRandomID(8)),
+		Source:   sourcePath,
+		Target:   filepath.ToSlash(filepath.Join(urls.TargetAlias, urls.TargetContent.URL.Path)),
+		Metadata: metadata,
+		URLs:     urls,
+	}
+	entry.URLs.Error = nil
+
+	dataPath := filepath.Join(q.dir, entry.ID+queueDataSuffix)
+	data, e := os.OpenFile(dataPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	entry.Size, e = io.Copy(data, io.LimitReader(reader, q.maxSize+1))
+	if e == nil {
+		// The source may have changed since it was listed, the
+		// reservation is adjusted to the size spooled.
+		q.mutex.Lock()
+		if q.used-reserved+entry.Size > q.maxSize {
+			e = errQueueFull(q.maxSize).ToGoError()
+		} else {
+			q.used += entry.Size - reserved
+			reserved = entry.Size
+		}
+		q.mutex.Unlock()
+	}
+	if e == nil {
+		e = data.Close()
+	} else {
+		data.Close()
+	}
+	if e != nil {
+		os.Remove(dataPath)
+		return nil, probe.NewError(e).Trace(sourcePath)
+	}
+
+	if err = q.save(entry); err != nil {
+		os.Remove(dataPath)
+		return nil, err.Trace(sourcePath)
+	}
+	return entry, nil
+}
+
+// save atomically writes the record of a queued transfer.
+func (q *offlineQueue) save(entry *queueEntry) *probe.Error {
+	record, e := json.Marshal(entry)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	entryPath := filepath.Join(q.dir, entry.ID+queueEntrySuffix)
+	if e = ioutil.WriteFile(entryPath+".tmp", record, 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	return probe.NewError(os.Rename(entryPath+".tmp", entryPath))
+}
+
+// listQueueEntries returns the queued transfers, oldest first.
+func listQueueEntries(dir string) ([]*queueEntry, *probe.Error) {
+	files, e := ioutil.ReadDir(dir)
+	if os.IsNotExist(e) {
+		return nil, nil
+	}
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	var entries []*queueEntry
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), queueEntrySuffix) {
+			continue
+		}
+		record, e := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		entry := &queueEntry{}
+		if e = json.Unmarshal(record, entry); e != nil {
+			return nil, probe.NewError(e).Trace(file.Name())
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+// replayURLs returns the transfer of a queued entry, reading from the
+// spooled data instead of the original source.
+func replayURLs(dir string, entry *queueEntry) URLs {
+	urls := entry.URLs
+	source := *urls.SourceContent
+	source.URL = *newClientURL(filepath.Join(dir, entry.ID+queueDataSuffix))
+	source.Size = entry.Size
+	source.VersionID = ""
+	source.Metadata = nil
+	source.UserMetadata = nil
+	urls.SourceAlias = ""
+	urls.SourceContent = &source
+
+	target := *urls.TargetContent
+	target.Metadata = make(map[string]string, len(entry.Metadata)+len(urls.TargetContent.Metadata))
+	for k, v := range entry.Metadata {
+		target.Metadata[k] = v
+	}
+	for k, v := range urls.TargetContent.Metadata {
+		target.Metadata[k] = v
+	}
+	urls.TargetContent = &target
+	return urls
+}
+
+// removeQueueEntry removes a queued transfer and its data.
+func removeQueueEntry(dir string, entry *queueEntry) *probe.Error {
+	if e := os.Remove(filepath.Join(dir, entry.ID+queueEntrySuffix)); e != nil {
+		return probe.NewError(e)
+	}
+	if e := os.Remove(filepath.Join(dir, entry.ID+queueDataSuffix)); e != nil && !os.IsNotExist(e) {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// isTargetUnreachable returns true if a transfer failed because its
+// remote target could not be reached.
+func isTargetUnreachable(urls URLs) bool {
+	if urls.Error == nil || urls.TargetContent == nil || urls.TargetContent.URL.Type != objectStorage {
+		return false
+	}
+	return isHostFailure(urls.Error.ToGoError())
+}
+
+// queuedMessage container for transfers queued until `mc flush`.
+type queuedMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+}
+
+// String colorized queued message.
+func (q queuedMessage) String() string {
+	return console.Colorize("Queued", fmt.Sprintf("`%s` -> `%s` queued until `mc flush`, the target is unreachable.", q.Source, q.Target))
+}
+
+// JSON jsonified queued message.
+func (q queuedMessage) JSON() string {
+	q.Status = "queued"
+	queuedMessageBytes, e := jsoncolor.MarshalIndent(q, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(queuedMessageBytes)
+}
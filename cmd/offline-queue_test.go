@@ -0,0 +1,107 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestOfflineQueue(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newMcConfig(), nil }
+
+	srcDir, queueDir := t.TempDir(), t.TempDir()
+	queue := &offlineQueue{dir: queueDir, maxSize: 10}
+
+	newURLs := func(name, data string) URLs {
+		path := filepath.Join(srcDir, name)
+		if e := ioutil.WriteFile(path, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		return URLs{
+			SourceContent: &ClientContent{URL: *newClientURL(path), Size: int64(len(data))},
+			TargetAlias:   "play",
+			TargetContent: &ClientContent{URL: *newClientURL("https://play.min.io/bucket/" + name)},
+		}
+	}
+
+	entry, err := queue.add(context.Background(), newURLs("a", "hello"), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Size != 5 || queue.used != 5 {
+		t.Fatalf("expected 5 bytes to be queued, got %d", entry.Size)
+	}
+	if _, err = queue.add(context.Background(), newURLs("b", "too large"), nil, false); err == nil {
+		t.Fatal("expected the queue to be full")
+	}
+
+	// Reservations follow the size spooled, and are given back on failure.
+	grown := newURLs("c", "grown!")
+	grown.SourceContent.Size = 2
+	if _, err = queue.add(context.Background(), grown, nil, false); err == nil {
+		t.Fatal("expected the queue to be full")
+	}
+	if queue.used != 5 {
+		t.Fatalf("expected the reservation to be given back, %d bytes used", queue.used)
+	}
+	shrunk := newURLs("d", "hi")
+	shrunk.SourceContent.Size = 4
+	if entry, err = queue.add(context.Background(), shrunk, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Size != 2 || queue.used != 7 {
+		t.Fatalf("expected 7 bytes to be queued, got %d", queue.used)
+	}
+	if err = removeQueueEntry(queueDir, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := listQueueEntries(queueDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Target != "play/bucket/a" {
+		t.Fatalf("unexpected queued entries %v", entries)
+	}
+
+	urls := replayURLs(queueDir, entries[0])
+	data, e := ioutil.ReadFile(urls.SourceContent.URL.Path)
+	if e != nil || string(data) != "hello" {
+		t.Fatalf("expected replay to read the spooled data, got %q (%v)", data, e)
+	}
+	if urls.TargetContent.URL.String() != "https://play.min.io/bucket/a" {
+		t.Fatalf("unexpected replay target %s", urls.TargetContent.URL.String())
+	}
+
+	if err = removeQueueEntry(queueDir, entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := ioutil.ReadDir(queueDir); len(files) != 0 {
+		t.Fatalf("expected an empty queue, found %d files", len(files))
+	}
+	if _, e = os.Stat(filepath.Join(srcDir, "a")); e != nil {
+		t.Fatal(e)
+	}
+}
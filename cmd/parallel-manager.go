@@ -18,14 +18,19 @@
 package cmd
 
 import (
+	"errors"
 	"io/ioutil"
+	"net"
+	"net/http"
 	"runtime"
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
 	mem "github.com/shirou/gopsutil/v3/mem"
 )
 
@@ -35,6 +40,19 @@ const (
 
 	// Monitor tick to decide to add new workers
 	monitorPeriod = 4 * time.Second
+
+	// Number of monitor periods without throttling before
+	// parked workers are resumed again.
+	throttleRecoveryPeriods = 2
+
+	// Number of consecutive failures to a host before new
+	// work is paused for it.
+	breakerThreshold = 10
+
+	// Pause applied when the circuit breaker trips, doubled on
+	// every failed probe up to breakerMaxBackoff.
+	breakerMinBackoff = 2 * time.Second
+	breakerMaxBackoff = 2 * time.Minute
 )
 
 // Number of workers added per bandwidth monitoring.
@@ -49,6 +67,8 @@ type task struct {
 	barrier bool
 	// The total size of the information that we need to upload
 	uploadSize int64
+	// The remote host the task talks to, if any.
+	host string
 }
 
 // ParallelManager - helps manage parallel workers to run tasks
@@ -66,6 +86,28 @@ type ParallelManager struct {
 	// Current threads number
 	workersNum uint32
 
+	// Number of workers allowed to pick up tasks, workers
+	// above this number are parked while a host throttles.
+	activeWorkers uint32
+
+	// Per host request and throttling counters of the
+	// current monitor period.
+	hostStatsMu sync.Mutex
+	hostStats   map[string]*hostThrottleStats
+
+	// Circuit breaker state, protected by hostStatsMu. Consecutive
+	// failures are counted per host, once a host trips its breaker
+	// the workers holding a task for that host are parked until the
+	// pause is over, after which a single task probes the host. Tasks
+	// of other hosts are not affected.
+	hostFailures map[string]int
+	breakers     map[string]*hostBreaker
+	breakerOff   bool
+
+	// Parked workers wait on parkCond, protected by hostStatsMu,
+	// until the number of active workers or the breaker changes.
+	parkCond *sync.Cond
+
 	// Channel to receive tasks to run
 	queueCh chan task
 
@@ -73,6 +115,7 @@ type ParallelManager struct {
 	resultCh chan URLs
 
 	stopMonitorCh chan struct{}
+	monitorDoneCh chan struct{}
 
 	// The maximum memory to use
 	maxMem uint64
@@ -87,12 +130,19 @@ func (p *ParallelManager) addWorker() {
 	}
 
 	// Update number of threads
-	atomic.AddUint32(&p.workersNum, 1)
+	idx := atomic.AddUint32(&p.workersNum, 1) - 1
 
 	// Start a new worker
 	p.wg.Add(1)
 	go func() {
 		for {
+			// Stay parked while the pool is scaled down.
+			p.hostStatsMu.Lock()
+			for idx >= atomic.LoadUint32(&p.activeWorkers) {
+				p.parkCond.Wait()
+			}
+			p.hostStatsMu.Unlock()
+
 			// Wait for jobs
 			t, ok := <-p.queueCh
 			if !ok {
@@ -101,8 +151,19 @@ func (p *ParallelManager) addWorker() {
 				return
 			}
 
+			// Hold the task while its host recovers.
+			p.hostStatsMu.Lock()
+			wait, probe := p.breakerWait(t.host)
+			for wait {
+				p.parkCond.Wait()
+				wait, probe = p.breakerWait(t.host)
+			}
+			p.hostStatsMu.Unlock()
+
 			// Execute the task and send the result to channel.
-			p.resultCh <- t.fn()
+			urls := t.fn()
+			p.recordResult(t.host, urls, probe)
+			p.resultCh <- urls
 
 			if t.barrier {
 				p.barrierSync.Unlock()
@@ -124,11 +185,13 @@ func (p *ParallelManager) Read(b []byte) (n int, err error) {
 // transfer speed.
 func (p *ParallelManager) monitorProgress() {
 	go func() {
+		defer close(p.monitorDoneCh)
 		ticker := time.NewTicker(monitorPeriod)
 		defer ticker.Stop()
 
 		var prevSentBytes, maxBandwidth int64
-		var retry int
+		var retry, cleanPeriods int
+		growing := true
 
 		for {
 			select {
@@ -141,13 +204,18 @@ func (p *ParallelManager) monitorProgress() {
 				bandwidth := sentBytes - prevSentBytes
 				prevSentBytes = sentBytes
 
+				if p.adjustForThrottling(&cleanPeriods) || !growing {
+					continue
+				}
+
 				if bandwidth <= maxBandwidth {
 					retry++
 					// We still want to add more workers
 					// until we are sure that it is not
 					// useful to add more of them.
 					if retry > 2 {
-						return
+						growing = false
+						continue
 					}
 				} else {
 					retry = 0
@@ -162,9 +230,223 @@ func (p *ParallelManager) monitorProgress() {
 	}()
 }
 
-// Queue task in parallel
-func (p *ParallelManager) queueTask(fn func() URLs, uploadSize int64) {
-	p.doQueueTask(task{fn: fn, uploadSize: uploadSize})
+// hostThrottleStats counts requests to a host and how many
+// of them were throttled.
+type hostThrottleStats struct {
+	requests  int
+	throttled int
+}
+
+// isThrottleError returns true if the server asked us to slow
+// down or did not answer in time.
+func isThrottleError(e error) bool {
+	if e == nil {
+		return false
+	}
+	var errResp minio.ErrorResponse
+	if errors.As(e, &errResp) {
+		switch errResp.Code {
+		case "SlowDown", "SlowDownRead", "SlowDownWrite", "RequestTimeout", "XMinioServerNotInitialized":
+			return true
+		}
+		return errResp.StatusCode == http.StatusServiceUnavailable
+	}
+	var netErr net.Error
+	return errors.As(e, &netErr) && netErr.Timeout()
+}
+
+// isHostFailure returns true for errors showing that the host
+// itself is unhealthy rather than a single request failing.
+func isHostFailure(e error) bool {
+	if e == nil {
+		return false
+	}
+	if isThrottleError(e) || errors.Is(e, syscall.ECONNREFUSED) {
+		return true
+	}
+	var errResp minio.ErrorResponse
+	if errors.As(e, &errResp) {
+		return errResp.StatusCode >= http.StatusInternalServerError
+	}
+	var opErr *net.OpError
+	return errors.As(e, &opErr) && opErr.Op == "dial"
+}
+
+// hostBreaker is the circuit breaker state of a failing host.
+type hostBreaker struct {
+	// No task is started before until.
+	until time.Time
+	// Current pause, doubled on every failed probe.
+	backoff time.Duration
+	// True while a single task probes the host.
+	probing bool
+}
+
+// taskHost returns the remote host a task talks to.
+func taskHost(urls URLs) string {
+	if urls.TargetContent != nil && urls.TargetContent.URL.Type == objectStorage {
+		return urls.TargetContent.URL.Host
+	}
+	if urls.SourceContent != nil && urls.SourceContent.URL.Type == objectStorage {
+		return urls.SourceContent.URL.Host
+	}
+	return ""
+}
+
+// recordResult accounts a finished task against the remote host it was
+// queued for, probe is true if the task was let through to probe a
+// tripped host.
+func (p *ParallelManager) recordResult(host string, urls URLs, probe bool) {
+	p.hostStatsMu.Lock()
+	defer p.hostStatsMu.Unlock()
+
+	if b, ok := p.breakers[host]; ok && probe {
+		// The probe is over whatever its result, the breaker is
+		// closed or tripped again below.
+		b.probing = false
+		p.parkCond.Broadcast()
+	}
+	if host == "" {
+		return
+	}
+
+	stats, ok := p.hostStats[host]
+	if !ok {
+		stats = &hostThrottleStats{}
+		p.hostStats[host] = stats
+	}
+	stats.requests++
+	if urls.Error != nil && isThrottleError(urls.Error.ToGoError()) {
+		stats.throttled++
+	}
+
+	if urls.Error == nil || !isHostFailure(urls.Error.ToGoError()) {
+		delete(p.hostFailures, host)
+		if _, ok := p.breakers[host]; ok {
+			// The host answers again, close its breaker.
+			delete(p.breakers, host)
+			p.parkCond.Broadcast()
+		}
+		return
+	}
+
+	if _, ok := p.breakers[host]; ok {
+		// Failures of tasks started before the breaker tripped
+		// are not counted, only a failed probe re-trips it.
+		if probe {
+			p.tripBreaker(host)
+		}
+		return
+	}
+	p.hostFailures[host]++
+	if p.hostFailures[host] >= breakerThreshold {
+		p.tripBreaker(host)
+	}
+}
+
+// tripBreaker pauses new tasks for host, doubling the pause on every
+// consecutive trip. Must be called with hostStatsMu held.
+func (p *ParallelManager) tripBreaker(host string) {
+	if p.breakerOff {
+		return
+	}
+	b, ok := p.breakers[host]
+	if !ok {
+		b = &hostBreaker{}
+		p.breakers[host] = b
+	}
+	b.backoff *= 2
+	if b.backoff < breakerMinBackoff {
+		b.backoff = breakerMinBackoff
+	}
+	if b.backoff > breakerMaxBackoff {
+		b.backoff = breakerMaxBackoff
+	}
+	b.until = time.Now().Add(b.backoff)
+	b.probing = false
+	delete(p.hostFailures, host)
+	// Wake up the parked workers once the pause is over.
+	time.AfterFunc(b.backoff, p.wakeParkedWorkers)
+
+	if !globalQuiet && !globalJSON {
+		console.Eraseline()
+		console.Infof("`%s` keeps failing, pausing new transfers to it for %s.\n", host, b.backoff)
+	}
+}
+
+// wakeParkedWorkers lets the parked workers check again whether they
+// may pick up tasks.
+func (p *ParallelManager) wakeParkedWorkers() {
+	p.hostStatsMu.Lock()
+	p.parkCond.Broadcast()
+	p.hostStatsMu.Unlock()
+}
+
+// breakerWait returns true if a task for host must wait because the
+// breaker of the host is open. Once the pause is over a single task is
+// let through to probe the host, probe is true for that task. Must be
+// called with hostStatsMu held.
+func (p *ParallelManager) breakerWait(host string) (wait, probe bool) {
+	b, ok := p.breakers[host]
+	if !ok || p.breakerOff {
+		return false, false
+	}
+	if b.probing || time.Now().Before(b.until) {
+		return true, false
+	}
+	b.probing = true
+	return false, true
+}
+
+// adjustForThrottling halves the number of active workers when more
+// than a tenth of the requests to a host were throttled during the last
+// monitor period, and gradually resumes parked workers once the hosts
+// recover. Returns true while the pool is scaled down.
+func (p *ParallelManager) adjustForThrottling(cleanPeriods *int) bool {
+	p.hostStatsMu.Lock()
+	throttled := false
+	for host, stats := range p.hostStats {
+		if stats.throttled > 0 && stats.throttled*10 >= stats.requests {
+			throttled = true
+		}
+		delete(p.hostStats, host)
+	}
+	p.hostStatsMu.Unlock()
+
+	workers := atomic.LoadUint32(&p.workersNum)
+	active := atomic.LoadUint32(&p.activeWorkers)
+	if active > workers {
+		active = workers
+	}
+
+	if throttled {
+		*cleanPeriods = 0
+		if active > 1 {
+			atomic.StoreUint32(&p.activeWorkers, active/2)
+		}
+		return true
+	}
+
+	if active >= workers {
+		return false
+	}
+
+	*cleanPeriods++
+	if *cleanPeriods >= throttleRecoveryPeriods {
+		*cleanPeriods = 0
+		active += uint32(defaultWorkerFactor)
+		if active >= workers {
+			active = maxParallelWorkers
+		}
+		atomic.StoreUint32(&p.activeWorkers, active)
+		p.wakeParkedWorkers()
+	}
+	return active < workers
+}
+
+// Queue task in parallel, host is the remote host the task talks to.
+func (p *ParallelManager) queueTask(fn func() URLs, uploadSize int64, host string) {
+	p.doQueueTask(task{fn: fn, uploadSize: uploadSize, host: host})
 }
 
 // Queue task but ensures that no tasks is running at parallel,
@@ -217,9 +499,18 @@ func (p *ParallelManager) doQueueTask(t task) {
 
 // Wait for all workers to finish tasks before shutting down Parallel
 func (p *ParallelManager) stopAndWait() {
+	// Stop the monitor first, it must not park workers again.
+	close(p.stopMonitorCh)
+	<-p.monitorDoneCh
+
+	// Wake up parked workers so that they can quit.
+	p.hostStatsMu.Lock()
+	atomic.StoreUint32(&p.activeWorkers, maxParallelWorkers)
+	p.breakerOff = true
+	p.parkCond.Broadcast()
+	p.hostStatsMu.Unlock()
 	close(p.queueCh)
 	p.wg.Wait()
-	close(p.stopMonitorCh)
 }
 
 const cgroupLimitFile = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
@@ -266,11 +557,17 @@ func newParallelManager(resultCh chan URLs) *ParallelManager {
 	p := &ParallelManager{
 		wg:            &sync.WaitGroup{},
 		workersNum:    0,
+		activeWorkers: maxParallelWorkers,
+		hostStats:     make(map[string]*hostThrottleStats),
+		hostFailures:  make(map[string]int),
+		breakers:      make(map[string]*hostBreaker),
 		stopMonitorCh: make(chan struct{}),
+		monitorDoneCh: make(chan struct{}),
 		queueCh:       make(chan task),
 		resultCh:      resultCh,
 		maxMem:        availableMemory(),
 	}
+	p.parkCond = sync.NewCond(&p.hostStatsMu)
 
 	// Start with runtime.NumCPU().
 	for i := 0; i < runtime.NumCPU(); i++ {
@@ -0,0 +1,196 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+func TestIsThrottleError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		throttle bool
+	}{
+		{nil, false},
+		{errors.New("disk full"), false},
+		{minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}, true},
+		{minio.ErrorResponse{Code: "ServiceUnavailable", StatusCode: http.StatusServiceUnavailable}, true},
+		{minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}, false},
+		{os.ErrDeadlineExceeded, true},
+		{context.Canceled, false},
+	}
+
+	for i, testCase := range testCases {
+		if throttle := isThrottleError(testCase.err); throttle != testCase.throttle {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.throttle, throttle)
+		}
+	}
+}
+
+func TestAdjustForThrottling(t *testing.T) {
+	p := &ParallelManager{
+		workersNum:    8,
+		activeWorkers: maxParallelWorkers,
+		hostStats:     make(map[string]*hostThrottleStats),
+		hostFailures:  make(map[string]int),
+	}
+	p.parkCond = sync.NewCond(&p.hostStatsMu)
+	throttled := URLs{
+		TargetContent: &ClientContent{URL: ClientURL{Type: objectStorage, Host: "play.min.io"}},
+		Error:         probe.NewError(minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}),
+	}
+
+	var cleanPeriods int
+	p.recordResult("play.min.io", throttled, false)
+	if !p.adjustForThrottling(&cleanPeriods) || p.activeWorkers != 4 {
+		t.Fatalf("expected pool to scale down to 4 workers, got %d", p.activeWorkers)
+	}
+
+	// Recover after enough periods without throttling.
+	for i := 0; i < throttleRecoveryPeriods*8 && p.activeWorkers < p.workersNum; i++ {
+		p.adjustForThrottling(&cleanPeriods)
+	}
+	if p.adjustForThrottling(&cleanPeriods) {
+		t.Fatalf("expected pool to recover, %d workers are active", p.activeWorkers)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	p := &ParallelManager{
+		hostStats:    make(map[string]*hostThrottleStats),
+		hostFailures: make(map[string]int),
+		breakers:     make(map[string]*hostBreaker),
+	}
+	p.parkCond = sync.NewCond(&p.hostStatsMu)
+	const host, otherHost = "play.min.io", "s3.amazonaws.com"
+	target := &ClientContent{URL: ClientURL{Type: objectStorage, Host: host}}
+	failed := URLs{
+		TargetContent: target,
+		Error:         probe.NewError(&os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}),
+	}
+
+	for i := 0; i < breakerThreshold-1; i++ {
+		p.recordResult(host, failed, false)
+	}
+	if wait, _ := p.breakerWait(host); wait {
+		t.Fatal("expected breaker to stay closed below the threshold")
+	}
+	p.recordResult(host, failed, false)
+	if wait, _ := p.breakerWait(host); !wait || p.breakers[host].backoff != breakerMinBackoff {
+		t.Fatalf("expected breaker to trip with %s backoff", breakerMinBackoff)
+	}
+	// Tasks of other hosts go on.
+	if wait, _ := p.breakerWait(otherHost); wait {
+		t.Fatal("expected other hosts not to be paused")
+	}
+
+	// Once the pause is over a single task probes the host.
+	p.breakers[host].until = time.Now()
+	if wait, probe := p.breakerWait(host); wait || !probe {
+		t.Fatal("expected the first task to probe the host")
+	}
+	if wait, _ := p.breakerWait(host); !wait {
+		t.Fatal("expected a single probing task")
+	}
+	// Failures of tasks started before the trip do not count.
+	p.recordResult(host, failed, false)
+	if p.breakers[host].backoff != breakerMinBackoff {
+		t.Fatal("expected only the probe to re-trip the breaker")
+	}
+	p.recordResult(host, failed, true)
+	if wait, _ := p.breakerWait(host); !wait || p.breakers[host].backoff != 2*breakerMinBackoff {
+		t.Fatal("expected failed probe to double the backoff")
+	}
+
+	p.breakers[host].until = time.Now()
+	_, probe := p.breakerWait(host)
+	p.recordResult(host, URLs{TargetContent: target}, probe)
+	if wait, _ := p.breakerWait(host); wait || len(p.breakers) != 0 {
+		t.Fatal("expected successful probe to close the breaker")
+	}
+}
+
+func TestCircuitBreakerProbeWithoutHost(t *testing.T) {
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	p := &ParallelManager{
+		hostStats:    make(map[string]*hostThrottleStats),
+		hostFailures: make(map[string]int),
+		breakers:     make(map[string]*hostBreaker),
+	}
+	p.parkCond = sync.NewCond(&p.hostStatsMu)
+	const host = "play.min.io"
+	hostErr := probe.NewError(&os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED})
+	p.hostStatsMu.Lock()
+	p.tripBreaker(host)
+	p.hostStatsMu.Unlock()
+
+	// Results of probes failing before the URLs of the task are known
+	// have no host, they are accounted against the host of the task.
+	p.breakers[host].until = time.Now()
+	_, isProbe := p.breakerWait(host)
+	p.recordResult(host, URLs{Error: hostErr}, isProbe)
+	if b := p.breakers[host]; b == nil || b.probing || b.backoff != 2*breakerMinBackoff {
+		t.Fatal("expected failed probe without host to re-trip the breaker")
+	}
+
+	p.breakers[host].until = time.Now()
+	_, isProbe = p.breakerWait(host)
+	p.recordResult(host, URLs{Error: probe.NewError(errors.New("invalid argument"))}, isProbe)
+	if wait, _ := p.breakerWait(host); wait || len(p.breakers) != 0 {
+		t.Fatal("expected probe without host to close the breaker")
+	}
+}
+
+func TestParkedWorkers(t *testing.T) {
+	resultCh := make(chan URLs, 1)
+	p := newParallelManager(resultCh)
+	atomic.StoreUint32(&p.activeWorkers, 0)
+
+	go p.queueTask(func() URLs { return URLs{} }, 0, "")
+	select {
+	case <-resultCh:
+		t.Fatal("expected parked workers not to run the task")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Parked workers are woken up without polling.
+	atomic.StoreUint32(&p.activeWorkers, maxParallelWorkers)
+	p.wakeParkedWorkers()
+	select {
+	case <-resultCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected a woken up worker to run the task")
+	}
+	p.stopAndWait()
+}
@@ -156,6 +156,16 @@ func (p *progressBar) Read(buf []byte) (n int, err error) {
 	return p.ProgressBar.Read(buf)
 }
 
+// SetEstimating marks the total as an estimate, while totals are still
+// being computed the overall percentage and ETA may move back.
+func (p *progressBar) SetEstimating(estimating bool) {
+	if estimating {
+		p.ProgressBar.Postfix(" (estimating)")
+	} else {
+		p.ProgressBar.Postfix("")
+	}
+}
+
 func (p *progressBar) SetTotal(total int64) {
 	p.ProgressBar.Total = total
 }
@@ -73,7 +73,7 @@ var (
 		},
 		cli.BoolFlag{
 			Name:  "stdin",
-			Usage: "read object names from STDIN",
+			Usage: "read object names from STDIN, relative to TARGET if specified",
 		},
 		cli.StringFlag{
 			Name:  "older-than",
@@ -133,29 +133,35 @@ EXAMPLES:
   06. Remove all objects read from STDIN.
       {{.Prompt}} {{.HelpName}} --force --stdin
 
-  07. Remove all objects recursively from Amazon S3 cloud storage.
+  07. Remove the keys listed in 'keys.txt' from the bucket 'jazz-songs', in batches.
+      {{.Prompt}} {{.HelpName}} --force --stdin s3/jazz-songs < keys.txt
+
+  08. Remove all objects recursively from Amazon S3 cloud storage.
       {{.Prompt}} {{.HelpName}} --recursive --force --dangerous s3
 
-  08. Remove all objects older than '90' days recursively under all buckets.
+  09. Remove all objects older than '90' days recursively under all buckets.
       {{.Prompt}} {{.HelpName}} --recursive --dangerous --force --older-than 90d s3
 
-  09. Drop all incomplete uploads on the bucket 'jazz-songs'.
+  10. Drop all incomplete uploads on the bucket 'jazz-songs'.
       {{.Prompt}} {{.HelpName}} --incomplete --recursive --force s3/jazz-songs/
 
-  10. Remove an encrypted object from Amazon S3 cloud storage.
+  11. Remove an encrypted object from Amazon S3 cloud storage.
       {{.Prompt}} {{.HelpName}} --encrypt-key "s3/sql-backups/=32byteslongsecretkeymustbegiven1" s3/sql-backups/1999/old-backup.tgz
 
-  11. Bypass object retention in governance mode and delete the object.
+  12. Bypass object retention in governance mode and delete the object.
       {{.Prompt}} {{.HelpName}} --bypass s3/pop-songs/
 
-  12. Remove a particular version ID.
+  13. Remove a particular version ID.
       {{.Prompt}} {{.HelpName}} s3/docs/money.xls --version-id "f20f3792-4bd4-4288-8d3c-b9d05b3b62f6"
 
-  13. Remove all object versions older than one year.
+  14. Remove all object versions older than one year.
       {{.Prompt}} {{.HelpName}} s3/docs/ --recursive --versions --rewind 365d
 
-  14. Remove object(s) versions that are non-current (with top-level delete marker).
+  15. Remove object(s) versions that are non-current (with top-level delete marker).
       {{.Prompt}} {{.HelpName}} s3/docs/ --recursive --versions --non-current
+
+  16. Remove all objects under a prefix using the '...' suffix, same as --recursive.
+      {{.Prompt}} {{.HelpName}} --force s3/jazz-songs/louis/...
 `,
 }
 
@@ -198,6 +204,7 @@ func (r rmMessage) JSON() string {
 func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	// Set command flags from context.
 	isForce := cliCtx.Bool("force")
+	args := cliCtx.Args()
 	isRecursive := cliCtx.Bool("recursive")
 	isStdin := cliCtx.Bool("stdin")
 	isDangerous := cliCtx.Bool("dangerous")
@@ -217,7 +224,22 @@ func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string
 			"You cannot specify --non-current without --versions, please use --non-current --versions.")
 	}
 
-	for _, url := range cliCtx.Args() {
+	if isStdin && cliCtx.Args().Present() {
+		// With --stdin the only argument is the target the keys are relative to.
+		if len(cliCtx.Args()) != 1 {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...),
+				"You can only specify a single target with --stdin.")
+		}
+		if isRecursive || isVersions || versionID != "" || rewind != "" {
+			fatalIf(errDummy().Trace(),
+				"You cannot specify --stdin with any of --versions, --version-id, --rewind and --recursive flags when passing a target.")
+		}
+	}
+
+	for _, url := range args {
+		if isStdin {
+			break
+		}
 		// clean path for aliases like s3/.
 		// Note: UNC path using / works properly in go 1.9.2 even though it breaks the UNC specification.
 		url = filepath.ToSlash(filepath.Clean(url))
@@ -345,6 +367,88 @@ func removeSingle(url, versionID string, isIncomplete, isFake, isForce, isBypass
 	return nil
 }
 
+// removeStdinKeys removes the newline delimited keys read from STDIN
+// under the target, e.g. the output of `mc find` or an inventory report.
+// Keys are batched through multi-object delete, a failure to remove a key
+// is reported and does not stop the removal of the remaining keys.
+func removeStdinKeys(url string, isIncomplete, isFake, isBypass bool) error {
+	ctx, cancelRemove := context.WithCancel(globalContext)
+	defer cancelRemove()
+
+	targetAlias, targetURL, _ := mustExpandAlias(url)
+	clnt, pErr := newClientFromAlias(targetAlias, targetURL)
+	if pErr != nil {
+		errorIf(pErr.Trace(url), "Invalid argument `"+url+"`.")
+		return exitStatus(globalErrorExitStatus) // End of journey.
+	}
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			key := strings.TrimSuffix(scanner.Text(), "\r")
+			if key == "" {
+				continue
+			}
+			contentURL := *newClientURL(urlJoinPath(targetURL, key))
+			if isFake {
+				result := fakeRemoveResult(clnt, contentURL)
+				printMsg(rmMessage{Key: path.Join(targetAlias, result.BucketName, result.ObjectName)})
+				continue
+			}
+			select {
+			case contentCh <- &ClientContent{URL: contentURL}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if e := scanner.Err(); e != nil {
+			errorIf(probe.NewError(e), "Unable to read object names from STDIN.")
+		}
+	}()
+
+	if isFake {
+		for range contentCh {
+		}
+		return nil
+	}
+
+	var rerr error
+	isRemoveBucket := false
+	for result := range clnt.Remove(ctx, isIncomplete, isRemoveBucket, isBypass, contentCh) {
+		key := path.Join(targetAlias, result.BucketName, result.ObjectName)
+		if result.Err != nil {
+			errorIf(result.Err.Trace(key), "Failed to remove `"+key+"`.")
+			rerr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		versionID := result.ObjectVersionID
+		if versionID == "" {
+			versionID = result.DeleteMarkerVersionID
+		}
+		printMsg(rmMessage{
+			Key:          key,
+			VersionID:    versionID,
+			DeleteMarker: result.DeleteMarker,
+		})
+	}
+	return rerr
+}
+
+// fakeRemoveResult returns the result a removal of the URL would report,
+// --fake prints the same keys as a real removal.
+func fakeRemoveResult(clnt Client, contentURL ClientURL) RemoveResult {
+	var result RemoveResult
+	switch c := clnt.(type) {
+	case *S3Client:
+		result.BucketName, result.ObjectName = c.splitPath(contentURL.Path)
+	default:
+		_, result.ObjectName = url2BucketAndObject(&contentURL, false)
+	}
+	return result
+}
+
 // listAndRemove uses listing before removal, it can list recursively or not, with versions or not.
 //   Use cases:
 //      * Remove objects recursively
@@ -581,6 +685,9 @@ func listAndRemove(url string, timeRef time.Time, withVersions, nonCurrentVersio
 
 // main for rm command.
 func mainRm(cliCtx *cli.Context) error {
+	trimRecursiveArgs(cliCtx)
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelRm := context.WithCancel(globalContext)
 	defer cancelRm()
 
@@ -593,6 +700,7 @@ func mainRm(cliCtx *cli.Context) error {
 
 	// rm specific flags.
 	isIncomplete := cliCtx.Bool("incomplete")
+	args := cliCtx.Args()
 	isRecursive := cliCtx.Bool("recursive")
 	isFake := cliCtx.Bool("fake")
 	isStdin := cliCtx.Bool("stdin")
@@ -614,8 +722,13 @@ func mainRm(cliCtx *cli.Context) error {
 
 	var rerr error
 	var e error
+
+	if isStdin && cliCtx.Args().Present() {
+		return removeStdinKeys(args[0], isIncomplete, isFake, isBypass)
+	}
+
 	// Support multiple targets.
-	for _, url := range cliCtx.Args() {
+	for _, url := range args {
 		if isRecursive || withVersions {
 			e = listAndRemove(url, rewind, withVersions, withNoncurrentVersion, isForce, isRecursive, isIncomplete, isFake, isBypass, olderThan, newerThan, encKeyDB)
 		} else {
@@ -0,0 +1,74 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test that --fake reports the same keys as a real removal.
+func TestFakeRemoveResult(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-rm-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	name := filepath.Join(root, "dir", "object")
+	if e = os.MkdirAll(filepath.Dir(name), 0o755); e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(name, []byte("hello"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	fsClnt, err := fsNew(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentURL := *newClientURL(name)
+	fake := fakeRemoveResult(fsClnt, contentURL)
+
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: contentURL}
+	close(contentCh)
+	for result := range fsClnt.Remove(context.Background(), false, false, false, contentCh) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+		if result.BucketName != fake.BucketName || result.ObjectName != fake.ObjectName {
+			t.Fatalf("expected %s/%s, got %s/%s", result.BucketName, result.ObjectName, fake.BucketName, fake.ObjectName)
+		}
+	}
+
+	conf := new(Config)
+	conf.HostURL = "http://localhost:9000/bucket"
+	conf.Signature = "S3v4"
+	s3Clnt, err := S3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake = fakeRemoveResult(s3Clnt, *newClientURL("http://localhost:9000/bucket/dir/object"))
+	if fake.BucketName != "bucket" || fake.ObjectName != "dir/object" {
+		t.Fatalf("expected bucket/dir/object, got %s/%s", fake.BucketName, fake.ObjectName)
+	}
+}
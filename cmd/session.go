@@ -18,13 +18,20 @@
 package cmd
 
 import (
+	"bufio"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/fatih/color"
+	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 // migrateSession migrates all previous migration to latest.
@@ -132,3 +139,91 @@ func getHash(prefix string, args []string) string {
 
 	return prefix + "-" + hex.EncodeToString(hasher.Sum(nil))
 }
+
+// sessionHashIgnoredFlags are flags which do not change what a command
+// transfers, so they are left out of the session hash.
+var sessionHashIgnoredFlags = map[string]bool{
+	"continue":      true,
+	"quiet":         true,
+	"no-color":      true,
+	"json":          true,
+	"debug":         true,
+	"insecure":      true,
+	"proxy":         true,
+	"resolve":       true,
+	"dns-resolver":  true,
+	"dns-cache-ttl": true,
+}
+
+// getSessionHash - returns a session ID for a normalized command, the
+// same command with its flags given in any order maps to the same ID.
+func getSessionHash(prefix string, args []string, flags map[string]string) string {
+	var names []string
+	for name := range flags {
+		if !sessionHashIgnoredFlags[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hashArgs := make([]string, 0, len(args)+len(names))
+	for _, arg := range args {
+		hashArgs = append(hashArgs, "arg="+arg+"\x00")
+	}
+	for _, name := range names {
+		hashArgs = append(hashArgs, "flag="+name+"="+flags[name]+"\x00")
+	}
+	return getHash(prefix, hashArgs)
+}
+
+// getCommandSessionHash - returns the session ID of the command line.
+func getCommandSessionHash(cliCtx *cli.Context) string {
+	flags := make(map[string]string)
+	for _, name := range cliCtx.FlagNames() {
+		if !cliCtx.IsSet(name) {
+			continue
+		}
+		flags[name] = fmt.Sprint(cliCtx.Generic(name))
+	}
+	return getSessionHash(cliCtx.Command.Name, cliCtx.Args(), flags)
+}
+
+// getCommandSessionID - returns the session ID of the command line,
+// a session saved under one of the legacy IDs of the same command is
+// still found so that it can be resumed.
+func getCommandSessionID(cliCtx *cli.Context, legacySIDs ...string) string {
+	sid := getCommandSessionHash(cliCtx)
+	if isSessionExists(sid) {
+		return sid
+	}
+	for _, legacySID := range legacySIDs {
+		if isSessionExists(legacySID) {
+			return legacySID
+		}
+	}
+	return sid
+}
+
+// promptResumeSession - asks on an interactive terminal whether a
+// previous session of the same command should be resumed, the prompt
+// goes to stderr to keep the command output clean.
+func promptResumeSession(sid string) bool {
+	if globalQuiet || globalJSON {
+		return false
+	}
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) || !terminal.IsTerminal(int(os.Stderr.Fd())) {
+		return false
+	}
+	if !isSessionExists(sid) {
+		return false
+	}
+	console.SetColor("SessionPrompt", color.New(color.FgYellow))
+	fmt.Fprint(os.Stderr, console.Colorize("SessionPrompt",
+		fmt.Sprintf("An interrupted session `%s` of this command exists, resume it? [y/N]: ", sid)))
+	answer, e := bufio.NewReader(os.Stdin).ReadString('\n')
+	if e != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
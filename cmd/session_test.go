@@ -18,9 +18,12 @@
 package cmd
 
 import (
+	"flag"
 	"os"
 	"regexp"
+	"strings"
 
+	"github.com/minio/cli"
 	. "gopkg.in/check.v1"
 )
 
@@ -59,3 +62,46 @@ func (s *TestSuite) TestSession(c *C) {
 	_, e = os.Stat(session.DataFP.Name())
 	c.Assert(e, NotNil)
 }
+
+func (s *TestSuite) TestSessionHash(c *C) {
+	args := []string{"dir/", "myminio/mybucket"}
+	sid := getSessionHash("cp", args, map[string]string{"recursive": "true", "storage-class": "STANDARD"})
+	c.Assert(strings.HasPrefix(sid, "cp-"), Equals, true)
+
+	// Output only flags and '--continue' do not change the session.
+	sameSid := getSessionHash("cp", []string{"dir/", "myminio/mybucket"}, map[string]string{
+		"storage-class": "STANDARD",
+		"continue":      "true",
+		"quiet":         "true",
+		"recursive":     "true",
+	})
+	c.Assert(sameSid, Equals, sid)
+
+	otherSid := getSessionHash("cp", args, map[string]string{"recursive": "true", "storage-class": "GLACIER"})
+	c.Assert(otherSid, Not(Equals), sid)
+
+	// Arguments are not concatenated ambiguously.
+	c.Assert(getSessionHash("cp", []string{"ab", "c"}, nil), Not(Equals), getSessionHash("cp", []string{"a", "bc"}, nil))
+}
+
+func (s *TestSuite) TestCommandSessionIDLegacy(c *C) {
+	err := createSessionDir()
+	c.Assert(err, IsNil)
+
+	set := flag.NewFlagSet("cp", flag.ContinueOnError)
+	cli.BoolFlag{Name: "recursive, r"}.Apply(set)
+	c.Assert(set.Parse([]string{"--recursive", "dir/", "myminio/mybucket"}), IsNil)
+	ctx := cli.NewContext(nil, set, nil)
+	ctx.Command = cli.Command{Name: "cp"}
+
+	// Without any saved session the new ID is used.
+	legacySID := getHash("cp", []string{"--recursive", "dir/", "myminio/mybucket"})
+	sid := getCommandSessionID(ctx, legacySID)
+	c.Assert(sid, Equals, getCommandSessionHash(ctx))
+
+	// A session saved by an older release is still resumed.
+	session := newSessionV8(legacySID)
+	c.Assert(session.Close(), IsNil)
+	defer session.Delete()
+	c.Assert(getCommandSessionID(ctx, legacySID), Equals, legacySID)
+}
@@ -77,22 +77,8 @@ func checkShareDownloadSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB
 		cli.ShowCommandHelpAndExit(cliCtx, "download", 1) // last argument is exit code.
 	}
 
-	// Parse expiry.
-	expiry := shareDefaultExpiry
-	expireArg := cliCtx.String("expire")
-	if expireArg != "" {
-		var e error
-		expiry, e = time.ParseDuration(expireArg)
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
-	}
-
-	// Validate expiry.
-	if expiry.Seconds() < 1 {
-		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be lesser than 1 second.")
-	}
-	if expiry.Seconds() > 604800 {
-		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be larger than 7 days.")
-	}
+	// Parse and validate expiry.
+	parseShareExpiry(cliCtx.String("expire"))
 
 	isRecursive := cliCtx.Bool("recursive")
 
@@ -220,14 +206,10 @@ func mainShareDownload(cliCtx *cli.Context) error {
 	// Set command flags from context.
 	isRecursive := cliCtx.Bool("recursive")
 	versionID := cliCtx.String("version-id")
-	expiry := shareDefaultExpiry
-	if cliCtx.String("expire") != "" {
-		var e error
-		expiry, e = time.ParseDuration(cliCtx.String("expire"))
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+cliCtx.String("expire")+"`.")
-	}
+	expiry := parseShareExpiry(cliCtx.String("expire"))
 
 	for _, targetURL := range cliCtx.Args() {
+		warnShareCredentialExpiry(targetURL, expiry)
 		err := doShareDownloadURL(ctx, targetURL, versionID, isRecursive, expiry)
 		if err != nil {
 			switch err.ToGoError().(type) {
@@ -20,9 +20,12 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
 )
@@ -34,6 +37,10 @@ var shareUploadFlags = []cli.Flag{
 	},
 	shareFlagExpire,
 	shareFlagContentType,
+	cli.StringFlag{
+		Name:  "content-length-range",
+		Usage: "limit the size of uploads to MIN-MAX bytes, or to MAX bytes, e.g. 1KiB-64MiB",
+	},
 }
 
 // Share documents via URL.
@@ -65,6 +72,9 @@ EXAMPLES:
 
   4. Generate a curl command to allow upload access to any objects matching the key prefix 'backup/'. Command expires in 2 hours.
      {{.Prompt}} {{.HelpName}} --recursive --expire=2h s3/backup/2007-Mar-2/backup/
+
+  5. Generate a curl command to allow upload access of a single '.png' image of up to 10MiB. Command expires in 1 hour.
+     {{.Prompt}} {{.HelpName}} --expire=1h --content-type=image/png --content-length-range=10MiB s3/backup/2007-Mar-2/avatar.png
 `,
 }
 
@@ -77,24 +87,12 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
-	expireArg := ctx.String("expire")
 
-	// Parse expiry.
-	expiry := shareDefaultExpiry
-	if expireArg != "" {
-		var e error
-		expiry, e = time.ParseDuration(expireArg)
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
-	}
+	// Parse and validate expiry.
+	parseShareExpiry(ctx.String("expire"))
 
-	// Validate expiry.
-	if expiry.Seconds() < 1 {
-		fatalIf(errDummy().Trace(expiry.String()),
-			"Expiry cannot be lesser than 1 second.")
-	}
-	if expiry.Seconds() > 604800 {
-		fatalIf(errDummy().Trace(expiry.String()),
-			"Expiry cannot be larger than 7 days.")
+	if _, _, err := parseContentLengthRange(ctx.String("content-length-range")); err != nil {
+		fatalIf(err.Trace(ctx.String("content-length-range")), "Unable to parse content-length-range=`"+ctx.String("content-length-range")+"`.")
 	}
 
 	for _, targetURL := range ctx.Args() {
@@ -106,27 +104,78 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 	}
 }
 
-// makeCurlCmd constructs curl command-line.
+// parseContentLengthRange parses the --content-length-range argument
+// as MIN-MAX or MAX with human readable sizes, no limit is set if
+// the argument is empty.
+func parseContentLengthRange(arg string) (minSize, maxSize int64, err *probe.Error) {
+	if arg == "" {
+		return 0, 0, nil
+	}
+	minArg, maxArg := "0", arg
+	if i := strings.Index(arg, "-"); i >= 0 {
+		minArg, maxArg = arg[:i], arg[i+1:]
+	}
+	minBytes, e := humanize.ParseBytes(strings.TrimSpace(minArg))
+	if e != nil {
+		return 0, 0, probe.NewError(e)
+	}
+	maxBytes, e := humanize.ParseBytes(strings.TrimSpace(maxArg))
+	if e != nil {
+		return 0, 0, probe.NewError(e)
+	}
+	if maxBytes == 0 || minBytes > maxBytes || maxBytes > math.MaxInt64 {
+		return 0, 0, errInvalidArgument().Trace(arg)
+	}
+	return int64(minBytes), int64(maxBytes), nil
+}
+
+// shellQuote quotes the argument for a POSIX shell.
+func shellQuote(arg string) string {
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+// makeCurlCmd constructs curl command-line, the form fields are sorted
+// so that the command is stable and the file is always the last field
+// as required by the POST policy.
 func makeCurlCmd(key, postURL string, isRecursive bool, uploadInfo map[string]string) (string, *probe.Error) {
-	postURL += " "
-	curlCommand := "curl " + postURL
-	for k, v := range uploadInfo {
-		if k == "key" {
-			key = v
-			continue
+	if v, ok := uploadInfo["key"]; ok {
+		key = v
+	}
+	fields := make([]string, 0, len(uploadInfo))
+	for k := range uploadInfo {
+		if k != "key" {
+			fields = append(fields, k)
 		}
-		curlCommand += fmt.Sprintf("-F %s=%s ", k, v)
+	}
+	sort.Strings(fields)
+
+	curlCommand := "curl -X POST " + shellQuote(postURL) + " "
+	for _, k := range fields {
+		curlCommand += fmt.Sprintf("-F %s ", shellQuote(k+"="+uploadInfo[k]))
 	}
 	// If key starts with is enabled prefix it with the output.
 	if isRecursive {
-		curlCommand += fmt.Sprintf("-F key=%s<NAME> ", key) // Object name.
+		curlCommand += fmt.Sprintf("-F %s ", shellQuote("key="+key+"<NAME>")) // Object name.
 	} else {
-		curlCommand += fmt.Sprintf("-F key=%s ", key) // Object name.
+		curlCommand += fmt.Sprintf("-F %s ", shellQuote("key="+key)) // Object name.
 	}
-	curlCommand += "-F file=@<FILE>" // File to upload.
+	curlCommand += "-F " + shellQuote("file=@<FILE>") // File to upload.
 	return curlCommand, nil
 }
 
+// makeFormData returns the fields of a browser upload form, browsers
+// substitute ${filename} in the key with the name of the chosen file.
+func makeFormData(isRecursive bool, uploadInfo map[string]string) map[string]string {
+	formData := make(map[string]string, len(uploadInfo))
+	for k, v := range uploadInfo {
+		formData[k] = v
+	}
+	if isRecursive {
+		formData["key"] += "${filename}"
+	}
+	return formData
+}
+
 // save shared URL to disk.
 func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, contentType string) *probe.Error {
 	// Load previously saved upload-shares.
@@ -143,14 +192,14 @@ func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, cont
 }
 
 // doShareUploadURL uploads files to the target.
-func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, expiry time.Duration, contentType string) *probe.Error {
+func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, expiry time.Duration, contentType string, minSize, maxSize int64) *probe.Error {
 	clnt, err := newClient(objectURL)
 	if err != nil {
 		return err.Trace(objectURL)
 	}
 
 	// Generate pre-signed access info.
-	shareURL, uploadInfo, err := clnt.ShareUpload(context.Background(), isRecursive, expiry, contentType)
+	shareURL, uploadInfo, err := clnt.ShareUpload(context.Background(), isRecursive, expiry, contentType, minSize, maxSize)
 	if err != nil {
 		return err.Trace(objectURL, "expiry="+expiry.String(), "contentType="+contentType)
 	}
@@ -169,6 +218,8 @@ func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, e
 		ShareURL:    curlCmd,
 		TimeLeft:    expiry,
 		ContentType: contentType,
+		PostURL:     shareURL,
+		FormData:    makeFormData(isRecursive, uploadInfo),
 	})
 
 	// save shared URL to disk.
@@ -191,17 +242,13 @@ func mainShareUpload(cliCtx *cli.Context) error {
 
 	// Set command flags from context.
 	isRecursive := cliCtx.Bool("recursive")
-	expireArg := cliCtx.String("expire")
-	expiry := shareDefaultExpiry
+	expiry := parseShareExpiry(cliCtx.String("expire"))
 	contentType := cliCtx.String("content-type")
-	if expireArg != "" {
-		var e error
-		expiry, e = time.ParseDuration(expireArg)
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
-	}
+	minSize, maxSize, _ := parseContentLengthRange(cliCtx.String("content-length-range"))
 
 	for _, targetURL := range cliCtx.Args() {
-		err := doShareUploadURL(ctx, targetURL, isRecursive, expiry, contentType)
+		warnShareCredentialExpiry(targetURL, expiry)
+		err := doShareUploadURL(ctx, targetURL, isRecursive, expiry, contentType, minSize, maxSize)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
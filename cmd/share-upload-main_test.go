@@ -0,0 +1,106 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestMakeCurlCmd(t *testing.T) {
+	uploadInfo := map[string]string{
+		"x-amz-signature":  "abcdef",
+		"policy":           "eyJleHBpcmF0aW9uIjoi",
+		"key":              "backup/",
+		"x-amz-credential": "minio/20211231/us-east-1/s3/aws4_request",
+		"Content-Type":     "image/png",
+	}
+
+	testCases := []struct {
+		isRecursive bool
+		expected    string
+	}{
+		{
+			false,
+			"curl -X POST 'https://play.min.io/mybucket/' " +
+				"-F 'Content-Type=image/png' -F 'policy=eyJleHBpcmF0aW9uIjoi' " +
+				"-F 'x-amz-credential=minio/20211231/us-east-1/s3/aws4_request' -F 'x-amz-signature=abcdef' " +
+				"-F 'key=backup/' -F 'file=@<FILE>'",
+		},
+		{
+			true,
+			"curl -X POST 'https://play.min.io/mybucket/' " +
+				"-F 'Content-Type=image/png' -F 'policy=eyJleHBpcmF0aW9uIjoi' " +
+				"-F 'x-amz-credential=minio/20211231/us-east-1/s3/aws4_request' -F 'x-amz-signature=abcdef' " +
+				"-F 'key=backup/<NAME>' -F 'file=@<FILE>'",
+		},
+	}
+
+	for i, testCase := range testCases {
+		curlCmd, err := makeCurlCmd("", "https://play.min.io/mybucket/", testCase.isRecursive, uploadInfo)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if curlCmd != testCase.expected {
+			t.Errorf("Test %d: expected `%s`, got `%s`", i+1, testCase.expected, curlCmd)
+		}
+	}
+
+	if quoted := shellQuote("it's"); quoted != `'it'\''s'` {
+		t.Errorf("Unexpected shell quoting `%s`", quoted)
+	}
+}
+
+func TestParseContentLengthRange(t *testing.T) {
+	testCases := []struct {
+		arg      string
+		minSize  int64
+		maxSize  int64
+		errorExp bool
+	}{
+		{"", 0, 0, false},
+		{"10MiB", 0, 10 << 20, false},
+		{"1KiB-64MiB", 1 << 10, 64 << 20, false},
+		{"100 - 200", 100, 200, false},
+		{"0", 0, 0, true},
+		{"2MiB-1MiB", 0, 0, true},
+		{"1KiB-", 0, 0, true},
+		{"ten", 0, 0, true},
+	}
+
+	for i, testCase := range testCases {
+		minSize, maxSize, err := parseContentLengthRange(testCase.arg)
+		if (err != nil) != testCase.errorExp {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.errorExp, err)
+		}
+		if minSize != testCase.minSize || maxSize != testCase.maxSize {
+			t.Errorf("Test %d: expected %d-%d, got %d-%d", i+1, testCase.minSize, testCase.maxSize, minSize, maxSize)
+		}
+	}
+}
+
+func TestMakeFormData(t *testing.T) {
+	uploadInfo := map[string]string{"key": "backup/", "policy": "eyJleHBpcmF0aW9uIjoi"}
+
+	if formData := makeFormData(false, uploadInfo); formData["key"] != "backup/" || formData["policy"] != uploadInfo["policy"] {
+		t.Errorf("Unexpected form data %v", formData)
+	}
+	if formData := makeFormData(true, uploadInfo); formData["key"] != "backup/${filename}" {
+		t.Errorf("Unexpected key `%s` for a prefix", formData["key"])
+	}
+	if uploadInfo["key"] != "backup/" {
+		t.Errorf("Upload info modified, key is `%s`", uploadInfo["key"])
+	}
+}
@@ -26,15 +26,19 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	jwtgo "github.com/golang-jwt/jwt"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/console"
+	"maze.io/x/duration"
 )
 
 const (
 	// Default expiry is 7 days (168h).
 	shareDefaultExpiry = time.Duration(604800) * time.Second
+	// Maximum expiry allowed by signature V4 presigned requests.
+	shareMaxExpiry = time.Duration(604800) * time.Second
 )
 
 // Upload specific flags.
@@ -46,7 +50,7 @@ var (
 	shareFlagExpire = cli.StringFlag{
 		Name:  "expire, E",
 		Value: "168h",
-		Usage: "set expiry in NN[h|m|s]",
+		Usage: "set expiry in NN[d|h|m|s], at most 7d",
 	}
 )
 
@@ -57,6 +61,10 @@ type shareMesssage struct {
 	ShareURL    string        `json:"share"`
 	TimeLeft    time.Duration `json:"timeLeft"`
 	ContentType string        `json:"contentType,omitempty"` // Only used by upload cmd.
+	// POST policy form of the upload cmd, for HTML forms uploading
+	// directly from a browser. Printed in JSON output only.
+	PostURL  string            `json:"postUrl,omitempty"`
+	FormData map[string]string `json:"formData,omitempty"`
 }
 
 // String - Themefied string message for console printing.
@@ -93,6 +101,55 @@ func (s shareMesssage) JSON() string {
 	return string(shareMessageBytes)
 }
 
+// parseShareExpiry parses and validates the --expire argument,
+// defaults to 7 days when empty.
+func parseShareExpiry(expireArg string) time.Duration {
+	expiry := shareDefaultExpiry
+	if expireArg != "" {
+		d, e := duration.ParseDuration(expireArg)
+		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
+		expiry = time.Duration(d)
+	}
+	if expiry < time.Second {
+		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be lesser than 1 second.")
+	}
+	if expiry > shareMaxExpiry {
+		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be larger than 7 days.")
+	}
+	return expiry
+}
+
+// sessionTokenExpiry returns the expiry of a session token issued by
+// MinIO STS, which is a JWT with an `exp` claim. Other tokens are opaque.
+func sessionTokenExpiry(sessionToken string) (time.Time, bool) {
+	claims := jwtgo.MapClaims{}
+	if _, _, e := new(jwtgo.Parser).ParseUnverified(sessionToken, claims); e != nil {
+		return time.Time{}, false
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
+
+// warnShareCredentialExpiry warns if the temporary credentials of the alias
+// expire before the share does, the shared URL stops working at that point.
+func warnShareCredentialExpiry(targetURL string, expiry time.Duration) {
+	_, _, hostCfg := mustExpandAlias(targetURL)
+	if hostCfg == nil || hostCfg.SessionToken == "" {
+		return
+	}
+	credsExpiry, ok := sessionTokenExpiry(hostCfg.SessionToken)
+	if !ok || !credsExpiry.Before(time.Now().Add(expiry)) {
+		return
+	}
+	if !globalQuiet && !globalJSON {
+		console.Infof("Credentials of `%s` expire at %s, shared URL will stop working before the requested expiry of %s.\n",
+			targetURL, credsExpiry.Local().Format(printDate), timeDurationToHumanizedDuration(expiry))
+	}
+}
+
 // shareSetColor sets colors share sub-commands.
 func shareSetColor() {
 	// Additional command speific theme customization.
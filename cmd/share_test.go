@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt"
+)
+
+func TestSessionTokenExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	token, e := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims{
+		"accessKey": "minio",
+		"exp":       exp.Unix(),
+	}).SignedString([]byte("minio123"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got, ok := sessionTokenExpiry(token)
+	if !ok {
+		t.Fatal("expected expiry to be found in the session token")
+	}
+	if !got.Equal(exp) {
+		t.Fatalf("expected expiry %s, got %s", exp, got)
+	}
+
+	// Opaque tokens such as AWS STS tokens carry no expiry.
+	if _, ok = sessionTokenExpiry("FwoGZXIvYXdzEBYaDHqa0AP"); ok {
+		t.Fatal("expected no expiry for an opaque session token")
+	}
+}
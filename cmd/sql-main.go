@@ -436,6 +436,8 @@ func checkSQLSyntax(ctx *cli.Context) {
 
 // mainSQL is the main entry point for sql command.
 func mainSQL(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelSQL := context.WithCancel(globalContext)
 	defer cancelSQL()
 
@@ -46,6 +46,10 @@ var (
 			Name:  "recursive, r",
 			Usage: "stat all objects recursively",
 		},
+		cli.BoolFlag{
+			Name:  "acl",
+			Usage: "show the owner and ACL grants of objects and buckets",
+		},
 	}
 )
 
@@ -91,6 +95,9 @@ EXAMPLES:
 
   7. Stat all objects versions recursively created before 1st January 2020.
      {{.Prompt}} {{.HelpName}} --versions --rewind 2020.01.01T00:00 s3/personal-docs/
+
+  8. Stat an object along with its owner and ACL grants.
+     {{.Prompt}} {{.HelpName}} --acl s3/personal-docs/2018-account_report.docx
 `,
 }
 
@@ -136,6 +143,8 @@ func parseAndCheckStatSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB
 
 // mainStat - is a handler for mc stat command
 func mainStat(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelStat := context.WithCancel(globalContext)
 	defer cancelStat()
 
@@ -162,9 +171,11 @@ func mainStat(cliCtx *cli.Context) error {
 		args = []string{"."}
 	}
 
+	withACL := cliCtx.Bool("acl")
+
 	var cErr error
 	for _, targetURL := range args {
-		contents, bstats, err := statURL(ctx, targetURL, versionID, rewind, withVersions, false, isRecursive, encKeyDB)
+		contents, bstats, err := statURL(ctx, targetURL, versionID, rewind, withVersions, false, isRecursive, withACL, encKeyDB)
 		if err != nil {
 			fatalIf(err, "Unable to stat `"+targetURL+"`.")
 		}
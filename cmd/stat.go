@@ -24,6 +24,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -49,9 +50,13 @@ type statMessage struct {
 	Expiration        time.Time         `json:"expiration,omitempty"`
 	ExpirationRuleID  string            `json:"expirationRuleID,omitempty"`
 	ReplicationStatus string            `json:"replicationStatus,omitempty"`
+	StorageClass      string            `json:"storageClass,omitempty"`
 	Metadata          map[string]string `json:"metadata,omitempty"`
 	VersionID         string            `json:"versionID,omitempty"`
 	DeleteMarker      bool              `json:"deleteMarker,omitempty"`
+	Owner             string            `json:"owner,omitempty"`
+	Grants            []ClientGrant     `json:"grants,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
 	singleObject      bool
 }
 
@@ -73,6 +78,30 @@ func (stat statMessage) String() (msg string) {
 		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "VersionID", versionIDField) + "\n")
 	}
 	msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Type", stat.Type) + "\n")
+	if stat.StorageClass != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Class", stat.StorageClass) + "\n")
+	}
+	if stat.Owner != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Owner", stat.Owner) + "\n")
+	}
+	if len(stat.Grants) > 0 {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s:", "ACL") + "\n")
+		for _, grant := range stat.Grants {
+			msgBuilder.WriteString(fmt.Sprintf("  %s: %s ", grant.Grantee, grant.Permission) + "\n")
+		}
+	}
+	if len(stat.Tags) > 0 {
+		keys := make([]string, 0, len(stat.Tags))
+		for k := range stat.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tags := make([]string, 0, len(keys))
+		for _, k := range keys {
+			tags = append(tags, k+":"+stat.Tags[k])
+		}
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Tags", strings.Join(tags, ", ")) + "\n")
+	}
 	if !stat.Expires.IsZero() {
 		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Expires", stat.Expires.Format(printDate)) + "\n")
 	}
@@ -149,6 +178,10 @@ func parseStat(c *ClientContent) statMessage {
 	content.Expiration = c.Expiration
 	content.ExpirationRuleID = c.ExpirationRuleID
 	content.ReplicationStatus = c.ReplicationStatus
+	content.StorageClass = c.StorageClass
+	content.Owner = c.Owner
+	content.Grants = c.Grants
+	content.Tags = c.Tags
 	return content
 }
 
@@ -157,10 +190,23 @@ func getStandardizedURL(targetURL string) string {
 	return filepath.FromSlash(targetURL)
 }
 
+// isACLUnavailable returns true if the server does not serve object ACLs,
+// or not to this user.
+func isACLUnavailable(err *probe.Error) bool {
+	if _, ok := err.ToGoError().(APINotImplemented); ok {
+		return true
+	}
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "NotImplemented", "AccessDenied":
+		return true
+	}
+	return false
+}
+
 // statURL - uses combination of GET listing and HEAD to fetch information of one or more objects
 // HEAD can fail with 400 with an SSE-C encrypted object but we still return information gathered
 // from GET listing.
-func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive bool, encKeyDB map[string][]prefixSSEPair) ([]*ClientContent, []*BucketInfo, *probe.Error) {
+func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive, withACL bool, encKeyDB map[string][]prefixSSEPair) ([]*ClientContent, []*BucketInfo, *probe.Error) {
 	var stats []*ClientContent
 	var bucketStats []*BucketInfo
 	var clnt Client
@@ -229,10 +275,44 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 		if err != nil {
 			continue
 		}
+		if stat.Owner == "" {
+			stat.Owner = content.Owner
+		}
+		// ACLs are only served for the latest version of an object.
+		if withACL && clnt != nil && !stat.Type.IsDir() && versionID == "" && timeRef.IsZero() && !includeOlderVersions {
+			owner, grants, err := clnt.GetObjectACL(ctx)
+			switch {
+			case err == nil:
+				if owner != "" {
+					stat.Owner = owner
+				}
+				stat.Grants = grants
+			case !isACLUnavailable(err):
+				errorIf(err.Trace(url), "Unable to get the ACL of `"+url+"`.")
+			}
+		}
+		// Only fetch tags when the object reports having some.
+		if clnt != nil && !stat.Type.IsDir() {
+			if count, _ := strconv.Atoi(stat.Metadata["X-Amz-Tagging-Count"]); count > 0 {
+				if tags, err := clnt.GetTags(ctx, stat.VersionID); err == nil {
+					stat.Tags = tags
+				}
+			}
+		}
 		// if stat is on a bucket and non-recursive mode, serve the bucket metadata
 		if clnt != nil && !isRecursive && stat.Type.IsDir() {
 			bstat, err := clnt.GetBucketInfo(ctx)
 			if err == nil {
+				if withACL {
+					owner, grants, err := clnt.GetBucketACL(ctx)
+					switch {
+					case err == nil:
+						bstat.ACL.Owner = owner
+						bstat.ACL.Grants = grants
+					case !isACLUnavailable(err):
+						errorIf(err.Trace(url), "Unable to get the ACL of `"+url+"`.")
+					}
+				}
 				// Convert any os specific delimiters to "/".
 				contentURL := filepath.ToSlash(bstat.URL.Path)
 				prefixPath = filepath.ToSlash(prefixPath)
@@ -284,6 +364,10 @@ type BucketInfo struct {
 		Type string `json:"type"`
 		Text string `json:"policy,omitempty"`
 	} `json:"Policy,omitempty"`
+	ACL struct {
+		Owner  string        `json:"owner,omitempty"`
+		Grants []ClientGrant `json:"grants,omitempty"`
+	} `json:"ACL,omitempty"`
 	Location string            `json:"location"`
 	Tagging  map[string]string `json:"tagging,omitempty"`
 	ILM      struct {
@@ -394,6 +478,15 @@ func (v bucketInfoMessage) String() string {
 		fmt.Fprintf(&b, console.Colorize("Set", info.Policy.Type))
 	}
 	fmt.Fprintln(&b)
+	if len(info.ACL.Grants) > 0 {
+		fmt.Fprintf(&b, "%2s%s", placeHolder, "ACL: ")
+		fmt.Fprintln(&b)
+		for _, grant := range info.ACL.Grants {
+			fmt.Fprintf(&b, "%4s%s: ", placeHolder, grant.Grantee)
+			fmt.Fprintf(&b, console.Colorize("Value", grant.Permission))
+			fmt.Fprintln(&b)
+		}
+	}
 	if info.Tags() != "" {
 		fmt.Fprintf(&b, "%2s%s", placeHolder, "Tagging: ")
 		fmt.Fprintf(&b, console.Colorize("Generic", info.Tags()))
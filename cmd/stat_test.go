@@ -18,11 +18,15 @@
 package cmd
 
 import (
+	"net/http"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
 )
 
 func TestParseStat(t *testing.T) {
@@ -34,7 +38,7 @@ func TestParseStat(t *testing.T) {
 		{ClientContent{URL: *newClientURL("https://play.min.io/abc"), Size: 0, Time: localTime, Type: os.ModeDir, ETag: "blahblah", Metadata: map[string]string{"cusom-key": "custom-value"}, Expires: time.Now()}, "play"},
 		{ClientContent{URL: *newClientURL("https://play.min.io/testbucket"), Size: 500, Time: localTime, Type: os.ModeDir, ETag: "blahblah", Metadata: map[string]string{"cusom-key": "custom-value"}, Expires: time.Unix(0, 0).UTC()}, "play"},
 		{ClientContent{URL: *newClientURL("https://s3.amazonaws.com/yrdy"), Size: 0, Time: localTime, Type: 0o644, ETag: "abcdefasaas", Metadata: map[string]string{}}, "s3"},
-		{ClientContent{URL: *newClientURL("https://play.min.io/yrdy"), Size: 10000, Time: localTime, Type: 0o644, ETag: "blahblah", Metadata: map[string]string{"cusom-key": "custom-value"}}, "play"},
+		{ClientContent{URL: *newClientURL("https://play.min.io/yrdy"), Size: 10000, Time: localTime, Type: 0o644, ETag: "blahblah", Metadata: map[string]string{"cusom-key": "custom-value"}, Tags: map[string]string{"project": "mc", "env": "test"}, StorageClass: "STANDARD_IA"}, "play"},
 	}
 	for _, testCase := range testCases {
 		testCase := testCase
@@ -43,6 +47,15 @@ func TestParseStat(t *testing.T) {
 			if !reflect.DeepEqual(testCase.content.Metadata, statMsg.Metadata) {
 				t.Errorf("Expecting %s, got %s", testCase.content.Metadata, statMsg.Metadata)
 			}
+			if !reflect.DeepEqual(testCase.content.Tags, statMsg.Tags) {
+				t.Errorf("Expecting %s, got %s", testCase.content.Tags, statMsg.Tags)
+			}
+			if len(statMsg.Tags) > 0 && !strings.Contains(statMsg.String(), "Tags      : env:test, project:mc") {
+				t.Errorf("Expecting sorted tags, got %s", statMsg.String())
+			}
+			if testCase.content.StorageClass != statMsg.StorageClass {
+				t.Errorf("Expecting %s, got %s", testCase.content.StorageClass, statMsg.StorageClass)
+			}
 			if testCase.content.Size != statMsg.Size {
 				t.Errorf("Expecting %d, got %d", testCase.content.Size, statMsg.Size)
 			}
@@ -66,3 +79,20 @@ func TestParseStat(t *testing.T) {
 		})
 	}
 }
+
+func TestIsACLUnavailable(t *testing.T) {
+	testCases := []struct {
+		err         error
+		unavailable bool
+	}{
+		{APINotImplemented{API: "GetObjectACL", APIType: "filesystem"}, true},
+		{minio.ErrorResponse{Code: "NotImplemented", StatusCode: http.StatusNotImplemented}, true},
+		{minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}, true},
+		{minio.ErrorResponse{Code: "InternalError", StatusCode: http.StatusInternalServerError}, false},
+	}
+	for i, testCase := range testCases {
+		if unavailable := isACLUnavailable(probe.NewError(testCase.err)); unavailable != testCase.unavailable {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.unavailable, unavailable)
+		}
+	}
+}
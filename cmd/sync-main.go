@@ -0,0 +1,584 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	jsoncolor "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// Folder in the config folder holding the snapshots of the last syncs.
+const globalSyncDir = "sync"
+
+var syncFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "conflict",
+		Value: "skip",
+		Usage: "resolve objects changed on both sides. Valid options are '[skip, newer, first, second]'",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "show the changes without applying them",
+	},
+}
+
+var syncCmd = cli.Command{
+	Name:         "sync",
+	Usage:        "synchronize two folders/buckets in both directions",
+	Action:       mainSync,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(syncFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FIRST SECOND
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Both sides are compared with a snapshot of the last sync, objects created, modified or
+  removed on one side since then are created, modified or removed on the other side.
+  Objects changed on both sides are conflicts, they are reported and left untouched unless
+  a resolution policy is given with --conflict. The first sync copies the objects missing
+  on either side, later syncs fail if either folder does not exist anymore. Local files are
+  compared by their MD5 sum with objects of the same size, the sums are cached in the
+  config folder and only computed again for modified files.
+
+EXAMPLES:
+  1. Synchronize a local folder with a bucket.
+     {{.Prompt}} {{.HelpName}} ~/Documents play/mybucket/documents
+
+  2. Show what would be synchronized, without changing anything.
+     {{.Prompt}} {{.HelpName}} --dry-run ~/Documents play/mybucket/documents
+
+  3. Synchronize two buckets, keeping the most recently modified object on conflicts.
+     {{.Prompt}} {{.HelpName}} --conflict newer play/mybucket s3/mybucket
+`,
+}
+
+// syncEntry is the state of an object on one side of a sync.
+type syncEntry struct {
+	Size    int64     `json:"size"`
+	ETag    string    `json:"etag,omitempty"`
+	ModTime time.Time `json:"modTime"`
+}
+
+func newSyncEntry(content *ClientContent) *syncEntry {
+	if content == nil {
+		return nil
+	}
+	return &syncEntry{
+		Size:    content.Size,
+		ETag:    strings.Trim(content.ETag, "\""),
+		ModTime: content.Time.UTC().Truncate(time.Second),
+	}
+}
+
+// syncState is the state of an object on both sides after a sync.
+type syncState struct {
+	First  *syncEntry `json:"first"`
+	Second *syncEntry `json:"second"`
+}
+
+// syncSnapshot is the state of all objects after the last sync.
+type syncSnapshot struct {
+	Version string                `json:"version"`
+	First   string                `json:"first"`
+	Second  string                `json:"second"`
+	Objects map[string]*syncState `json:"objects"`
+}
+
+// syncChanged returns true if an object of one side changed since the snapshot.
+func syncChanged(prev, now *syncEntry) bool {
+	if prev == nil || now == nil {
+		return prev != now
+	}
+	if prev.Size != now.Size {
+		return true
+	}
+	if prev.ETag != "" && now.ETag != "" {
+		return prev.ETag != now.ETag
+	}
+	return !prev.ModTime.Equal(now.ModTime)
+}
+
+// syncSameContent returns true if the objects of both sides are known to be
+// identical. Without comparable MD5 ETags the modification times must match,
+// reporting a false conflict is better than losing a change.
+func syncSameContent(first, second *syncEntry) bool {
+	if first.Size != second.Size {
+		return false
+	}
+	if first.ETag != "" && first.ETag == second.ETag {
+		return true
+	}
+	plainMD5 := func(etag string) bool {
+		return etag != "" && !strings.Contains(etag, "-")
+	}
+	if plainMD5(first.ETag) && plainMD5(second.ETag) {
+		return false
+	}
+	return first.ModTime.Equal(second.ModTime)
+}
+
+// syncOp is the operation needed to synchronize an object.
+type syncOp int
+
+const (
+	syncNone syncOp = iota
+	syncCopyToSecond
+	syncCopyToFirst
+	syncRemoveFirst
+	syncRemoveSecond
+	syncConflict
+)
+
+// syncAction decides how to synchronize an object from its state in the
+// last snapshot, nil if it was unknown, and its current state on both sides.
+func syncAction(prev *syncState, first, second *syncEntry, policy string) syncOp {
+	if prev == nil {
+		prev = &syncState{}
+	}
+	firstChanged := syncChanged(prev.First, first)
+	secondChanged := syncChanged(prev.Second, second)
+
+	propagate := func(fromFirst bool) syncOp {
+		switch {
+		case fromFirst && first != nil:
+			return syncCopyToSecond
+		case fromFirst && second != nil:
+			return syncRemoveSecond
+		case !fromFirst && second != nil:
+			return syncCopyToFirst
+		case !fromFirst && first != nil:
+			return syncRemoveFirst
+		}
+		return syncNone
+	}
+
+	switch {
+	case !firstChanged && !secondChanged:
+		return syncNone
+	case firstChanged && !secondChanged:
+		return propagate(true)
+	case secondChanged && !firstChanged:
+		return propagate(false)
+	}
+
+	// Changed on both sides.
+	if first == nil && second == nil {
+		return syncNone
+	}
+	if first != nil && second != nil && syncSameContent(first, second) {
+		return syncNone
+	}
+	switch policy {
+	case "first":
+		return propagate(true)
+	case "second":
+		return propagate(false)
+	case "newer":
+		if first != nil && second != nil {
+			return propagate(!second.ModTime.After(first.ModTime))
+		}
+	}
+	return syncConflict
+}
+
+// syncMessage container for sync operations.
+type syncMessage struct {
+	Status    string `json:"status"`
+	Operation string `json:"operation"`
+	Source    string `json:"source,omitempty"`
+	Target    string `json:"target"`
+	Size      int64  `json:"size,omitempty"`
+	DryRun    bool   `json:"dryRun,omitempty"`
+}
+
+// String colorized sync message.
+func (s syncMessage) String() string {
+	var msg string
+	switch s.Operation {
+	case "copy":
+		msg = console.Colorize("Sync", fmt.Sprintf("`%s` -> `%s`", s.Source, s.Target))
+	case "remove":
+		msg = console.Colorize("Sync", fmt.Sprintf("Removed `%s`", s.Target))
+	case "conflict":
+		return console.Colorize("SyncConflict", fmt.Sprintf("Conflict: `%s` and `%s` both changed since the last sync.", s.Source, s.Target))
+	}
+	if s.DryRun {
+		msg += " (dry run)"
+	}
+	return msg
+}
+
+// JSON jsonified sync message.
+func (s syncMessage) JSON() string {
+	s.Status = "success"
+	syncMessageBytes, e := jsoncolor.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(syncMessageBytes)
+}
+
+// syncSide is one of the folders of a sync.
+type syncSide struct {
+	alias   string
+	url     string
+	clnt    Client
+	objects map[string]*ClientContent
+	// missing is set if the folder does not exist.
+	missing bool
+}
+
+func newSyncSide(ctx context.Context, aliasedURL string) (*syncSide, *probe.Error) {
+	separator := string(newClientURL(aliasedURL).Separator)
+	if !strings.HasSuffix(aliasedURL, separator) {
+		aliasedURL += separator
+	}
+	alias, urlStr, _ := mustExpandAlias(aliasedURL)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+
+	side := &syncSide{alias: alias, url: urlStr, clnt: clnt, objects: make(map[string]*ClientContent)}
+	if clnt.GetURL().Type == fileSystem {
+		// Local listings of a missing folder are empty.
+		if _, err = clnt.Stat(ctx, StatOptions{}); err != nil {
+			switch err.ToGoError().(type) {
+			case PathNotFound, ObjectMissing:
+				side.missing = true
+				return side, nil
+			}
+			return nil, err.Trace(aliasedURL)
+		}
+	}
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			switch content.Err.ToGoError().(type) {
+			case BucketDoesNotExist:
+				// A missing side is synchronized as an empty one on
+				// the first sync, see checkSyncSides.
+				side.missing = true
+				continue
+			case ObjectMissing, PathNotFound:
+				// An empty prefix is synchronized as well.
+				continue
+			}
+			return nil, content.Err.Trace(aliasedURL)
+		}
+		if !content.Type.IsRegular() {
+			continue
+		}
+		key := filepath.ToSlash(strings.TrimPrefix(content.URL.String(), urlStr))
+		side.objects[key] = content
+	}
+	return side, nil
+}
+
+// checkSyncSides returns an error if a side does not exist while the
+// snapshot has objects, a mistyped or unmounted folder would otherwise
+// look like all objects were removed from it.
+func checkSyncSides(snapshot *syncSnapshot, sides ...*syncSide) *probe.Error {
+	if len(snapshot.Objects) == 0 {
+		return nil
+	}
+	for _, side := range sides {
+		if side.missing {
+			return errSyncRootMissing(side.aliasedPath(""))
+		}
+	}
+	return nil
+}
+
+// fillETag sets the ETag of an object of a local side to its MD5 sum,
+// comparable with the plain MD5 ETag or the sum of the other side, so that
+// both are compared by content. Sums are cached between runs. In FIPS mode
+// local files are compared by size and modification time instead.
+func (s *syncSide) fillETag(key string, entry, other *syncEntry) {
+	if globalFIPS || entry.ETag != "" || s.clnt.GetURL().Type != fileSystem || strings.Contains(other.ETag, "-") {
+		return
+	}
+	if sum, e := localMD5(newClientURL(s.targetURL(key)).Path); e == nil {
+		entry.ETag = sum
+	}
+}
+
+// targetURL returns the URL of key on this side.
+func (s *syncSide) targetURL(key string) string {
+	return urlJoinPath(s.url, key)
+}
+
+// aliasedPath returns key on this side as shown to the user.
+func (s *syncSide) aliasedPath(key string) string {
+	return filepath.ToSlash(filepath.Join(s.alias, newClientURL(s.targetURL(key)).Path))
+}
+
+// getSyncSnapshotPath returns the snapshot file of the sync of two folders.
+func getSyncSnapshotPath(first, second string) (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalSyncDir, getHash("sync", []string{first, "\x00", second})+".json"), nil
+}
+
+// loadSyncSnapshot reads the snapshot of the last sync, a missing file is an
+// empty snapshot.
+func loadSyncSnapshot(path string) (*syncSnapshot, *probe.Error) {
+	snapshot := &syncSnapshot{Objects: make(map[string]*syncState)}
+	data, e := ioutil.ReadFile(path)
+	if os.IsNotExist(e) {
+		return snapshot, nil
+	}
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if e = json.Unmarshal(data, snapshot); e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	if snapshot.Objects == nil {
+		snapshot.Objects = make(map[string]*syncState)
+	}
+	return snapshot, nil
+}
+
+// saveSyncSnapshot atomically replaces the snapshot file.
+func saveSyncSnapshot(path string, snapshot *syncSnapshot) *probe.Error {
+	if e := os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return probe.NewError(e)
+	}
+	data, e := json.Marshal(snapshot)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	tmpPath := path + ".tmp"
+	if e = ioutil.WriteFile(tmpPath, data, 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	return probe.NewError(os.Rename(tmpPath, path))
+}
+
+// syncCopy copies key from one side to the other and returns the new
+// state of the copy.
+func syncCopy(ctx context.Context, from, to *syncSide, key string, encKeyDB map[string][]prefixSSEPair) (*syncEntry, *probe.Error) {
+	targetURL := to.targetURL(key)
+	urls := uploadSourceToTargetURL(ctx, URLs{
+		SourceAlias:   from.alias,
+		SourceContent: from.objects[key],
+		TargetAlias:   to.alias,
+		TargetContent: &ClientContent{URL: *newClientURL(targetURL)},
+	}, nil, encKeyDB, false)
+	if urls.Error != nil {
+		return nil, urls.Error.Trace(key)
+	}
+
+	clnt, err := newClientFromAlias(to.alias, targetURL)
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	content, err := clnt.Stat(ctx, StatOptions{sse: getSSE(to.aliasedPath(key), encKeyDB[to.alias])})
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	return newSyncEntry(content), nil
+}
+
+// syncRemove removes key from a side.
+func syncRemove(ctx context.Context, side *syncSide, key string) *probe.Error {
+	targetURL := side.targetURL(key)
+	clnt, err := newClientFromAlias(side.alias, targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: *newClientURL(targetURL)}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, contentCh) {
+		if result.Err != nil {
+			return result.Err.Trace(targetURL)
+		}
+	}
+	return nil
+}
+
+// checkSyncSyntax - validate all the passed arguments
+func checkSyncSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(cliCtx, "sync", 1) // last argument is exit code
+	}
+	for _, arg := range cliCtx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	switch cliCtx.String("conflict") {
+	case "skip", "newer", "first", "second":
+	default:
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("conflict")),
+			"Unrecognized conflict policy. Valid options are `[skip, newer, first, second]`.")
+	}
+}
+
+// mainSync is the entry point for sync command.
+func mainSync(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
+	ctx, cancelSync := context.WithCancel(globalContext)
+	defer cancelSync()
+
+	checkSyncSyntax(cliCtx)
+
+	console.SetColor("Sync", color.New(color.FgGreen))
+	console.SetColor("SyncConflict", color.New(color.FgYellow, color.Bold))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	args := cliCtx.Args()
+	policy := cliCtx.String("conflict")
+	dryRun := cliCtx.Bool("dry-run")
+
+	first, err := newSyncSide(ctx, args[0])
+	fatalIf(err, "Unable to list `"+args[0]+"`.")
+	second, err := newSyncSide(ctx, args[1])
+	fatalIf(err, "Unable to list `"+args[1]+"`.")
+
+	snapshotPath, err := getSyncSnapshotPath(first.url, second.url)
+	fatalIf(err, "Unable to locate the sync snapshot.")
+	snapshot, err := loadSyncSnapshot(snapshotPath)
+	fatalIf(err, "Unable to load the sync snapshot `"+snapshotPath+"`.")
+	fatalIf(checkSyncSides(snapshot, first, second), "Unable to synchronize `"+args[0]+"` and `"+args[1]+"`.")
+
+	keys := make(map[string]struct{})
+	for key := range first.objects {
+		keys[key] = struct{}{}
+	}
+	for key := range second.objects {
+		keys[key] = struct{}{}
+	}
+	for key := range snapshot.Objects {
+		keys[key] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	next := &syncSnapshot{
+		Version: "1",
+		First:   first.url,
+		Second:  second.url,
+		Objects: make(map[string]*syncState),
+	}
+
+	var exitErr error
+	for _, key := range sortedKeys {
+		prev := snapshot.Objects[key]
+		firstEntry := newSyncEntry(first.objects[key])
+		secondEntry := newSyncEntry(second.objects[key])
+		if firstEntry != nil && secondEntry != nil && firstEntry.Size == secondEntry.Size {
+			first.fillETag(key, firstEntry, secondEntry)
+			second.fillETag(key, secondEntry, firstEntry)
+		}
+		current := &syncState{First: firstEntry, Second: secondEntry}
+
+		op := syncAction(prev, firstEntry, secondEntry, policy)
+		msg := syncMessage{DryRun: dryRun}
+		switch op {
+		case syncCopyToSecond:
+			msg.Operation, msg.Source, msg.Target, msg.Size = "copy", first.aliasedPath(key), second.aliasedPath(key), firstEntry.Size
+		case syncCopyToFirst:
+			msg.Operation, msg.Source, msg.Target, msg.Size = "copy", second.aliasedPath(key), first.aliasedPath(key), secondEntry.Size
+		case syncRemoveFirst:
+			msg.Operation, msg.Target = "remove", first.aliasedPath(key)
+		case syncRemoveSecond:
+			msg.Operation, msg.Target = "remove", second.aliasedPath(key)
+		case syncConflict:
+			msg.Operation, msg.Source, msg.Target = "conflict", first.aliasedPath(key), second.aliasedPath(key)
+		}
+		if msg.Operation != "" {
+			printMsg(msg)
+		}
+		if dryRun {
+			continue
+		}
+
+		switch op {
+		case syncNone:
+			if firstEntry != nil && secondEntry != nil {
+				next.Objects[key] = current
+			}
+			continue
+		case syncConflict:
+			// Keep the old state so that the conflict is reported again.
+			if prev != nil {
+				next.Objects[key] = prev
+			}
+			continue
+		}
+
+		switch op {
+		case syncCopyToSecond:
+			current.Second, err = syncCopy(ctx, first, second, key, encKeyDB)
+		case syncCopyToFirst:
+			current.First, err = syncCopy(ctx, second, first, key, encKeyDB)
+		case syncRemoveFirst:
+			err = syncRemove(ctx, first, key)
+			current = nil
+		case syncRemoveSecond:
+			err = syncRemove(ctx, second, key)
+			current = nil
+		}
+		if err != nil {
+			errorIf(err, "Unable to synchronize `"+key+"`.")
+			exitErr = exitStatus(globalErrorExitStatus)
+			if prev != nil {
+				next.Objects[key] = prev
+			}
+			continue
+		}
+		if current != nil {
+			next.Objects[key] = current
+		}
+	}
+
+	if !dryRun {
+		fatalIf(saveSyncSnapshot(snapshotPath, next), "Unable to save the sync snapshot `"+snapshotPath+"`.")
+	}
+	errorIf(saveMD5Cache(), "Unable to save the MD5 cache.")
+	return exitErr
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestSyncAction(t *testing.T) {
+	now := time.Now().UTC()
+	a := &syncEntry{Size: 1, ETag: "a", ModTime: now}
+	b := &syncEntry{Size: 2, ETag: "b", ModTime: now.Add(time.Minute)}
+	aCopy := &syncEntry{Size: 1, ETag: "a", ModTime: now.Add(time.Hour)}
+
+	testCases := []struct {
+		prev          *syncState
+		first, second *syncEntry
+		policy        string
+		op            syncOp
+	}{
+		// First sync.
+		{nil, a, nil, "skip", syncCopyToSecond},
+		{nil, nil, a, "skip", syncCopyToFirst},
+		{nil, a, aCopy, "skip", syncNone},
+		{nil, a, b, "skip", syncConflict},
+		{nil, a, b, "newer", syncCopyToFirst},
+		// Unchanged.
+		{&syncState{a, aCopy}, a, aCopy, "skip", syncNone},
+		// Changed on one side.
+		{&syncState{a, a}, b, a, "skip", syncCopyToSecond},
+		{&syncState{a, a}, a, b, "skip", syncCopyToFirst},
+		{&syncState{a, a}, nil, a, "skip", syncRemoveSecond},
+		{&syncState{a, a}, a, nil, "skip", syncRemoveFirst},
+		// Changed on both sides.
+		{&syncState{a, a}, nil, nil, "skip", syncNone},
+		{&syncState{a, a}, b, b, "skip", syncNone},
+		{&syncState{a, a}, b, nil, "skip", syncConflict},
+		{&syncState{a, a}, b, nil, "newer", syncConflict},
+		{&syncState{a, a}, b, nil, "first", syncCopyToSecond},
+		{&syncState{a, a}, b, nil, "second", syncRemoveFirst},
+		{&syncState{b, b}, a, aCopy, "skip", syncNone},
+		{&syncState{b, b}, &syncEntry{Size: 1, ModTime: now}, &syncEntry{Size: 1, ModTime: now}, "skip", syncNone},
+		{&syncState{b, b}, &syncEntry{Size: 1, ModTime: now}, &syncEntry{Size: 1, ModTime: now.Add(time.Second)}, "skip", syncConflict},
+		{&syncState{b, b}, aCopy, &syncEntry{Size: 1, ETag: "c", ModTime: now}, "newer", syncCopyToSecond},
+	}
+
+	for i, testCase := range testCases {
+		if op := syncAction(testCase.prev, testCase.first, testCase.second, testCase.policy); op != testCase.op {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.op, op)
+		}
+	}
+}
+
+func TestCheckSyncSides(t *testing.T) {
+	root, e := ioutil.TempDir("", "sync-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newMcConfig(), nil }
+
+	existing, err := newSyncSide(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := newSyncSide(context.Background(), filepath.Join(root, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing.missing || !missing.missing {
+		t.Fatalf("expected only the missing folder to be missing, got %t and %t", existing.missing, missing.missing)
+	}
+
+	a := &syncEntry{Size: 1, ETag: "a", ModTime: time.Now().UTC()}
+	synced := &syncSnapshot{Objects: map[string]*syncState{"a": {a, a}}}
+	if err = checkSyncSides(&syncSnapshot{}, existing, missing); err != nil {
+		t.Errorf("expected the first sync to a missing folder to succeed, got %s", err)
+	}
+	if err = checkSyncSides(synced, existing, existing); err != nil {
+		t.Errorf("expected existing folders to succeed, got %s", err)
+	}
+	if err = checkSyncSides(synced, existing, missing); err == nil {
+		t.Error("expected a missing folder synchronized before to fail")
+	}
+}
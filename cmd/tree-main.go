@@ -257,6 +257,8 @@ func doTree(ctx context.Context, url string, timeRef time.Time, level int, leaf
 
 // mainTree - is a handler for mc tree command
 func mainTree(cliCtx *cli.Context) error {
+	applyAliasFlags(cliCtx, cliCtx.Args())
+
 	ctx, cancelList := context.WithCancel(globalContext)
 	defer cancelList()
 
@@ -284,7 +286,7 @@ func mainTree(cliCtx *cli.Context) error {
 			}
 			clnt, err := newClientFromAlias(targetAlias, targetURL)
 			fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
-			if e := doList(ctx, clnt, true, false, false, timeRef, false); e != nil {
+			if e := doList(ctx, clnt, true, false, false, timeRef, false, false); e != nil {
 				cErr = e
 			}
 		}
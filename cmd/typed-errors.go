@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/mc/pkg/probe"
 )
 
@@ -120,9 +121,41 @@ var errSourceIsDir = func(URL string) *probe.Error {
 	return probe.NewError(sourceIsDirErr(errors.New(msg))).Untrace()
 }
 
+type duplicateTargetErr struct {
+	error
+}
+
+var errDuplicateTarget = func(firstURL, secondURL, targetURL string) *probe.Error {
+	msg := "Sources `" + firstURL + "` and `" + secondURL + "` both resolve to target `" + targetURL + "`."
+	return probe.NewError(duplicateTargetErr{errors.New(msg)}).Untrace()
+}
+
+type caseCollisionErr struct {
+	error
+}
+
+var errCaseCollision = func(firstURL, secondURL, targetURL string) *probe.Error {
+	msg := "Sources `" + firstURL + "` and `" + secondURL + "` differ only in case and overwrite each other at target `" + targetURL + "`, use --rename-case-collisions to copy both."
+	return probe.NewError(caseCollisionErr{errors.New(msg)}).Untrace()
+}
+
 type conflictSSEErr error
 
 var errConflictSSE = func(sseServer, sseKeys string) *probe.Error {
 	err := fmt.Errorf("SSE alias '%s' overlaps with SSE-C aliases '%s'", sseServer, sseKeys)
 	return probe.NewError(conflictSSEErr(err)).Untrace()
 }
+
+type queueFullErr error
+
+var errQueueFull = func(maxSize int64) *probe.Error {
+	msg := "Offline queue is full, the queued data is limited to " + humanize.IBytes(uint64(maxSize)) + "."
+	return probe.NewError(queueFullErr(errors.New(msg))).Untrace()
+}
+
+type syncRootMissingErr error
+
+var errSyncRootMissing = func(URL string) *probe.Error {
+	msg := "Folder `" + URL + "` does not exist but was synchronized before, its objects would be removed from the other side."
+	return probe.NewError(syncRootMissingErr(errors.New(msg))).Untrace()
+}
@@ -20,7 +20,6 @@ package cmd
 import (
 	"context"
 	"errors"
-	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -73,6 +72,9 @@ EXAMPLES:
 
   2. Undo the last upload/removal change of all objects under a prefix
      {{.Prompt}} {{.HelpName}} s3/backups/prefix/ --recursive --force
+
+  3. Show which versions would be removed to undo the last 2 changes under a prefix
+     {{.Prompt}} {{.HelpName}} s3/backups/prefix/ --recursive --force --last 2 --dry-run
 `,
 }
 
@@ -83,17 +85,21 @@ type undoMessage struct {
 	Key            string `json:"key,omitempty"`
 	VersionID      string `json:"versionId,omitempty"`
 	IsDeleteMarker bool   `json:"isDeleteMarker,omitempty"`
+	DryRun         bool   `json:"dryRun,omitempty"`
 }
 
 // String colorized string message.
 func (c undoMessage) String() string {
-	var msg string
-	fmt.Print(color.GreenString("\u2713 "))
+	msg := color.GreenString("\u2713 ")
 	yellow := color.New(color.FgYellow).SprintFunc()
+	reverted := "is reverted"
+	if c.DryRun {
+		reverted = "would be reverted"
+	}
 	if c.IsDeleteMarker {
-		msg += "Last " + color.RedString("delete") + " of `" + yellow(c.Key) + "` is reverted"
+		msg += "Last " + color.RedString("delete") + " of `" + yellow(c.Key) + "` " + reverted
 	} else {
-		msg += "Last " + color.BlueString("upload") + " of `" + yellow(c.Key) + "` (vid=" + c.VersionID + ") is reverted"
+		msg += "Last " + color.BlueString("upload") + " of `" + yellow(c.Key) + "` (vid=" + c.VersionID + ") " + reverted
 	}
 	msg += "."
 	return msg
@@ -168,6 +174,7 @@ func undoLastNOperations(ctx context.Context, clnt Client, objectVersions []*Cli
 				URL:            objectVersion.URL.String(),
 				VersionID:      objectVersion.VersionID,
 				IsDeleteMarker: objectVersion.IsDeleteMarker,
+				DryRun:         dryRun,
 			})
 
 		}
@@ -218,7 +225,9 @@ func undoURL(ctx context.Context, aliasedURL string, last int, recursive, dryRun
 
 		if lastObjectPath != content.URL.Path {
 			// Print any object in the current list before reinitializing it
-			exitErr = undoLastNOperations(ctx, clnt, perObjectVersions, last, dryRun)
+			if e := undoLastNOperations(ctx, clnt, perObjectVersions, last, dryRun); e != nil {
+				exitErr = e
+			}
 			lastObjectPath = content.URL.Path
 			perObjectVersions = []*ClientContent{}
 		}
@@ -228,7 +237,9 @@ func undoURL(ctx context.Context, aliasedURL string, last int, recursive, dryRun
 	}
 
 	// Undo the remaining versions found if any
-	exitErr = undoLastNOperations(ctx, clnt, perObjectVersions, last, dryRun)
+	if e := undoLastNOperations(ctx, clnt, perObjectVersions, last, dryRun); e != nil {
+		exitErr = e
+	}
 
 	if !atLeastOneUndoApplied {
 		errorIf(errDummy().Trace(clnt.GetURL().String()), "Unable to find any object version to undo.")
@@ -391,7 +391,9 @@ func getUpdateTransport(timeout time.Duration) http.RoundTripper {
 		TLSHandshakeTimeout:   timeout,
 		ExpectContinueTimeout: timeout,
 		TLSClientConfig: &tls.Config{
-			RootCAs: globalRootCAs,
+			RootCAs:          globalRootCAs,
+			CipherSuites:     tlsCipherSuites(),
+			CurvePreferences: tlsCurvePreferences(),
 		},
 		DisableCompression: true,
 	}
@@ -152,6 +152,9 @@ func NewS3Config(urlStr string, aliasCfg *aliasConfigV10) *Config {
 		s3Config.SecretKey = aliasCfg.SecretKey
 		s3Config.SessionToken = aliasCfg.SessionToken
 		s3Config.Signature = aliasCfg.API
+		s3Config.Socket = aliasCfg.Socket
+		s3Config.RelaxedBucketNames = strings.EqualFold(aliasCfg.StrictAWSNames, "off")
+		s3Config.ListAPI = aliasCfg.ListAPI
 	}
 	s3Config.Lookup = getLookupType(aliasCfg.Path)
 	return s3Config
@@ -446,6 +449,13 @@ func getAliasAndBucket(ctx *cli.Context) (string, string) {
 	return url2Alias(aliasedURL)
 }
 
+// checkMD5Flag refuses --md5 in FIPS mode, MD5 is not a FIPS approved algorithm.
+func checkMD5Flag(ctx *cli.Context, args ...string) {
+	if globalFIPS && ctx.Bool("md5") {
+		fatalIf(errInvalidArgument().Trace(args...), "Unable to use --md5 in FIPS mode, MD5 is not a FIPS approved algorithm.")
+	}
+}
+
 func getClient(aliasURL string) *madmin.AdminClient {
 	client, err := newAdminClient(aliasURL)
 	fatalIf(err, "Unable to initialize admin connection.")
@@ -462,7 +472,9 @@ func httpClient(timeout time.Duration) *http.Client {
 				// Can't use SSLv3 because of POODLE and BEAST
 				// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 				// Can't use TLSv1.1 because of RC4 cipher usage
-				MinVersion: tls.VersionTLS12,
+				MinVersion:       tls.VersionTLS12,
+				CipherSuites:     tlsCipherSuites(),
+				CurvePreferences: tlsCurvePreferences(),
 			},
 		},
 	}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package limiter caps the rate of data transfers with a token
+// bucket shared by every reader and round-tripper wrapped with the
+// same Limiter.
+package limiter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket refilled at a fixed number of bytes per
+// second, holding at most one second worth of tokens. A nil Limiter
+// does not limit anything.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// New returns a Limiter allowing bytesPerSec bytes per second, or nil
+// if bytesPerSec is zero.
+func New(bytesPerSec uint64) *Limiter {
+	if bytesPerSec == 0 {
+		return nil
+	}
+	return &Limiter{
+		rate:   float64(bytesPerSec),
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+// burst is the largest chunk consumed at once.
+func (l *Limiter) burst() int {
+	return int(l.rate)
+}
+
+// WaitN takes n tokens from the bucket, blocking until they are
+// available or ctx is done.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	// Reserve the tokens upfront, callers queue up behind the debt.
+	l.tokens -= float64(n)
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reader limits the rate data is read from the source.
+type reader struct {
+	ctx     context.Context
+	source  io.Reader
+	limiter *Limiter
+}
+
+// Read implements io.Reader. Reads at most one burst from the source
+// and waits for the read bytes to be allowed by the limiter.
+func (r *reader) Read(b []byte) (n int, err error) {
+	if burst := r.limiter.burst(); len(b) > burst {
+		b = b[:burst]
+	}
+	n, err = r.source.Read(b)
+	if werr := r.limiter.WaitN(r.ctx, n); werr != nil {
+		return n, werr
+	}
+	return n, err
+}
+
+// NewReader returns a io.Reader reading from source no faster than
+// the limiter allows.
+func NewReader(ctx context.Context, source io.Reader, limiter *Limiter) io.Reader {
+	if limiter == nil {
+		return source
+	}
+	return &reader{ctx, source, limiter}
+}
+
+// readCloser is a limited reader keeping the Close of its source.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// transport limits request bodies with the upload limiter and
+// response bodies with the download limiter.
+type transport struct {
+	base     http.RoundTripper
+	upload   *Limiter
+	download *Limiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.upload != nil && req.Body != nil && req.Body != http.NoBody {
+		r := req.Clone(req.Context())
+		r.Body = readCloser{NewReader(req.Context(), req.Body, t.upload), req.Body}
+		req = r
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.download != nil && resp.Body != nil {
+		resp.Body = readCloser{NewReader(req.Context(), resp.Body, t.download), resp.Body}
+	}
+	return resp, nil
+}
+
+// NewTransport returns a http.RoundTripper limiting the uploads and
+// downloads going through base.
+func NewTransport(base http.RoundTripper, upload, download *Limiter) http.RoundTripper {
+	if upload == nil && download == nil {
+		return base
+	}
+	return &transport{base, upload, download}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package limiter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (s *MySuite) TestUnlimited(c *C) {
+	source := bytes.NewReader([]byte("hello"))
+	c.Assert(NewReader(context.Background(), source, New(0)), Equals, io.Reader(source))
+}
+
+func (s *MySuite) TestReader(c *C) {
+	data := bytes.Repeat([]byte("a"), 3000)
+	start := time.Now()
+	// The initial burst covers the first 1000 bytes.
+	r := NewReader(context.Background(), bytes.NewReader(data), New(1000))
+	b, e := ioutil.ReadAll(r)
+	c.Assert(e, IsNil)
+	c.Assert(b, DeepEquals, data)
+	c.Assert(time.Since(start) >= 1900*time.Millisecond, Equals, true)
+}
+
+func (s *MySuite) TestCancel(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := NewReader(ctx, bytes.NewReader(make([]byte, 20)), New(10))
+	_, e := ioutil.ReadAll(r)
+	c.Assert(e, Equals, context.Canceled)
+}